@@ -14,4 +14,4 @@
 
 module gitlab.com/tymonx/go-logger
 
-go 1.14
+go 1.20
@@ -0,0 +1,149 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// These constants define default values for local IP address.
+const (
+	DefaultAddress    = "127.0.0.1"
+	DefaultAddressTTL = time.Minute
+)
+
+var gAddress = addressCache{ttl: DefaultAddressTTL} // nolint:gochecknoglobals
+
+// addressCache caches local IP address set in log records. Expired address is
+// refreshed in background and the cached one is used until then.
+type addressCache struct {
+	address    string
+	expires    time.Time
+	ttl        time.Duration
+	refreshing bool
+	mutex      sync.RWMutex
+}
+
+// SetAddressTTL sets time to live of cached local IP address set in log
+// records. Expired address is resolved again in background. Zero or negative
+// duration sets it to DefaultAddressTTL.
+func SetAddressTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultAddressTTL
+	}
+
+	gAddress.mutex.Lock()
+	defer gAddress.mutex.Unlock()
+
+	gAddress.ttl = ttl
+	gAddress.expires = time.Time{}
+}
+
+// GetAddressTTL returns time to live of cached local IP address.
+func GetAddressTTL() time.Duration {
+	gAddress.mutex.RLock()
+	defer gAddress.mutex.RUnlock()
+
+	return gAddress.ttl
+}
+
+// get returns cached local IP address. The first call resolves it
+// synchronously and it returns an error if it cannot be resolved. Expired
+// address is resolved again in background.
+func (c *addressCache) get() (string, error) {
+	c.mutex.RLock()
+	address, expires, refreshing := c.address, c.expires, c.refreshing
+	c.mutex.RUnlock()
+
+	if address == "" {
+		return c.refresh()
+	}
+
+	if !refreshing && time.Now().After(expires) {
+		c.mutex.Lock()
+
+		if !c.refreshing {
+			c.refreshing = true
+
+			go func() {
+				if _, err := c.refresh(); err != nil {
+					printError(NewRuntimeError("cannot get local IP address", err))
+				}
+			}()
+		}
+
+		c.mutex.Unlock()
+	}
+
+	return address, nil
+}
+
+// refresh resolves local IP address and it caches it.
+func (c *addressCache) refresh() (string, error) {
+	address, err := resolveAddress()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.address = address
+	c.expires = time.Now().Add(c.ttl)
+	c.refreshing = false
+
+	return address, err
+}
+
+// resolveAddress returns the first non-loopback IP address of local network
+// interfaces, IPv4 addresses are preferred. Without them it fallbacks to local
+// address of UDP connection to primary Google DNS that doesn't send any
+// packets.
+func resolveAddress() (string, error) {
+	if addresses, err := net.InterfaceAddrs(); err == nil {
+		var found net.IP
+
+		for _, address := range addresses {
+			if network, ok := address.(*net.IPNet); ok && network.IP.IsGlobalUnicast() {
+				if network.IP.To4() != nil {
+					return network.IP.String(), nil
+				}
+
+				if found == nil {
+					found = network.IP
+				}
+			}
+		}
+
+		if found != nil {
+			return found.String(), nil
+		}
+	}
+
+	connection, err := net.Dial("udp", "8.8.8.8:80")
+
+	if err != nil {
+		return DefaultAddress, NewRuntimeError("cannot connect to primary Google DNS", err)
+	}
+
+	defer func() {
+		err := connection.Close()
+
+		if err != nil {
+			printError(NewRuntimeError("cannot close UDP connection", err))
+		}
+	}()
+
+	return connection.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
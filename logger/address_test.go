@@ -0,0 +1,65 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestSetAddressTTL(test *testing.T) {
+	defer logger.SetAddressTTL(logger.DefaultAddressTTL)
+
+	logger.SetAddressTTL(time.Second)
+
+	if ttl := logger.GetAddressTTL(); ttl != time.Second {
+		test.Errorf("GetAddressTTL() = %v; want %v", ttl, time.Second)
+	}
+
+	logger.SetAddressTTL(0)
+
+	if ttl := logger.GetAddressTTL(); ttl != logger.DefaultAddressTTL {
+		test.Errorf("GetAddressTTL() = %v; want %v", ttl, logger.DefaultAddressTTL)
+	}
+}
+
+func TestLoggerSetAddress(test *testing.T) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{address}")
+
+	log := logger.New().SetSynchronous(true).SetHandler("buffer", buffer).SetAddress("10.0.0.1")
+
+	log.Info(testMessage)
+
+	if want := "10.0.0.1\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+
+	cached := log.SetAddress("").GetAddress()
+
+	if cached == "" {
+		test.Fatal("GetAddress() returns empty address")
+	}
+
+	buffer.Reset()
+	log.Info(testMessage)
+	log.Info(testMessage)
+
+	if want := cached + "\n" + cached + "\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
@@ -14,5 +14,82 @@
 
 package logger
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // Arguments defines log arguments.
 type Arguments []interface{}
+
+// MarshalJSON packs log arguments to JSON. Errors that don't implement
+// the json.Marshaler interface are packed as their error messages.
+func (a Arguments) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("null"), nil
+	}
+
+	values := make([]interface{}, len(a))
+
+	for index, argument := range a {
+		values[index] = argument
+
+		if err, ok := argument.(error); ok {
+			if _, ok := argument.(json.Marshaler); !ok {
+				values[index] = err.Error()
+			}
+		}
+	}
+
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON unpacks log arguments from JSON. Integer numbers are unpacked
+// as int64 and other numbers as float64 values. JSON objects are unpacked as
+// map[string]interface{} values and they work with named and object
+// placeholders.
+func (a *Arguments) UnmarshalJSON(data []byte) error {
+	var values []interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&values); err != nil {
+		return err
+	}
+
+	for index, value := range values {
+		values[index] = normalizeJSON(value)
+	}
+
+	*a = values
+
+	return nil
+}
+
+// normalizeJSON converts JSON numbers in unpacked JSON value to int64 or
+// float64 values.
+func normalizeJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if number, err := v.Int64(); err == nil {
+			return number
+		}
+
+		if number, err := v.Float64(); err == nil {
+			return number
+		}
+
+		return v.String()
+	case []interface{}:
+		for index, element := range v {
+			v[index] = normalizeJSON(element)
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = normalizeJSON(element)
+		}
+	}
+
+	return value
+}
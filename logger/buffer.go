@@ -18,6 +18,12 @@ import (
 	"bytes"
 )
 
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandler("buffer", func() Handler {
+		return NewBuffer()
+	})
+}
+
 // A Buffer represents a log handler object for logging messages using buffer
 // object.
 type Buffer struct {
@@ -42,6 +48,19 @@ func (b *Buffer) SetStreamHandler(handler StreamHandler) *Buffer {
 	return b
 }
 
+// SetEncoder sets encoder used to encode log records instead of stream handler
+// and formatter.
+func (b *Buffer) SetEncoder(encoder Encoder) *Buffer {
+	b.stream.SetEncoder(encoder)
+	return b
+}
+
+// SetLineEnding sets line ending written after every log record.
+func (b *Buffer) SetLineEnding(lineEnding string) *Buffer {
+	b.stream.SetLineEnding(lineEnding)
+	return b
+}
+
 // GetBuffer returns internal buffer object.
 func (b *Buffer) GetBuffer() *bytes.Buffer {
 	b.stream.RLock()
@@ -84,6 +103,17 @@ func (b *Buffer) Reset() {
 	b.buffer.Reset()
 }
 
+// SetHandlerName sets log handler name.
+func (b *Buffer) SetHandlerName(name string) Handler {
+	b.stream.SetHandlerName(name)
+	return b
+}
+
+// GetHandlerName returns log handler name.
+func (b *Buffer) GetHandlerName() string {
+	return b.stream.GetHandlerName()
+}
+
 // Enable enables log handler.
 func (b *Buffer) Enable() Handler {
 	return b.stream.Enable()
@@ -149,6 +179,12 @@ func (b *Buffer) Emit(record *Record) error {
 	return b.stream.Emit(record)
 }
 
+// Profile returns time spent on formatting and writing of log records like
+// the Stream Profile method.
+func (b *Buffer) Profile() Profile {
+	return b.stream.Profile()
+}
+
 // Close closes buffer.
 func (b *Buffer) Close() error {
 	return b.stream.Close()
@@ -0,0 +1,210 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func newCallerLogger() (*logger.Logger, *logger.Buffer) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{file}:{line}:{function}()")
+
+	return logger.New().SetHandler("buffer", buffer).SetSynchronous(true), buffer
+}
+
+func wrapOnce(log *logger.Logger) {
+	log.Info(testMessage)
+}
+
+func wrapTwice(log *logger.Logger) {
+	wrapOnce(log)
+}
+
+func helperOnce(log *logger.Logger) {
+	log.Helper()
+	log.Info(testMessage)
+}
+
+func helperTwice(log *logger.Logger) {
+	log.Helper()
+	helperOnce(log)
+}
+
+func TestLoggerCallerSkip(test *testing.T) {
+	want := "caller_test.go:55:logger_test.TestLoggerCallerSkip()\n" +
+		"caller_test.go:56:logger_test.TestLoggerCallerSkip()\n" +
+		"caller_test.go:57:logger_test.TestLoggerCallerSkip()\n"
+
+	log, buffer := newCallerLogger()
+
+	wrapOnce(log.WithCallerSkip(1))
+	wrapTwice(log.WithCallerSkip(2))
+	wrapOnce(log.SetCallerSkip(1))
+
+	if buffer.String() != want {
+		test.Error("buffer.String() =", buffer.String(), "; want", want)
+	}
+
+	if log.GetCallerSkip() != 1 {
+		test.Errorf("GetCallerSkip() = %d; want 1", log.GetCallerSkip())
+	}
+}
+
+func TestLoggerHelper(test *testing.T) {
+	want := "caller_test.go:74:logger_test.TestLoggerHelper()\n" +
+		"caller_test.go:75:logger_test.TestLoggerHelper()\n"
+
+	log, buffer := newCallerLogger()
+
+	helperOnce(log)
+	helperTwice(log)
+
+	if buffer.String() != want {
+		test.Error("buffer.String() =", buffer.String(), "; want", want)
+	}
+}
+
+func TestLoggerCaptureCaller(test *testing.T) {
+	log, buffer := newCallerLogger()
+
+	log.SetCaptureCaller(false).Info(testMessage)
+
+	if want := ":0:()\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+
+	if log.IsCallerCaptured() {
+		test.Error("IsCallerCaptured() = true; want false")
+	}
+
+	buffer.Reset()
+	log.SetCaptureCaller(true).Info(testMessage)
+
+	if buffer.String() == ":0:()\n" {
+		test.Error("source location is not captured")
+	}
+}
+
+func TestLoggerCaptureCallerFiltered(test *testing.T) {
+	var captured logger.Source
+
+	log, _ := newCallerLogger()
+
+	log.SetLevel(logger.ErrorLevel).AddHook("capture", func(record *logger.Record) error {
+		captured = record.File
+		return nil
+	})
+
+	log.Info(testMessage)
+
+	if captured != (logger.Source{}) {
+		test.Error("source location is captured for filtered log record", captured)
+	}
+
+	log.Error(testMessage)
+
+	if captured.Line == 0 {
+		test.Error("source location is not captured for emitted log record")
+	}
+}
+
+func BenchmarkLoggerCaptureCaller(benchmark *testing.B) {
+	for _, capture := range []bool{true, false} {
+		capture := capture
+
+		name := "enabled"
+
+		if !capture {
+			name = "disabled"
+		}
+
+		benchmark.Run(name, func(benchmark *testing.B) {
+			buffer := logger.NewBuffer()
+			buffer.GetFormatter().SetFormat("{line}")
+
+			log := logger.New().SetHandler("buffer", buffer).SetCaptureCaller(capture)
+
+			benchmark.ResetTimer()
+
+			for index := 0; index < benchmark.N; index++ {
+				log.Info(testMessage)
+			}
+
+			benchmark.StopTimer()
+			log.Flush()
+		})
+	}
+}
+
+func logMessageWrapper(log *logger.Logger) {
+	log.LogMessage(logger.InfoLevel, logger.InfoName, testMessage)
+}
+
+func TestLoggerLogMessageCaller(test *testing.T) {
+	want := "caller_test.go:165:logger_test.TestLoggerLogMessageCaller()\n" +
+		"caller_test.go:166:logger_test.TestLoggerLogMessageCaller()\n" +
+		"caller_test.go:167:logger_test.TestLoggerLogMessageCaller()\n"
+
+	log, buffer := newCallerLogger()
+
+	logMessageWrapper(log)
+	log.Log(logger.InfoLevel, logger.InfoName, testMessage)
+	log.Info(testMessage)
+
+	if buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func logBaseInner(log *logger.Logger) {
+	log.LogBase(2, logger.InfoLevel, logger.InfoName, testMessage)
+}
+
+func logBaseOuter(log *logger.Logger) {
+	logBaseInner(log)
+}
+
+func TestLoggerLogBase(test *testing.T) {
+	want := "caller_test.go:188:logger_test.TestLoggerLogBase()\n" +
+		"caller_test.go:189:logger_test.TestLoggerLogBase()\n"
+
+	log, buffer := newCallerLogger()
+
+	logBaseOuter(log)
+	log.LogBase(0, logger.InfoLevel, logger.InfoName, testMessage)
+
+	if buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestLoggerMeasureCaller(test *testing.T) {
+	want := "caller_test.go:202:logger_test.TestLoggerMeasureCaller()\n" +
+		"caller_test.go:202:logger_test.TestLoggerMeasureCaller()\n"
+
+	log, buffer := newCallerLogger()
+
+	done := log.Measure(logger.InfoLevel, testMessage)
+
+	done()
+	done()
+
+	if buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
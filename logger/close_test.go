@@ -0,0 +1,229 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestCloseErrors(test *testing.T) {
+	errs := []error{Error("first"), Error("second")}
+	handlers := logger.Handlers{}
+
+	for index, err := range errs {
+		stream := logger.NewStream()
+
+		if err := stream.SetWriteCloser(failingCloser{err: err}); err != nil {
+			test.Fatal("SetWriteCloser() returns an unexpected error", err)
+		}
+
+		handlers[string(rune('a'+index))] = stream
+	}
+
+	log := logger.New().SetHandlers(handlers).SetInternalErrorHandler(func(error) {})
+
+	err := log.Close()
+
+	for _, want := range errs {
+		if !errors.Is(err, want) {
+			test.Error("errors.Is() = false; want", want)
+		}
+	}
+}
+
+func newBlockedLogger(log *logger.Logger) (*logger.Logger, func()) {
+	started := make(chan struct{})
+	released := make(chan struct{})
+
+	log.SetHandler("observer", logger.NewObserver()).SetWorker(logger.NewWorker())
+
+	log.AddHook("block", func(*logger.Record) error {
+		select {
+		case <-released:
+		case started <- struct{}{}:
+			<-released
+		}
+
+		return nil
+	})
+
+	log.Info(testMessage)
+	<-started
+
+	return log, func() { close(released) }
+}
+
+func TestFlushTimeout(test *testing.T) {
+	log, release := newBlockedLogger(logger.New())
+
+	log.Info(testMessage)
+
+	if err := log.FlushTimeout(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		test.Error("FlushTimeout() =", err, "; want", context.DeadlineExceeded)
+	}
+
+	release()
+
+	if err := log.FlushTimeout(time.Minute); err != nil {
+		test.Error("FlushTimeout() returns an unexpected error", err)
+	}
+
+	if observer, _ := log.GetHandler("observer"); observer.(*logger.Observer).Len() != 2 {
+		test.Error("queued log messages are not emitted after timeout")
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestFlushContext(test *testing.T) {
+	log, release := newBlockedLogger(logger.New())
+
+	log.Info(testMessage)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if err := log.FlushContext(ctx); !errors.Is(err, context.Canceled) {
+		test.Error("FlushContext() =", err, "; want", context.Canceled)
+	}
+
+	release()
+
+	if err := log.FlushContext(context.Background()); err != nil {
+		test.Error("FlushContext() returns an unexpected error", err)
+	}
+
+	if observer, _ := log.GetHandler("observer"); observer.(*logger.Observer).Len() != 2 {
+		test.Error("queued log messages are not emitted after cancellation")
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestCloseTimeout(test *testing.T) {
+	log, release := newBlockedLogger(logger.New())
+
+	if err := log.CloseTimeout(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		test.Error("CloseTimeout() =", err, "; want", context.DeadlineExceeded)
+	}
+
+	release()
+
+	if err := log.CloseTimeout(time.Minute); err != nil {
+		test.Error("CloseTimeout() returns an unexpected error", err)
+	}
+
+	if log.GetWorker().IsRunning() {
+		test.Error("GetWorker().IsRunning() = true; want false")
+	}
+}
+
+func TestShutdown(test *testing.T) {
+	log, release := newBlockedLogger(logger.New())
+
+	defer release()
+
+	log.Info(testMessage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := log.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		test.Error("Shutdown() =", err, "; want", context.DeadlineExceeded)
+	}
+
+	if !log.IsShutdown() {
+		test.Error("IsShutdown() = false; want true")
+	}
+
+	if err := log.Shutdown(context.Background()); err != nil {
+		test.Error("Shutdown() returns an unexpected error", err)
+	}
+}
+
+func TestShutdownErrors(test *testing.T) {
+	closeErr := Error("close")
+	stream := logger.NewStream()
+
+	if err := stream.SetWriteCloser(failingCloser{err: closeErr}); err != nil {
+		test.Fatal("SetWriteCloser() returns an unexpected error", err)
+	}
+
+	log := logger.New().SetHandler("stream", stream).SetInternalErrorHandler(func(error) {})
+
+	if err := log.Shutdown(context.Background()); !errors.Is(err, closeErr) {
+		test.Error("Shutdown() =", err, "; want", closeErr)
+	}
+}
+
+func TestShutdownDiscards(test *testing.T) {
+	observer := logger.NewObserver()
+	log := logger.New().SetHandler("observer", observer).SetWorker(logger.NewWorker())
+
+	log.Info(testMessage)
+
+	if err := log.Shutdown(context.Background()); err != nil {
+		test.Fatal("Shutdown() returns an unexpected error", err)
+	}
+
+	if log.GetWorker().IsRunning() {
+		test.Error("GetWorker().IsRunning() = true; want false")
+	}
+
+	log.Info(testMessage)
+	log.Flush()
+
+	if length := observer.Len(); length != 1 {
+		test.Errorf("Len() = %d; want 1", length)
+	}
+
+	if log.Reset().IsShutdown() {
+		test.Error("IsShutdown() = true after Reset(); want false")
+	}
+}
+
+func TestGlobalCloseErrors(test *testing.T) {
+	var reported []error
+
+	stream := logger.NewStream()
+
+	if err := stream.SetWriteCloser(failingCloser{err: testError}); err != nil {
+		test.Fatal("SetWriteCloser() returns an unexpected error", err)
+	}
+
+	logger.SetInternalErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+
+	defer logger.SetInternalErrorHandler(nil)
+	defer logger.Reset()
+
+	logger.SetHandler("failing", stream)
+	logger.Close()
+
+	if (len(reported) == 0) || !errors.Is(reported[len(reported)-1], testError) {
+		test.Errorf("reported = %v; want %v", reported, testError)
+	}
+}
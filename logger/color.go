@@ -0,0 +1,93 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// These constants define ANSI escape sequences used by the levelColor and
+// colorReset template functions.
+const (
+	ColorRed    = "\x1b[31m"
+	ColorGreen  = "\x1b[32m"
+	ColorYellow = "\x1b[33m"
+	ColorCyan   = "\x1b[36m"
+	ColorReset  = "\x1b[0m"
+)
+
+// IsColorSupported returns true if provided writer is a terminal that supports
+// ANSI colors. It returns false if the NO_COLOR environment variable is set to
+// a non-empty value, if the TERM environment variable is set to "dumb" or if
+// provided writer is not a terminal, like for example a file, a pipe or
+// a buffer.
+func IsColorSupported(writer io.Writer) bool {
+	if (os.Getenv("NO_COLOR") != "") || (os.Getenv("TERM") == "dumb") {
+		return false
+	}
+
+	file, ok := writer.(*os.File)
+
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return false
+	}
+
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
+
+// getLevelColor returns ANSI escape sequence of color for provided log level
+// value based on its severity.
+func getLevelColor(level int) string {
+	switch severity := GetLevelSeverity(level); {
+	case severity <= ErrorSeverity:
+		return ColorRed
+	case severity == WarningSeverity:
+		return ColorYellow
+	case severity == DebugSeverity:
+		return ColorCyan
+	default:
+		return ColorGreen
+	}
+}
+
+// toTitle returns provided string in lower case with the first letter of every
+// word in upper case. Words are separated by any character other than letters,
+// digits and underscore like in the deprecated strings.Title function.
+func toTitle(text string) string {
+	separator := true
+
+	return strings.Map(func(r rune) rune {
+		isSeparator := !unicode.IsLetter(r) && !unicode.IsDigit(r) && (r != '_')
+
+		if separator && !isSeparator {
+			r = unicode.ToUpper(r)
+		} else {
+			r = unicode.ToLower(r)
+		}
+
+		separator = isSeparator
+
+		return r
+	}, text)
+}
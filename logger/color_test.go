@@ -0,0 +1,85 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestIsColorSupported(test *testing.T) {
+	file, err := os.Create(filepath.Join(test.TempDir(), "color.log"))
+
+	if err != nil {
+		test.Fatal("Create() returns an unexpected error", err)
+	}
+
+	for _, writer := range []io.Writer{new(bytes.Buffer), file} {
+		if logger.IsColorSupported(writer) {
+			test.Errorf("IsColorSupported(%T) = true; want false", writer)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+
+	test.Setenv("NO_COLOR", "1")
+
+	if logger.IsColorSupported(os.Stdout) {
+		test.Error("IsColorSupported() = true with NO_COLOR; want false")
+	}
+
+	if logger.NewStdout().GetFormatter().IsColors() {
+		test.Error("IsColors() = true with NO_COLOR; want false")
+	}
+}
+
+func TestFormatterColors(test *testing.T) {
+	formatter := logger.NewFormatter().SetFormat("{levelColor}{Level}{colorReset}")
+
+	record := &logger.Record{Level: logger.Level{Value: logger.ErrorLevel, Name: "error"}}
+
+	for _, tt := range []struct {
+		colors bool
+		want   string
+	}{
+		{false, "Error"},
+		{true, logger.ColorRed + "Error" + logger.ColorReset},
+	} {
+		message, err := formatter.SetColors(tt.colors).Format(record)
+
+		if err != nil {
+			test.Error("Format() returns an unexpected error", err)
+		}
+
+		if message != tt.want {
+			test.Errorf("Format() with colors %t = %q; want %q", tt.colors, message, tt.want)
+		}
+	}
+
+	record.Level = logger.Level{Value: logger.WarningLevel, Name: "CUSTOM level"}
+
+	if message, err := formatter.SetColors(true).Format(record); err != nil {
+		test.Error("Format() returns an unexpected error", err)
+	} else if want := logger.ColorYellow + "Custom Level" + logger.ColorReset; message != want {
+		test.Errorf("Format() = %q; want %q", message, want)
+	}
+}
@@ -24,3 +24,27 @@ type Error string
 func (e Error) Error() string {
 	return string(e)
 }
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, testError
+}
+
+type discardWriter struct{}
+
+func (discardWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+type failingCloser struct {
+	err error
+}
+
+func (failingCloser) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (f failingCloser) Close() error {
+	return f.err
+}
@@ -0,0 +1,412 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+
+	"gitlab.com/tymonx/go-logger/logger/yaml"
+)
+
+// Config defines declarative logger configuration, like for example loaded
+// from a JSON document by the LoadConfig function. Log levels are log level
+// names or decimal log level values accepted by the ParseLevel function.
+//
+// Level sets minimum log level of all log handlers and Format sets default
+// format of all log handlers. Log handlers are replaced only if Handlers are
+// provided. They are created using log handler factories registered by the
+// RegisterHandler or RegisterHandlerFactory functions.
+type Config struct {
+	Level    string                   `json:"level"`
+	Format   string                   `json:"format"`
+	Handlers map[string]HandlerConfig `json:"handlers"`
+}
+
+// HandlerConfig defines declarative configuration of log handler. Type is
+// the name of registered log handler factory, on default it is log handler
+// identifier name. Level and MaximumLevel set log level range of log handler.
+// Options are passed to log handler factory, like for example "bufferSize" or
+// "locking" of the File log handler. Name is a shortcut for the "name" option
+// of the File log handler, Address, Network and Port are shortcuts for
+// options of the Syslog log handler.
+type HandlerConfig struct {
+	Type         string         `json:"type"`
+	Level        string         `json:"level"`
+	MaximumLevel string         `json:"maximumLevel"`
+	Format       string         `json:"format"`
+	DateFormat   string         `json:"dateFormat"`
+	Disabled     bool           `json:"disabled"`
+	Name         string         `json:"name"`
+	Address      string         `json:"address"`
+	Network      string         `json:"network"`
+	Port         int            `json:"port"`
+	Options      HandlerOptions `json:"options"`
+}
+
+// configLevel defines parsed optional log level from configuration.
+type configLevel struct {
+	value int
+	isSet bool
+}
+
+// configHandler defines log handler created from configuration with its parsed
+// log levels.
+type configHandler struct {
+	handler      Handler
+	config       HandlerConfig
+	minimumLevel configLevel
+	maximumLevel configLevel
+}
+
+// LoadConfig creates a new logger configured from provided JSON document. It
+// returns an error naming the offending key if configuration is invalid.
+//
+//	log, err := logger.LoadConfig(strings.NewReader(`{
+//	    "level": "info",
+//	    "handlers": {
+//	        "file": {"type": "file", "name": "/var/log/app.log", "format": "{message}"},
+//	        "syslog": {"type": "syslog", "address": "logs", "network": "udp"}
+//	    }
+//	}`))
+func LoadConfig(reader io.Reader) (*Logger, error) {
+	l := New()
+
+	if err := l.ApplyConfig(reader); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// LoadConfigYAML creates a new logger configured from provided YAML document
+// like the LoadConfig function. Environment variables in string values are
+// expanded, like for example ${LOG_DIR} in "${LOG_DIR}/app.log".
+//
+//	log, err := logger.LoadConfigYAML(strings.NewReader(`
+//	level: info
+//	handlers:
+//	  file:
+//	    type: file
+//	    name: ${LOG_DIR}/app.log
+//	    format: "{message}"
+//	`))
+func LoadConfigYAML(reader io.Reader) (*Logger, error) {
+	l := New()
+
+	if err := l.ApplyConfigYAML(reader); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// ApplyConfig configures logger from provided JSON document like the
+// LoadConfig function. Logger is left intact if configuration is invalid.
+func (l *Logger) ApplyConfig(reader io.Reader) error {
+	config, err := decodeConfig(reader)
+
+	if err != nil {
+		return err
+	}
+
+	return l.applyConfig(config)
+}
+
+// ApplyConfigYAML configures logger from provided YAML document like the
+// LoadConfigYAML function. Logger is left intact if configuration is invalid.
+func (l *Logger) ApplyConfigYAML(reader io.Reader) error {
+	config, err := decodeConfigYAML(reader)
+
+	if err != nil {
+		return err
+	}
+
+	return l.applyConfig(config)
+}
+
+// decodeConfig decodes configuration from provided JSON document. Unknown keys
+// are reported as errors.
+func decodeConfig(reader io.Reader) (*Config, error) {
+	var config Config
+
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&config); err != nil {
+		return nil, NewRuntimeError("cannot decode config", err)
+	}
+
+	return &config, nil
+}
+
+// decodeConfigYAML decodes configuration from provided YAML document with
+// expanded environment variables. Unknown keys are reported as errors.
+func decodeConfigYAML(reader io.Reader) (*Config, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot read config", err)
+	}
+
+	var document interface{}
+
+	if err = yaml.Unmarshal(data, &document); err != nil {
+		return nil, NewRuntimeError("cannot decode config", err)
+	}
+
+	if data, err = json.Marshal(expandConfigEnv(document)); err != nil {
+		return nil, NewRuntimeError("cannot decode config", err)
+	}
+
+	return decodeConfig(bytes.NewReader(data))
+}
+
+// applyConfig configures logger from provided configuration. Logger is
+// modified only after successful creation of all log handlers.
+func (l *Logger) applyConfig(config *Config) error {
+	_, err := l.reloadConfig(config, nil)
+	return err
+}
+
+// reloadConfig configures logger from provided configuration like the
+// applyConfig method. Previously created log handlers with the same type and
+// options are reused instead of creating new ones. It returns log handlers
+// set from configuration or nil if configuration doesn't provide them.
+func (l *Logger) reloadConfig(config *Config, previous map[string]*configHandler) (map[string]*configHandler, error) {
+	level, err := parseConfigLevel("level", config.Level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var handlers map[string]*configHandler
+
+	if config.Handlers != nil {
+		if handlers, err = createConfigHandlers(config.Handlers, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Format != "" {
+		l.SetDefaultFormat(config.Format)
+
+		if handlers == nil {
+			l.SetFormat(config.Format)
+		}
+	}
+
+	if handlers != nil {
+		added := make(Handlers, len(handlers))
+
+		for name, created := range handlers {
+			added[name] = created.handler
+		}
+
+		l.SetHandlers(added)
+	}
+
+	if level.isSet {
+		l.SetMinimumLevel(level.value)
+	}
+
+	for _, created := range handlers {
+		created.apply()
+	}
+
+	return handlers, nil
+}
+
+// createConfigHandlers creates log handlers from provided configuration.
+// Previous log handlers with unchanged type and options are reused. Already
+// created log handlers are closed on error.
+func createConfigHandlers(configs map[string]HandlerConfig, previous map[string]*configHandler) (map[string]*configHandler, error) {
+	names := make([]string, 0, len(configs))
+
+	for name := range configs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	handlers := make(map[string]*configHandler, len(configs))
+
+	for _, name := range names {
+		created, err := createConfigHandler(name, configs[name], previous[name])
+
+		if err != nil {
+			closeConfigHandlers(handlers, previous)
+			return nil, err
+		}
+
+		handlers[name] = created
+	}
+
+	return handlers, nil
+}
+
+// createConfigHandler creates log handler from provided configuration. Previous
+// log handler is reused if its type and options are unchanged.
+func createConfigHandler(name string, config HandlerConfig, previous *configHandler) (*configHandler, error) {
+	key := "handlers." + name
+
+	created := &configHandler{
+		config: config,
+	}
+
+	var err error
+
+	if created.minimumLevel, err = parseConfigLevel(key+".level", config.Level); err != nil {
+		return nil, err
+	}
+
+	if created.maximumLevel, err = parseConfigLevel(key+".maximumLevel", config.MaximumLevel); err != nil {
+		return nil, err
+	}
+
+	if (previous != nil) && previous.isReusable(name, config) {
+		created.handler = previous.handler
+		return created, nil
+	}
+
+	if created.handler, err = CreateHandlerWithOptions(config.getType(name), config.getOptions()); err != nil {
+		return nil, NewRuntimeError("cannot apply config key {p | printf \"%q\"}", key, err)
+	}
+
+	return created, nil
+}
+
+// closeConfigHandlers closes log handlers that are not reused from previous
+// log handlers. Errors are reported as internal errors.
+func closeConfigHandlers(handlers, previous map[string]*configHandler) {
+	for name, created := range handlers {
+		if reused, ok := previous[name]; ok && (reused.handler == created.handler) {
+			continue
+		}
+
+		if err := created.handler.Close(); err != nil {
+			printError(NewRuntimeError("cannot close log handler {p | printf \"%q\"}", name, err))
+		}
+	}
+}
+
+// getType returns name of log handler factory. On default it is provided log
+// handler identifier name.
+func (c *HandlerConfig) getType(name string) string {
+	if c.Type == "" {
+		return name
+	}
+
+	return c.Type
+}
+
+// getOptions returns log handler options passed to log handler factory. Name,
+// Address, Network and Port override the same options from Options.
+func (c *HandlerConfig) getOptions() HandlerOptions {
+	options := make(HandlerOptions, len(c.Options))
+
+	for key, value := range c.Options {
+		options[key] = value
+	}
+
+	for key, value := range map[string]string{
+		"name":    c.Name,
+		"address": c.Address,
+		"network": c.Network,
+	} {
+		if value != "" {
+			options[key] = value
+		}
+	}
+
+	if c.Port != 0 {
+		options["port"] = c.Port
+	}
+
+	return options
+}
+
+// isReusable returns true if log handler created from configuration can be
+// reused for provided configuration with the same type and options.
+func (c *configHandler) isReusable(name string, config HandlerConfig) bool {
+	return (c.config.getType(name) == config.getType(name)) &&
+		reflect.DeepEqual(c.config.getOptions(), config.getOptions())
+}
+
+// apply sets log levels and formats to log handler. It is called after adding
+// log handler to logger to override logger default format and level.
+func (c *configHandler) apply() {
+	if c.minimumLevel.isSet {
+		c.handler.SetMinimumLevel(c.minimumLevel.value)
+	}
+
+	if c.maximumLevel.isSet {
+		c.handler.SetMaximumLevel(c.maximumLevel.value)
+	}
+
+	if formatter := c.handler.GetFormatter(); formatter != nil {
+		if c.config.Format != "" {
+			formatter.SetFormat(c.config.Format)
+		}
+
+		if c.config.DateFormat != "" {
+			formatter.SetDateFormat(c.config.DateFormat)
+		}
+	}
+
+	if c.config.Disabled {
+		c.handler.Disable()
+	} else {
+		c.handler.Enable()
+	}
+}
+
+// parseConfigLevel parses optional log level from configuration key.
+func parseConfigLevel(key, name string) (configLevel, error) {
+	if name == "" {
+		return configLevel{}, nil
+	}
+
+	value, err := ParseLevel(name)
+
+	if err != nil {
+		return configLevel{}, NewRuntimeError("cannot apply config key {p | printf \"%q\"}", key, err)
+	}
+
+	return configLevel{value: value, isSet: true}, nil
+}
+
+// expandConfigEnv expands environment variables in all string values of
+// provided decoded configuration document.
+func expandConfigEnv(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return os.ExpandEnv(value)
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = expandConfigEnv(item)
+		}
+	case []interface{}:
+		for index, item := range value {
+			value[index] = expandConfigEnv(item)
+		}
+	}
+
+	return value
+}
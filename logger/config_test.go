@@ -0,0 +1,182 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestLoadConfig(test *testing.T) {
+	name := filepath.Join(test.TempDir(), "config.log")
+
+	config := `{
+		"level": "info",
+		"format": "{level}:{message}",
+		"handlers": {
+			"file": {"type": "file", "name": ` + `"` + filepath.ToSlash(name) + `"` + `, "format": "{message}"},
+			"syslog": {"type": "syslog", "address": "logs", "network": "udp", "port": 1514, "disabled": true},
+			"stderr": {"level": "error", "maximumLevel": "alert"}
+		}
+	}`
+
+	log, err := logger.LoadConfig(strings.NewReader(config))
+
+	if err != nil {
+		test.Fatal("LoadConfig() returns an unexpected error", err)
+	}
+
+	handler, err := log.GetHandler("syslog")
+
+	if err != nil {
+		test.Fatal("GetHandler() returns an unexpected error", err)
+	}
+
+	syslog := handler.(*logger.Syslog) // nolint:forcetypeassert
+
+	if (syslog.GetAddress() != "logs") || (syslog.GetNetwork() != "udp") || (syslog.GetPort() != 1514) {
+		test.Errorf("syslog = %s:%d/%s; want logs:1514/udp", syslog.GetAddress(), syslog.GetPort(), syslog.GetNetwork())
+	}
+
+	if syslog.IsEnabled() {
+		test.Error("IsEnabled() = true; want false")
+	}
+
+	if handler, _ = log.GetHandler("stderr"); handler.GetFormatter().GetFormat() != "{level}:{message}" {
+		test.Errorf("GetFormat() = %q; want default format", handler.GetFormatter().GetFormat())
+	}
+
+	if min, max := handler.GetLevelRange(); (min != logger.ErrorLevel) || (max != logger.AlertLevel) {
+		test.Errorf("GetLevelRange() = %d, %d; want %d, %d", min, max, logger.ErrorLevel, logger.AlertLevel)
+	}
+
+	log.Debug("dropped")
+	log.Info("logged")
+
+	if err = log.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	data, err := os.ReadFile(name)
+
+	if err != nil {
+		test.Fatal("ReadFile() returns an unexpected error", err)
+	}
+
+	if string(data) != "logged\n" {
+		test.Errorf("file content = %q; want \"logged\\n\"", data)
+	}
+}
+
+func TestApplyConfigErrors(test *testing.T) {
+	for config, key := range map[string]string{
+		`{"level": "unknown"}`:                                  `"level"`,
+		`{"handlers": {"a": {"type": "unknown"}}}`:              `"handlers.a"`,
+		`{"handlers": {"stdout": {"maximumLevel": "x"}}}`:       `"handlers.stdout.maximumLevel"`,
+		`{"handlers": {"stdout": {"address": "localhost"}}}`:    `"address"`,
+		`{"handlers": {"file": {"type": "file", "port": 514}}}`: `"port"`,
+		`{"levels": "info"}`:                                    `"levels"`,
+	} {
+		buffer := logger.NewBuffer()
+
+		log := logger.New().SetHandler("buffer", buffer)
+
+		err := log.ApplyConfig(strings.NewReader(config))
+
+		if err == nil {
+			test.Errorf("ApplyConfig(%s) doesn't return an error", config)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), key) {
+			test.Errorf("ApplyConfig(%s) = %q; want error with key %s", config, err.Error(), key)
+		}
+
+		if handler, _ := log.GetHandler("buffer"); handler != buffer {
+			test.Errorf("ApplyConfig(%s) changes handlers on error", config)
+		}
+	}
+}
+
+func TestLoadConfigYAML(test *testing.T) {
+	directory := test.TempDir()
+
+	test.Setenv("LOGGER_TEST_LOG_DIR", directory)
+
+	config := `
+# Logger configuration
+level: info
+handlers:
+  file:
+    type: file
+    name: ${LOGGER_TEST_LOG_DIR}/app.log
+    format: "{level}: {message}"
+  stderr:
+    level: error
+    disabled: true
+`
+
+	log, err := logger.LoadConfigYAML(strings.NewReader(config))
+
+	if err != nil {
+		test.Fatal("LoadConfigYAML() returns an unexpected error", err)
+	}
+
+	if handler, _ := log.GetHandler("stderr"); handler.IsEnabled() {
+		test.Error("IsEnabled() = true; want false")
+	}
+
+	log.Debug("dropped")
+	log.Info("logged")
+
+	if err = log.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(directory, "app.log"))
+
+	if err != nil {
+		test.Fatal("ReadFile() returns an unexpected error", err)
+	}
+
+	if string(data) != "info: logged\n" {
+		test.Errorf("file content = %q; want \"info: logged\\n\"", data)
+	}
+}
+
+func TestApplyConfigYAMLErrors(test *testing.T) {
+	for config, want := range map[string]string{
+		"level: info\nhandlers:\n  stdout:\n    lvel: error\n": `"lvel"`,
+		"handlers:\n  a:\n    type: unknown\n":                 `"handlers.a"`,
+		"level: [info]\n":                                      "unsupported feature",
+	} {
+		log := logger.New()
+
+		err := log.ApplyConfigYAML(strings.NewReader(config))
+
+		if err == nil {
+			test.Errorf("ApplyConfigYAML(%q) doesn't return an error", config)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), want) {
+			test.Errorf("ApplyConfigYAML(%q) = %q; want error with %s", config, err.Error(), want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultConfigWatchInterval defines default interval of checking modification
+// time of configuration file watched by the WatchConfig method.
+const DefaultConfigWatchInterval = time.Second
+
+// configWatcher defines watcher of configuration file that reapplies it to
+// logger when configuration file is modified.
+type configWatcher struct {
+	logger   *Logger
+	path     string
+	modTime  time.Time
+	size     int64
+	handlers map[string]*configHandler
+	done     chan struct{}
+	stopped  chan struct{}
+	once     sync.Once
+}
+
+// WatchConfig configures logger from provided configuration file and it
+// reapplies configuration file every time when it is modified, like for
+// example to switch running service to debug log level without restarting it.
+// Configuration files with the .yaml or .yml extension are decoded like with
+// the ApplyConfigYAML method, other configuration files are decoded like with
+// the ApplyConfig method. Modification time of configuration file is checked
+// every DefaultConfigWatchInterval.
+//
+// It returns an error if configuration file cannot be applied for the first
+// time. Later invalid configuration is reported as an internal error and
+// previous configuration stays active. Log level and format changes apply to
+// existing log handlers. Log handlers with changed type or options are created
+// again using registered log handler factories and removed log handlers are
+// closed. Removed log levels and formats are not restored to defaults. Returned
+// stop function stops watching of configuration file.
+//
+//	stop, err := logger.WatchConfig("/etc/app/logger.yaml")
+//
+//	if err != nil {
+//	    return err
+//	}
+//
+//	defer stop()
+func (l *Logger) WatchConfig(path string) (stop func(), err error) {
+	return l.WatchConfigInterval(path, DefaultConfigWatchInterval)
+}
+
+// WatchConfigInterval configures logger from provided configuration file and
+// it reapplies configuration file when it is modified like the WatchConfig
+// method. Modification time of configuration file is checked every provided
+// interval.
+func (l *Logger) WatchConfigInterval(path string, interval time.Duration) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, NewRuntimeError("invalid config watch interval", interval)
+	}
+
+	w := &configWatcher{
+		logger:  l,
+		path:    path,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	if _, err = w.reload(); err != nil {
+		return nil, err
+	}
+
+	go w.run(interval)
+
+	return w.stop, nil
+}
+
+// run checks modification time of configuration file periodically and it
+// reapplies modified configuration file.
+func (w *configWatcher) run(interval time.Duration) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if _, err := w.reload(); err != nil {
+				w.logger.printError(NewRuntimeError("cannot reload config {p | printf \"%q\"}", w.path, err))
+			}
+		}
+	}
+}
+
+// stop stops watching of configuration file. It waits until ongoing reload of
+// configuration file is finished.
+func (w *configWatcher) stop() {
+	w.once.Do(func() {
+		close(w.done)
+		<-w.stopped
+	})
+}
+
+// reload applies configuration file if it was modified since the last check.
+// Invalid configuration file is reported only once until it is modified again.
+// It returns true if configuration file was applied.
+func (w *configWatcher) reload() (bool, error) {
+	info, err := os.Stat(w.path)
+
+	if err != nil {
+		return false, NewRuntimeError("cannot read config", err)
+	}
+
+	if info.ModTime().Equal(w.modTime) && (info.Size() == w.size) {
+		return false, nil
+	}
+
+	w.modTime, w.size = info.ModTime(), info.Size()
+
+	config, err := w.decode()
+
+	if err != nil {
+		return false, err
+	}
+
+	handlers, err := w.logger.reloadConfig(config, w.handlers)
+
+	if err != nil {
+		return false, err
+	}
+
+	if handlers != nil {
+		w.logger.Flush()
+		closeConfigHandlers(w.handlers, handlers)
+		w.handlers = handlers
+	}
+
+	return true, nil
+}
+
+// decode decodes configuration file based on its extension.
+func (w *configWatcher) decode() (*Config, error) {
+	file, err := os.Open(w.path)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot read config", err)
+	}
+
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(w.path)) {
+	case ".yaml", ".yml":
+		return decodeConfigYAML(file)
+	default:
+		return decodeConfig(file)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+type closeCountingBuffer struct {
+	*logger.Buffer
+	closed *int32
+}
+
+func (b closeCountingBuffer) Close() error {
+	atomic.AddInt32(b.closed, 1)
+	return b.Buffer.Close()
+}
+
+func writeWatchedConfig(test *testing.T, path, config string, modTime time.Time) {
+	test.Helper()
+
+	temporary := path + ".tmp"
+
+	if err := os.WriteFile(temporary, []byte(config), 0o600); err != nil {
+		test.Fatal("WriteFile() returns an unexpected error", err)
+	}
+
+	if err := os.Chtimes(temporary, modTime, modTime); err != nil {
+		test.Fatal("Chtimes() returns an unexpected error", err)
+	}
+
+	if err := os.Rename(temporary, path); err != nil {
+		test.Fatal("Rename() returns an unexpected error", err)
+	}
+}
+
+func waitWatchedConfig(test *testing.T, condition func() bool) {
+	test.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); !condition(); {
+		if time.Now().After(deadline) {
+			test.Fatal("configuration file is not reloaded")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchConfig(test *testing.T) {
+	var closed int32
+
+	logger.RegisterHandlerFactory("watch", func(options logger.HandlerOptions) (logger.Handler, error) {
+		return closeCountingBuffer{Buffer: logger.NewBuffer(), closed: &closed}, nil
+	})
+
+	path := filepath.Join(test.TempDir(), "config.json")
+	modTime := time.Now().Add(-time.Hour)
+
+	writeWatchedConfig(test, path, `{
+		"level": "info",
+		"handlers": {"buffer": {"type": "watch", "format": "{message}"}}
+	}`, modTime)
+
+	log := logger.New()
+
+	defer log.Close()
+
+	reported := make(chan error, 16)
+
+	log.SetInternalErrorHandler(func(err error) {
+		reported <- err
+	})
+
+	stop, err := log.WatchConfigInterval(path, time.Millisecond)
+
+	if err != nil {
+		test.Fatal("WatchConfigInterval() returns an unexpected error", err)
+	}
+
+	defer stop()
+
+	handler, err := log.GetHandler("buffer")
+
+	if err != nil {
+		test.Fatal("GetHandler() returns an unexpected error", err)
+	}
+
+	log.Debug("dropped")
+	log.Info("first")
+	log.Flush()
+
+	buffer := handler.(closeCountingBuffer) // nolint:forcetypeassert
+
+	if want := "first\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	writeWatchedConfig(test, path, `{
+		"level": "debug",
+		"handlers": {
+			"buffer": {"type": "watch", "format": "{level}:{message}"},
+			"added": {"type": "watch"}
+		}
+	}`, modTime.Add(time.Second))
+
+	waitWatchedConfig(test, func() bool {
+		_, err := log.GetHandler("added")
+		return err == nil
+	})
+
+	if reloaded, _ := log.GetHandler("buffer"); reloaded != handler {
+		test.Error("log handler with unchanged type and options is created again")
+	}
+
+	log.Debug("second")
+	log.Flush()
+
+	if want := "first\ndebug:second\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	writeWatchedConfig(test, path, `{"level": "invalid"}`, modTime.Add(2*time.Second))
+
+	select {
+	case err = <-reported:
+		if !strings.Contains(err.Error(), "level") {
+			test.Errorf("error = %q; want invalid level error", err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		test.Fatal("invalid configuration file is not reported")
+	}
+
+	log.Debug("third")
+	log.Flush()
+
+	if want := "first\ndebug:second\ndebug:third\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	writeWatchedConfig(test, path, `{
+		"handlers": {"buffer": {"type": "watch", "level": "info", "format": "{message}"}}
+	}`, modTime.Add(3*time.Second))
+
+	waitWatchedConfig(test, func() bool {
+		return atomic.LoadInt32(&closed) != 0
+	})
+
+	if _, err = log.GetHandler("added"); err == nil {
+		test.Error("removed log handler is not removed")
+	}
+
+	if got := atomic.LoadInt32(&closed); got != 1 {
+		test.Errorf("closed = %d; want 1", got)
+	}
+
+	stop()
+
+	writeWatchedConfig(test, path, `{"handlers": {}}`, modTime.Add(4*time.Second))
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err = log.GetHandler("buffer"); err != nil {
+		test.Error("configuration file is reloaded after stop")
+	}
+}
+
+func TestWatchConfigErrors(test *testing.T) {
+	log := logger.New()
+
+	if _, err := log.WatchConfig(filepath.Join(test.TempDir(), "missing.json")); err == nil {
+		test.Error("WatchConfig() doesn't return an error for missing file")
+	}
+
+	path := filepath.Join(test.TempDir(), "config.yaml")
+
+	writeWatchedConfig(test, path, "level: invalid\n", time.Now())
+
+	if _, err := log.WatchConfig(path); err == nil {
+		test.Error("WatchConfig() doesn't return an error for invalid file")
+	}
+
+	if _, err := log.WatchConfigInterval(path, 0); err == nil {
+		test.Error("WatchConfigInterval() doesn't return an error for invalid interval")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("context", func() IDGenerator { return NewContextID(nil, nil) })
+}
+
+// requestIDKey defines context key of request ID set by the
+// ContextWithRequestID function.
+type requestIDKey struct{}
+
+// A ContextID represents ID generator that returns request ID stored in
+// context of log record, like for example request ID propagated from incoming
+// request header. It implements the ContextIDGenerator interface. For log
+// records without context or without request ID in context it falls back to
+// inner ID generator.
+type ContextID struct {
+	key      interface{}
+	fallback IDGenerator
+}
+
+// NewContextID creates a new ContextID object that returns request ID stored
+// in context under provided key. Request ID must be a string or it must
+// implement the fmt.Stringer interface. Nil key means the key used by
+// the ContextWithRequestID function. Nil fallback ID generator means
+// the UUID4 ID generator.
+func NewContextID(key interface{}, fallback IDGenerator) *ContextID {
+	if key == nil {
+		key = requestIDKey{}
+	}
+
+	if fallback == nil {
+		fallback = NewUUID4()
+	}
+
+	return &ContextID{
+		key:      key,
+		fallback: fallback,
+	}
+}
+
+// ContextWithRequestID returns a copy of provided context with provided
+// request ID used by the ContextID generator created with nil key.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns request ID set by the ContextWithRequestID
+// function.
+func RequestIDFromContext(ctx context.Context) (id string, ok bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	id, ok = ctx.Value(requestIDKey{}).(string)
+
+	return id, ok
+}
+
+// GetFallback returns inner ID generator used when request ID is not present.
+func (c *ContextID) GetFallback() IDGenerator {
+	return c.fallback
+}
+
+// Generate generates a new ID using inner ID generator.
+func (c *ContextID) Generate() (id string, err error) {
+	return c.fallback.Generate()
+}
+
+// GenerateFor returns request ID stored in provided context. It generates
+// a new ID using inner ID generator if context is nil or request ID is not
+// present or it is empty.
+func (c *ContextID) GenerateFor(ctx context.Context, _ *Record) (id string, err error) {
+	if ctx != nil {
+		switch value := ctx.Value(c.key).(type) {
+		case string:
+			id = value
+		case fmt.Stringer:
+			id = value.String()
+		}
+	}
+
+	if id != "" {
+		return id, nil
+	}
+
+	return c.fallback.Generate()
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+type requestID string
+
+func (r requestID) String() string {
+	return "request-" + string(r)
+}
+
+func TestContextID(test *testing.T) {
+	for _, generation := range []logger.IDGeneration{logger.IDGenerationAlways, logger.IDGenerationLazy} {
+		buffer := logger.NewBuffer()
+		buffer.GetFormatter().SetFormat("{id}")
+
+		log := logger.New().SetSynchronous(true).SetHandler("buffer", buffer).SetIDGeneration(generation).
+			SetIDGenerator(logger.NewContextID(nil, logger.NewStaticID("fallback")))
+
+		ctx := logger.ContextWithRequestID(context.Background(), "abc")
+
+		log.InfoContext(ctx, testMessage)
+		log.InfoContext(context.Background(), testMessage)
+		log.Info(testMessage)
+
+		if want := "abc\nfallback\nfallback\n"; buffer.String() != want {
+			test.Errorf("%v: buffer.String() = %q; want %q", generation, buffer.String(), want)
+		}
+	}
+}
+
+func TestContextIDCustomKey(test *testing.T) {
+	type key struct{}
+
+	generator := logger.NewContextID(key{}, nil)
+	ctx := context.WithValue(context.Background(), key{}, requestID("42"))
+
+	if id, err := generator.GenerateFor(ctx, nil); (err != nil) || (id != "request-42") {
+		test.Errorf("GenerateFor() = %q, %v; want %q", id, err, "request-42")
+	}
+
+	if id, err := generator.GenerateFor(context.WithValue(ctx, key{}, 42), nil); (err != nil) || (len(id) != 36) {
+		test.Errorf("GenerateFor() = %q, %v; want UUID", id, err)
+	}
+
+	if _, ok := generator.GetFallback().(*logger.UUID4); !ok {
+		test.Errorf("GetFallback() = %T; want *logger.UUID4", generator.GetFallback())
+	}
+}
+
+func TestRequestIDFromContext(test *testing.T) {
+	if id, ok := logger.RequestIDFromContext(logger.ContextWithRequestID(context.Background(), "abc")); !ok || (id != "abc") {
+		test.Errorf("RequestIDFromContext() = %q, %v; want %q, true", id, ok, "abc")
+	}
+
+	if _, ok := logger.RequestIDFromContext(nil); ok { // nolint:staticcheck
+		test.Error("RequestIDFromContext(nil) = true; want false")
+	}
+}
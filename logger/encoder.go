@@ -0,0 +1,194 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Encoder defines interface for encoding log records written by the Stream
+// log handler. It is an alternative to stream handlers that makes output
+// formats composable.
+type Encoder interface {
+	Encode(record *Record) ([]byte, error)
+}
+
+// A TextEncoder represents an encoder that encodes log records as text using
+// formatter.
+type TextEncoder struct {
+	formatter *Formatter
+}
+
+// A JSONEncoder represents an encoder that encodes log records in the JSON
+// format like the Record.ToJSON method. Log record attributes and named values
+// are rewritten by function set by the Formatter.SetReplaceAttr method.
+type JSONEncoder struct {
+	formatter *Formatter
+}
+
+// A LogfmtEncoder represents an encoder that encodes log records in the
+// logfmt format as key=value pairs with time, level, name, source location,
+// formatted log message and labels.
+type LogfmtEncoder struct {
+	formatter *Formatter
+}
+
+// NewTextEncoder creates a new TextEncoder object that encodes log records
+// using provided formatter. Nil formatter is replaced with a new default
+// formatter.
+func NewTextEncoder(formatter *Formatter) *TextEncoder {
+	if formatter == nil {
+		formatter = NewFormatter()
+	}
+
+	return &TextEncoder{
+		formatter: formatter,
+	}
+}
+
+// GetFormatter returns formatter used to encode log records.
+func (e *TextEncoder) GetFormatter() *Formatter {
+	return e.formatter
+}
+
+// Encode encodes provided log record as text using formatter.
+func (e *TextEncoder) Encode(record *Record) ([]byte, error) {
+	message, err := e.formatter.Format(record)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot format record", err)
+	}
+
+	return []byte(message), nil
+}
+
+// NewJSONEncoder creates a new JSONEncoder object.
+func NewJSONEncoder() *JSONEncoder {
+	return &JSONEncoder{
+		formatter: NewFormatter(),
+	}
+}
+
+// GetFormatter returns formatter with function used to rewrite log record
+// attributes and named values.
+func (e *JSONEncoder) GetFormatter() *Formatter {
+	return e.formatter
+}
+
+// Encode encodes provided log record in the JSON format.
+func (e *JSONEncoder) Encode(record *Record) ([]byte, error) {
+	replaceAttr := e.formatter.GetReplaceAttr()
+
+	if replaceAttr == nil {
+		return record.ToJSON()
+	}
+
+	attributes := record.jsonFields()
+
+	for index, attribute := range attributes {
+		switch attribute.Key {
+		case "arguments":
+			attributes[index].Value = replaceArguments(record.Arguments, replaceAttr)
+		case "fields":
+			fields, _ := getNamedFields(record.Fields)
+			attributes[index].Value = replaceFields(fields, replaceAttr)
+		}
+	}
+
+	return replaceFields(attributes, replaceAttr).MarshalJSON()
+}
+
+// NewLogfmtEncoder creates a new LogfmtEncoder object.
+func NewLogfmtEncoder() *LogfmtEncoder {
+	return &LogfmtEncoder{
+		formatter: NewFormatter(),
+	}
+}
+
+// GetFormatter returns formatter used to format log message.
+func (e *LogfmtEncoder) GetFormatter() *Formatter {
+	return e.formatter
+}
+
+// Encode encodes provided log record in the logfmt format.
+func (e *LogfmtEncoder) Encode(record *Record) ([]byte, error) {
+	message, err := e.formatter.FormatMessage(record)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot format message", err)
+	}
+
+	attributes := Fields{
+		{Key: "time", Value: record.Time.Format(time.RFC3339Nano)},
+		{Key: "level", Value: strings.ToLower(record.Level.Name)},
+		{Key: "name", Value: record.Name},
+	}
+
+	if record.File.Line != 0 {
+		attributes = append(attributes, Field{Key: "source", Value: record.File.Name + ":" + strconv.Itoa(record.File.Line)})
+	}
+
+	attributes = append(attributes, Field{Key: "msg", Value: message})
+
+	keys := make([]string, 0, len(record.Labels))
+
+	for key := range record.Labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		attributes = append(attributes, Field{Key: key, Value: record.Labels[key]})
+	}
+
+	var builder strings.Builder
+
+	for _, attribute := range replaceFields(attributes, e.formatter.GetReplaceAttr()) {
+		appendLogfmt(&builder, attribute.Key, fmt.Sprint(attribute.Value))
+	}
+
+	return []byte(builder.String()), nil
+}
+
+// appendLogfmt appends provided key and value as logfmt pair. Value is quoted
+// if it is empty or if it contains spaces, quotes, equal signs or control
+// characters.
+func appendLogfmt(builder *strings.Builder, key, value string) {
+	if builder.Len() != 0 {
+		builder.WriteByte(' ')
+	}
+
+	builder.WriteString(key)
+	builder.WriteByte('=')
+
+	if (value == "") || strings.IndexFunc(value, needsLogfmtQuote) >= 0 {
+		value = strconv.Quote(value)
+	}
+
+	builder.WriteString(value)
+}
+
+// needsLogfmtQuote returns true if provided character requires quoting of
+// logfmt value.
+func needsLogfmtQuote(character rune) bool {
+	return (character == '"') || (character == '=') || (character == '\\') ||
+		unicode.IsSpace(character) || !unicode.IsPrint(character)
+}
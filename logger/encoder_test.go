@@ -0,0 +1,122 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func newEncoderRecord() *logger.Record {
+	return &logger.Record{
+		Time:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Name:      "app",
+		Message:   "Hello {p}",
+		Arguments: logger.Arguments{"world"},
+		Labels:    map[string]string{"env": "prod", "zone": "eu 1"},
+		Level: logger.Level{
+			Value: logger.InfoLevel,
+			Name:  logger.InfoName,
+		},
+		File: logger.Source{
+			Name: "main.go",
+			Line: 10,
+		},
+	}
+}
+
+func TestStreamSetEncoder(test *testing.T) {
+	record := newEncoderRecord()
+
+	text := logger.NewBuffer()
+	encoded := logger.NewBuffer().SetEncoder(logger.NewTextEncoder(text.GetFormatter()))
+
+	for _, buffer := range []*logger.Buffer{text, encoded} {
+		if err := buffer.Emit(record); err != nil {
+			test.Fatal("Emit() returns an unexpected error", err)
+		}
+	}
+
+	if encoded.String() != text.String() {
+		test.Errorf("TextEncoder = %q; want %q", encoded.String(), text.String())
+	}
+
+	json := logger.NewBuffer().SetEncoder(logger.NewJSONEncoder())
+
+	if err := json.Emit(record); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	data, err := record.ToJSON()
+
+	if err != nil {
+		test.Fatal("ToJSON() returns an unexpected error", err)
+	}
+
+	if want := string(data) + "\n"; json.String() != want {
+		test.Errorf("JSONEncoder = %q; want %q", json.String(), want)
+	}
+}
+
+func TestLogfmtEncoder(test *testing.T) {
+	data, err := logger.NewLogfmtEncoder().Encode(newEncoderRecord())
+
+	if err != nil {
+		test.Fatal("Encode() returns an unexpected error", err)
+	}
+
+	want := `time=2020-01-02T03:04:05Z level=info name=app source=main.go:10 msg="Hello world" env=prod zone="eu 1"`
+
+	if string(data) != want {
+		test.Errorf("Encode() = %q; want %q", data, want)
+	}
+}
+
+func TestJSONEncoderReplaceAttr(test *testing.T) {
+	record := newEncoderRecord()
+	record.Arguments = logger.Arguments{logger.Named{"password": "secret", "user": "admin"}}
+
+	encoder := logger.NewJSONEncoder()
+
+	encoder.GetFormatter().SetReplaceAttr(func(key string, value interface{}) (string, interface{}, bool) {
+		switch key {
+		case "password", "hostname", "address", "timestamp":
+			return key, value, false
+		case "user":
+			return "username", value, true
+		case "message":
+			return "msg", value, true
+		}
+
+		return key, value, true
+	})
+
+	data, err := encoder.Encode(record)
+
+	if err != nil {
+		test.Fatal("Encode() returns an unexpected error", err)
+	}
+
+	want := `{"id":"","type":"","name":"app","level":{"value":` + strconv.Itoa(logger.InfoLevel) +
+		`,"name":"info"},"msg":"Hello {p}","file":{"function":"","name":"main.go","line":10},` +
+		`"arguments":[{"username":"admin"}],"labels":{"env":"prod","zone":"eu 1"},"time":"2020-01-02T03:04:05Z"}`
+
+	if string(data) != want {
+		test.Errorf("Encode() = %s; want %s", data, want)
+	}
+}
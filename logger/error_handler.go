@@ -0,0 +1,148 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrorHandler defines function that receives internal logger errors like
+// for example cannot emit record, cannot generate ID or cannot format.
+type ErrorHandler func(err error)
+
+// An errorReporter represents an internal error handler with protection
+// against recursive calls.
+type errorReporter struct {
+	handler ErrorHandler
+	mutex   sync.RWMutex
+}
+
+// gReporting counts running internal error handlers. Call stack is searched
+// for running internal error handler only when it is not zero.
+var gReporting int32 // nolint:gochecknoglobals
+
+var gErrorReporter errorReporter // nolint:gochecknoglobals
+
+var gCallErrorHandlerName = runtime.FuncForPC( // nolint:gochecknoglobals
+	reflect.ValueOf(callErrorHandler).Pointer(),
+).Name()
+
+// SetInternalErrorHandler sets global internal error handler. It receives
+// internal errors from all loggers without own internal error handler and
+// from all places without logger context like formatters. Setting it to nil
+// restores the default behavior of printing errors to error output.
+func SetInternalErrorHandler(handler ErrorHandler) {
+	gErrorReporter.set(handler)
+}
+
+// GetInternalErrorHandler returns global internal error handler.
+func GetInternalErrorHandler() ErrorHandler {
+	return gErrorReporter.get()
+}
+
+// set sets internal error handler.
+func (e *errorReporter) set(handler ErrorHandler) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.handler = handler
+}
+
+// get returns internal error handler.
+func (e *errorReporter) get() ErrorHandler {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.handler
+}
+
+// report passes error to internal error handler. It returns false if
+// internal error handler is not set. Errors reported by internal error handler
+// itself, when it is already running in the calling goroutine, are printed to
+// error output to avoid recursion. Errors reported concurrently by other
+// goroutines are passed to internal error handler. Log records logged by
+// internal error handler are marked, so errors caused by them in logger worker
+// thread are printed to error output too.
+func (e *errorReporter) report(err error) bool {
+	handler := e.get()
+
+	if handler == nil {
+		return false
+	}
+
+	if isReportingError() {
+		printErrorOutput(err)
+		return true
+	}
+
+	callErrorHandler(handler, err)
+
+	return true
+}
+
+// callErrorHandler calls provided internal error handler. It counts running
+// internal error handlers and its frame on the call stack marks that internal
+// error handler is running in the calling goroutine.
+//
+//go:noinline
+func callErrorHandler(handler ErrorHandler, err error) {
+	atomic.AddInt32(&gReporting, 1)
+	defer atomic.AddInt32(&gReporting, -1)
+
+	handler(err)
+}
+
+// isReportingError returns true if internal error handler is running in
+// the calling goroutine. Without running internal error handlers it returns
+// false without searching the call stack.
+func isReportingError() bool {
+	if atomic.LoadInt32(&gReporting) == 0 {
+		return false
+	}
+
+	return hasCaller(3, gCallErrorHandlerName)
+}
+
+// reportRecordError reports error caused by provided log records. Errors
+// caused by log records logged by internal error handler are printed to error
+// output, because reporting them would log them again through logger worker
+// thread endlessly.
+func reportRecordError(logger *Logger, err error, records ...*Record) {
+	for _, record := range records {
+		if record.internal {
+			printErrorOutput(err)
+			return
+		}
+	}
+
+	logger.printError(err)
+}
+
+// printError reports error using global internal error handler.
+func printError(err error) {
+	if !gErrorReporter.report(err) {
+		printErrorOutput(err)
+	}
+}
+
+// printErrorOutput prints error to error output.
+func printErrorOutput(err error) {
+	fmt.Fprintln(os.Stderr, "Logger error:", err)
+}
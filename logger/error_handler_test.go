@@ -0,0 +1,196 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestLoggerInternalErrorHandler(test *testing.T) {
+	var errs []error
+
+	log := logger.New().SetHandler("buffer", logger.NewBuffer())
+
+	log.SetInternalErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	log.AddHook("error", func(*logger.Record) error {
+		return testError
+	})
+
+	log.Info(testMessage)
+	log.Flush()
+
+	if len(errs) != 1 {
+		test.Fatalf("len(errs) = %d; want 1", len(errs))
+	}
+
+	if !errors.Is(errs[0], testError) {
+		test.Error("errors.Is() = false; want true")
+	}
+}
+
+func TestInternalErrorHandlerRecursion(test *testing.T) {
+	calls := 0
+
+	formatter := logger.NewFormatter().SetFormat("{date}").SetDateFormat("{invalid")
+	recursive := logger.NewFormatter().SetFormat("{date}").SetDateFormat("{invalid")
+
+	logger.SetInternalErrorHandler(func(err error) {
+		calls++
+
+		if _, err := recursive.Format(&logger.Record{}); err != nil {
+			test.Error("Format() returns an unexpected error", err)
+		}
+	})
+
+	defer logger.SetInternalErrorHandler(nil)
+
+	if _, err := formatter.Format(&logger.Record{}); err != nil {
+		test.Error("Format() returns an unexpected error", err)
+	}
+
+	if calls != 1 {
+		test.Errorf("calls = %d; want 1", calls)
+	}
+}
+
+func TestInternalErrorHandlerConcurrent(test *testing.T) {
+	const count = 8
+
+	started := make(chan struct{})
+	released := make(chan struct{})
+
+	var calls int32
+
+	logger.SetInternalErrorHandler(func(error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-released
+		}
+	})
+
+	defer logger.SetInternalErrorHandler(nil)
+
+	format := func() {
+		formatter := logger.NewFormatter().SetFormat("{date}").SetDateFormat("{invalid")
+
+		if _, err := formatter.Format(&logger.Record{}); err != nil {
+			test.Error("Format() returns an unexpected error", err)
+		}
+	}
+
+	var blocked sync.WaitGroup
+
+	blocked.Add(1)
+
+	go func() {
+		defer blocked.Done()
+		format()
+	}()
+
+	<-started
+
+	var wg sync.WaitGroup
+
+	for index := 0; index < count; index++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			format()
+		}()
+	}
+
+	wg.Wait()
+	close(released)
+	blocked.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != count+1 {
+		test.Errorf("calls = %d; want %d", got, count+1)
+	}
+}
+
+func TestInternalErrorHandlerWorker(test *testing.T) {
+	var calls int32
+
+	stream := logger.NewStream()
+
+	if err := stream.SetWriter(failingWriter{}); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	log := logger.New().SetHandler("failing", stream).SetWorker(logger.NewWorker())
+
+	log.SetInternalErrorHandler(func(err error) {
+		atomic.AddInt32(&calls, 1)
+		log.Error("internal error: {p}", err)
+	})
+
+	log.Info(testMessage)
+
+	for index := 0; index < 10; index++ {
+		log.Flush()
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		test.Errorf("calls = %d; want 1", got)
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func reportDeep(depth int, fn func()) {
+	if depth == 0 {
+		fn()
+		return
+	}
+
+	reportDeep(depth-1, fn)
+}
+
+func TestInternalErrorHandlerDeepStack(test *testing.T) {
+	calls := 0
+
+	stream := logger.NewStream()
+
+	if err := stream.SetWriter(failingWriter{}); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	log := logger.New().SetHandler("failing", stream).SetSynchronous(true)
+
+	log.SetInternalErrorHandler(func(err error) {
+		calls++
+
+		reportDeep(1000, func() {
+			log.Error("internal error: {p}", err)
+		})
+	})
+
+	log.Info(testMessage)
+
+	if calls != 1 {
+		test.Errorf("calls = %d; want 1", calls)
+	}
+}
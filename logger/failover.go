@@ -0,0 +1,231 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// A Failover represents a log handler object that emits log records via
+// primary log handler and it routes log records to fallback log handler when
+// primary log handler fails, like for example to spill log records to a local
+// file when network destination is not available. Log records are emitted via
+// fallback log handler as copies created by the Record.Clone method.
+//
+// After set number of consecutive failures of primary log handler it stops
+// trying primary log handler for set cooldown and log records are emitted
+// only via fallback log handler. After cooldown primary log handler is tried
+// again. On default primary log handler is tried for every log record.
+type Failover struct {
+	HandlerBase
+	primary   Handler
+	fallback  Handler
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	retryTime time.Time
+	mutex     sync.RWMutex
+}
+
+// NewFailover creates a new Failover log handler object with provided primary
+// and fallback log handlers.
+//
+//	syslog := logger.NewSyslog().SetNetwork("tcp").SetWriteTimeout(time.Second)
+//	file := logger.NewFile().SetName("/var/log/app.log")
+//
+//	log.AddHandler("syslog", logger.NewFailover(syslog, file).SetCooldown(3, time.Minute))
+func NewFailover(primary, fallback Handler) *Failover {
+	f := &Failover{
+		primary:  primary,
+		fallback: fallback,
+	}
+
+	f.owner = f
+
+	return f
+}
+
+// GetPrimary returns primary log handler.
+func (f *Failover) GetPrimary() Handler {
+	return f.primary
+}
+
+// GetFallback returns fallback log handler.
+func (f *Failover) GetFallback() Handler {
+	return f.fallback
+}
+
+// SetCooldown sets number of consecutive failures of primary log handler after
+// which primary log handler is not tried for provided cooldown. Zero or
+// negative number of failures or cooldown disables it and it is the default.
+func (f *Failover) SetCooldown(failures int, cooldown time.Duration) *Failover {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if (failures <= 0) || (cooldown <= 0) {
+		failures, cooldown = 0, 0
+	}
+
+	f.threshold = failures
+	f.cooldown = cooldown
+	f.retryTime = time.Time{}
+
+	return f
+}
+
+// GetCooldown returns number of consecutive failures of primary log handler
+// and cooldown set by the SetCooldown method.
+func (f *Failover) GetCooldown() (failures int, cooldown time.Duration) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.threshold, f.cooldown
+}
+
+// GetFailures returns number of consecutive failures of primary log handler.
+// It is reset after successful emitting of log record via primary log handler.
+func (f *Failover) GetFailures() int {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.failures
+}
+
+// SetFormatter sets Formatter to primary and fallback log handlers.
+func (f *Failover) SetFormatter(formatter *Formatter) Handler {
+	f.primary.SetFormatter(formatter)
+	f.fallback.SetFormatter(formatter)
+
+	return f
+}
+
+// GetFormatter returns Formatter of primary log handler.
+func (f *Failover) GetFormatter() *Formatter {
+	return f.primary.GetFormatter()
+}
+
+// Emit emits log record via primary log handler and on its error via
+// fallback log handler. It returns an error only if both log handlers fail.
+func (f *Failover) Emit(record *Record) error {
+	var primaryErr error
+
+	if f.isPrimaryActive() {
+		if primaryErr = f.primary.Emit(record); primaryErr == nil {
+			f.succeeded()
+			return nil
+		}
+
+		f.failed()
+	}
+
+	if err := f.fallback.Emit(record.Clone()); err != nil {
+		return NewRuntimeError("cannot emit record via fallback handler", errors.Join(primaryErr, err))
+	}
+
+	return nil
+}
+
+// isPrimaryActive returns false during cooldown of primary log handler.
+func (f *Failover) isPrimaryActive() bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.retryTime.IsZero() || !time.Now().Before(f.retryTime)
+}
+
+// succeeded resets consecutive failures of primary log handler.
+func (f *Failover) succeeded() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.failures = 0
+	f.retryTime = time.Time{}
+}
+
+// failed counts failure of primary log handler and it starts cooldown after
+// set number of consecutive failures.
+func (f *Failover) failed() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.failures++
+
+	if (f.threshold > 0) && (f.failures >= f.threshold) {
+		f.retryTime = time.Now().Add(f.cooldown)
+	}
+}
+
+// RetainsRecords returns true if primary or fallback log handler keeps
+// provided log records after returning from the Emit method.
+func (f *Failover) RetainsRecords() bool {
+	for _, handler := range []Handler{f.primary, f.fallback} {
+		if retainer, ok := handler.(Retainer); ok && retainer.RetainsRecords() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Flush flushes primary and fallback log handlers that implement the Flusher
+// interface.
+func (f *Failover) Flush() error {
+	var errs []error
+
+	for _, handler := range []Handler{f.primary, f.fallback} {
+		if flusher, ok := handler.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) != 0 {
+		return NewRuntimeError("cannot flush failover handlers", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Reopen reopens primary and fallback log handlers that implement the Reopener
+// interface.
+func (f *Failover) Reopen() Handler {
+	for _, handler := range []Handler{f.primary, f.fallback} {
+		if reopener, ok := handler.(Reopener); ok {
+			reopener.Reopen()
+		}
+	}
+
+	return f
+}
+
+// Close closes primary and fallback log handlers.
+func (f *Failover) Close() error {
+	var errs []error
+
+	for _, handler := range []Handler{f.primary, f.fallback} {
+		if err := handler.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return NewRuntimeError("cannot close failover handlers", errors.Join(errs...))
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+type toggleWriter struct {
+	buffer  bytes.Buffer
+	failing bool
+}
+
+func (w *toggleWriter) Write(data []byte) (int, error) {
+	if w.failing {
+		return 0, testError
+	}
+
+	return w.buffer.Write(data)
+}
+
+func TestFailover(test *testing.T) {
+	writer := &toggleWriter{}
+
+	primary := logger.NewWriter(writer)
+	fallback := logger.NewBuffer()
+
+	primary.GetFormatter().SetFormat("{message}")
+	fallback.GetFormatter().SetFormat("{message}")
+
+	failover := logger.NewFailover(primary, fallback)
+
+	log := logger.New().SetSynchronous(true).SetHandler("failover", failover)
+
+	log.Info("first")
+
+	writer.failing = true
+
+	log.Info("second")
+
+	if failures := failover.GetFailures(); failures != 1 {
+		test.Errorf("GetFailures() = %d; want 1", failures)
+	}
+
+	failover.SetCooldown(2, time.Hour)
+
+	log.Info("third")
+
+	writer.failing = false
+
+	log.Info("fourth")
+
+	if want := "first\n"; writer.buffer.String() != want {
+		test.Errorf("primary = %q; want %q", writer.buffer.String(), want)
+	}
+
+	if want := "second\nthird\nfourth\n"; fallback.String() != want {
+		test.Errorf("fallback = %q; want %q", fallback.String(), want)
+	}
+
+	if failures, cooldown := failover.SetCooldown(2, time.Nanosecond).GetCooldown(); (failures != 2) || (cooldown != time.Nanosecond) {
+		test.Errorf("GetCooldown() = %d, %v; want 2, %v", failures, cooldown, time.Nanosecond)
+	}
+
+	log.Info("fifth")
+
+	if want := "first\nfifth\n"; writer.buffer.String() != want {
+		test.Errorf("primary = %q; want %q", writer.buffer.String(), want)
+	}
+
+	if failures := failover.GetFailures(); failures != 0 {
+		test.Errorf("GetFailures() = %d; want 0", failures)
+	}
+}
+
+func TestFailoverErrors(test *testing.T) {
+	failover := logger.NewFailover(logger.NewWriter(failingWriter{}), logger.NewWriter(failingWriter{}))
+
+	if err := failover.Emit(&logger.Record{Message: testMessage}); err == nil {
+		test.Error("Emit() doesn't return an error when both handlers fail")
+	}
+
+	if failures, cooldown := failover.SetCooldown(0, time.Minute).GetCooldown(); (failures != 0) || (cooldown != 0) {
+		test.Errorf("GetCooldown() = %d, %v; want 0, 0", failures, cooldown)
+	}
+}
+
+func TestFailoverClose(test *testing.T) {
+	var closed int32
+
+	failover := logger.NewFailover(
+		closeCountingBuffer{Buffer: logger.NewBuffer(), closed: &closed},
+		closeCountingBuffer{Buffer: logger.NewBuffer(), closed: &closed},
+	)
+
+	if err := failover.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	if got := atomic.LoadInt32(&closed); got != 2 {
+		test.Errorf("closed = %d; want 2", got)
+	}
+}
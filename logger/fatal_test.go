@@ -0,0 +1,90 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+const (
+	testFatalFile     = "LOGGER_TEST_FATAL_FILE"
+	testFatalExitCode = 3
+)
+
+func TestLoggerFatalClosesInstance(test *testing.T) {
+	if name := os.Getenv(testFatalFile); name != "" {
+		file := logger.NewFile().SetName(name)
+		file.GetFormatter().SetFormat("{message}")
+
+		log := logger.New().SetHandler("file", file).SetErrorCode(testFatalExitCode)
+
+		log.Fatal(testMessage)
+
+		return
+	}
+
+	name := filepath.Join(test.TempDir(), "fatal.log")
+
+	command := exec.Command(os.Args[0], "-test.run=^TestLoggerFatalClosesInstance$") // nolint:gosec
+	command.Env = append(os.Environ(), testFatalFile+"="+name)
+
+	var exitError *exec.ExitError
+
+	if err := command.Run(); !errors.As(err, &exitError) || exitError.ExitCode() != testFatalExitCode {
+		test.Fatalf("command.Run() = %v; want exit code %d", err, testFatalExitCode)
+	}
+
+	data, err := ioutil.ReadFile(name) // nolint:gosec
+
+	if err != nil {
+		test.Fatal("ReadFile() returns an unexpected error", err)
+	}
+
+	if string(data) != testMessage+"\n" {
+		test.Error("ReadFile() =", string(data), "; want", testMessage)
+	}
+}
+
+func TestLoggerSetExitFunc(test *testing.T) {
+	code := 0
+
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer).SetErrorCode(testFatalExitCode)
+
+	log.SetExitFunc(func(exitCode int) {
+		code = exitCode
+	})
+
+	log.Fatal(testMessage)
+
+	if code != testFatalExitCode {
+		test.Errorf("code = %d; want %d", code, testFatalExitCode)
+	}
+
+	if buffer.String() != testMessage+"\n" {
+		test.Error("buffer.String() =", buffer.String(), "; want", testMessage)
+	}
+
+	log.SetExitFunc(nil).Fatal(testMessage)
+}
@@ -15,23 +15,50 @@
 package logger
 
 import (
+	"bufio"
 	"io"
 	"os"
+	"time"
 )
 
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandlerFactory("file", newFileFromOptions)
+}
+
 // These constants define default values for File log handler.
 const (
 	DefaultFileName  = "log"
 	DefaultFileMode  = 0644
 	DefaultFileFlags = os.O_CREATE | os.O_APPEND | os.O_WRONLY
+
+	DefaultFileFlushInterval = time.Second
 )
 
 // A File represents a log handler object for logging messages to file.
 type File struct {
-	name   string
-	stream *Stream
-	flags  int
-	mode   os.FileMode
+	name          string
+	stream        *Stream
+	flags         int
+	mode          os.FileMode
+	bufferSize    int
+	flushInterval time.Duration
+	locking       bool
+}
+
+// lockedFile defines opened file that is locked by an advisory lock during
+// every write.
+type lockedFile struct {
+	*os.File
+}
+
+// bufferedFile defines opened file with a write buffer in front of it. It is
+// flushed periodically by a separate goroutine and on close. All methods must
+// be called with the stream lock held.
+type bufferedFile struct {
+	*bufio.Writer
+	file   *os.File
+	done   chan struct{}
+	closed bool
 }
 
 // NewFile creates a new File log handler object.
@@ -41,6 +68,8 @@ func NewFile() *File {
 		mode:   DefaultFileMode,
 		flags:  DefaultFileFlags,
 		stream: NewStream(),
+
+		flushInterval: DefaultFileFlushInterval,
 	}
 
 	f.stream.SetOpener(f)
@@ -48,15 +77,88 @@ func NewFile() *File {
 	return f
 }
 
+// newFileFromOptions creates a new File log handler object configured with
+// provided options: "name", "flags", "mode", "bufferSize", "flushInterval" and
+// "locking".
+func newFileFromOptions(options HandlerOptions) (Handler, error) {
+	f := NewFile()
+	o := newHandlerOptions(options)
+
+	o.setString("name", func(name string) { f.SetName(name) })
+	o.setInt("flags", func(flags int) { f.SetFlags(flags) })
+	o.setInt("mode", func(mode int) { f.SetMode(os.FileMode(mode)) })
+	o.setInt("bufferSize", func(size int) { f.SetWriteBuffer(size) })
+	o.setDuration("flushInterval", func(interval time.Duration) { f.SetFlushInterval(interval) })
+	o.setBool("locking", func(locking bool) { f.SetLocking(locking) })
+
+	if err := o.err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
 // SetStreamHandler sets custom stream handler.
 func (f *File) SetStreamHandler(handler StreamHandler) *File {
 	f.stream.SetStreamHandler(handler)
 	return f
 }
 
-// Open file.
+// SetEncoder sets encoder used to encode log records instead of stream handler
+// and formatter.
+func (f *File) SetEncoder(encoder Encoder) *File {
+	f.stream.SetEncoder(encoder)
+	return f
+}
+
+// SetLineEnding sets line ending written after every log record.
+func (f *File) SetLineEnding(lineEnding string) *File {
+	f.stream.SetLineEnding(lineEnding)
+	return f
+}
+
+// Open file. Opened file is wrapped with a write buffer if it was set by the
+// SetWriteBuffer method and it is locked during every write if locking was
+// enabled by the SetLocking method.
 func (f *File) Open() (io.WriteCloser, error) {
-	return os.OpenFile(f.name, f.flags, f.mode)
+	file, err := os.OpenFile(f.name, f.flags, f.mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var writer io.WriteCloser = file
+
+	if f.locking {
+		writer = &lockedFile{File: file}
+	}
+
+	if f.bufferSize <= 0 {
+		return writer, nil
+	}
+
+	buffered := &bufferedFile{
+		Writer: bufio.NewWriterSize(writer, f.bufferSize),
+		file:   file,
+		done:   make(chan struct{}),
+	}
+
+	if f.flushInterval > 0 {
+		go f.flushPeriodically(buffered, f.flushInterval)
+	}
+
+	return buffered, nil
+}
+
+// SetHandlerName sets log handler name.
+func (f *File) SetHandlerName(name string) Handler {
+	f.stream.SetHandlerName(name)
+	return f
+}
+
+// GetHandlerName returns log handler name.
+func (f *File) GetHandlerName() string {
+	return f.stream.GetHandlerName()
 }
 
 // Enable enables log handler.
@@ -126,7 +228,7 @@ func (f *File) SetName(name string) *File {
 
 	if f.name != name {
 		f.name = name
-		f.stream.Reopen()
+		f.stream.reopen = true
 	}
 
 	return f
@@ -147,7 +249,7 @@ func (f *File) SetFlags(flags int) *File {
 
 	if f.flags != flags {
 		f.flags = flags
-		f.stream.Reopen()
+		f.stream.reopen = true
 	}
 
 	return f
@@ -168,7 +270,7 @@ func (f *File) SetMode(mode os.FileMode) *File {
 
 	if f.mode != mode {
 		f.mode = mode
-		f.stream.Reopen()
+		f.stream.reopen = true
 	}
 
 	return f
@@ -182,12 +284,210 @@ func (f *File) GetMode() os.FileMode {
 	return f.mode
 }
 
+// SetWriteBuffer sets size in bytes of write buffer used in front of file.
+// Buffered log records are written to file when buffer is full, periodically
+// with interval set by the SetFlushInterval method, on the Flush, Sync and
+// Close methods. Zero or negative size disables write buffer and every log
+// record is written directly to file. By default write buffer is disabled.
+func (f *File) SetWriteBuffer(size int) *File {
+	f.stream.Lock()
+	defer f.stream.Unlock()
+
+	if f.bufferSize != size {
+		f.bufferSize = size
+		f.stream.reopen = true
+	}
+
+	return f
+}
+
+// GetWriteBuffer returns size in bytes of write buffer.
+func (f *File) GetWriteBuffer() int {
+	f.stream.RLock()
+	defer f.stream.RUnlock()
+
+	return f.bufferSize
+}
+
+// SetFlushInterval sets interval of periodic write buffer flushes. Zero or
+// negative interval disables periodic flushes. It is applied on the next file
+// open. Default interval is DefaultFileFlushInterval.
+func (f *File) SetFlushInterval(interval time.Duration) *File {
+	f.stream.Lock()
+	defer f.stream.Unlock()
+
+	if f.flushInterval != interval {
+		f.flushInterval = interval
+		f.stream.reopen = true
+	}
+
+	return f
+}
+
+// GetFlushInterval returns interval of periodic write buffer flushes.
+func (f *File) GetFlushInterval() time.Duration {
+	f.stream.RLock()
+	defer f.stream.RUnlock()
+
+	return f.flushInterval
+}
+
+// SetLocking enables or disables locking of file by an advisory exclusive lock
+// during every write. It prevents interleaving of log records written by
+// multiple processes sharing the same log file. Every write costs additional
+// two system calls and it waits for other processes holding the lock. Locking
+// is advisory, it protects only from processes that also lock the log file.
+// It uses the flock system call and it does nothing on platforms without it,
+// like for example Windows. With write buffer set by the SetWriteBuffer method
+// log records are locked when write buffer is flushed and log record that
+// doesn't fit in write buffer can be split. By default locking is disabled.
+func (f *File) SetLocking(locking bool) *File {
+	f.stream.Lock()
+	defer f.stream.Unlock()
+
+	if f.locking != locking {
+		f.locking = locking
+		f.stream.reopen = true
+	}
+
+	return f
+}
+
+// IsLocking returns if file is locked during every write.
+func (f *File) IsLocking() bool {
+	f.stream.RLock()
+	defer f.stream.RUnlock()
+
+	return f.locking
+}
+
+// Flush writes buffered log records to file. It does nothing if write buffer
+// is disabled.
+func (f *File) Flush() error {
+	f.stream.Lock()
+	defer f.stream.Unlock()
+
+	if buffered, ok := f.stream.writer.(*bufferedFile); ok {
+		if err := buffered.Flush(); err != nil {
+			return NewRuntimeError("cannot flush file", err)
+		}
+	}
+
+	return nil
+}
+
+// Sync writes buffered log records to file and it commits file content to
+// stable storage.
+func (f *File) Sync() error {
+	f.stream.Lock()
+	defer f.stream.Unlock()
+
+	var file *os.File
+
+	switch writer := f.stream.writer.(type) {
+	case *bufferedFile:
+		if err := writer.Flush(); err != nil {
+			return NewRuntimeError("cannot flush file", err)
+		}
+
+		file = writer.file
+	case *os.File:
+		file = writer
+	case *lockedFile:
+		file = writer.File
+	default:
+		return nil
+	}
+
+	if err := file.Sync(); err != nil {
+		return NewRuntimeError("cannot sync file", err)
+	}
+
+	return nil
+}
+
+// Reopen reopens file before emitting the next log record. It is useful
+// after renaming log file by external tools like logrotate.
+func (f *File) Reopen() Handler {
+	f.stream.Reopen()
+	return f
+}
+
 // Emit logs messages from Logger to file.
 func (f *File) Emit(record *Record) error {
 	return f.stream.Emit(record)
 }
 
+// EmitBatch logs multiple messages from Logger to file with a single write.
+func (f *File) EmitBatch(records []*Record) error {
+	return f.stream.emitBatch(records)
+}
+
+// Profile returns time spent on formatting and writing of log records like
+// the Stream Profile method.
+func (f *File) Profile() Profile {
+	return f.stream.Profile()
+}
+
 // Close closes opened file.
 func (f *File) Close() error {
 	return f.stream.Close()
 }
+
+// flushPeriodically flushes write buffer with provided interval until buffered
+// file is closed.
+func (f *File) flushPeriodically(buffered *bufferedFile, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-buffered.done:
+			return
+		case <-ticker.C:
+			f.stream.Lock()
+
+			if !buffered.closed {
+				if err := buffered.Flush(); err != nil {
+					printError(NewRuntimeError("cannot flush file", err))
+				}
+			}
+
+			f.stream.Unlock()
+		}
+	}
+}
+
+// Close flushes write buffer and it closes file.
+func (b *bufferedFile) Close() error {
+	if b.closed {
+		return nil
+	}
+
+	b.closed = true
+	close(b.done)
+
+	flushErr := b.Flush()
+	closeErr := b.file.Close()
+
+	if flushErr != nil {
+		return flushErr
+	}
+
+	return closeErr
+}
+
+// Write writes data to file holding an advisory exclusive file lock.
+func (l *lockedFile) Write(data []byte) (int, error) {
+	if err := lockFile(l.File); err != nil {
+		return 0, NewRuntimeError("cannot lock file", err)
+	}
+
+	written, err := l.File.Write(data)
+
+	if unlockErr := unlockFile(l.File); (unlockErr != nil) && (err == nil) {
+		err = NewRuntimeError("cannot unlock file", unlockErr)
+	}
+
+	return written, err
+}
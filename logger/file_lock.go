@@ -0,0 +1,46 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile locks provided file by an advisory exclusive lock. It waits until
+// lock is acquired.
+func lockFile(file *os.File) error {
+	return flock(file, syscall.LOCK_EX)
+}
+
+// unlockFile unlocks provided file locked by the lockFile function.
+func unlockFile(file *os.File) error {
+	return flock(file, syscall.LOCK_UN)
+}
+
+// flock applies provided flock operation to file. It retries operation
+// interrupted by a signal.
+func flock(file *os.File, operation int) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), operation)
+
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func newFileLogger(test *testing.T) (log *logger.Logger, name string) {
+	name = filepath.Join(test.TempDir(), "test.log")
+
+	file := logger.NewFile().SetName(name)
+	file.GetFormatter().SetFormat("{message}")
+
+	return logger.New().SetHandler("file", file).SetSynchronous(true), name
+}
+
+func TestFileReopen(test *testing.T) {
+	log, name := newFileLogger(test)
+
+	defer log.CloseDefer()
+
+	log.Info("first")
+
+	if err := os.Rename(name, name+".1"); err != nil {
+		test.Fatal("Rename() returns an unexpected error", err)
+	}
+
+	log.Reopen().Info("second")
+
+	data, err := os.ReadFile(name)
+
+	if err != nil {
+		test.Fatal("ReadFile() returns an unexpected error", err)
+	}
+
+	if string(data) != "second\n" {
+		test.Errorf("file content = %q; want \"second\\n\"", data)
+	}
+}
+
+func TestFileReopenOnSignal(test *testing.T) {
+	log, name := newFileLogger(test)
+
+	defer log.CloseDefer()
+
+	log.ReopenOnSignal(syscall.SIGHUP).Info("first")
+
+	if err := os.Rename(name, name+".1"); err != nil {
+		test.Fatal("Rename() returns an unexpected error", err)
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+
+	if err != nil {
+		test.Fatal("FindProcess() returns an unexpected error", err)
+	}
+
+	if err = process.Signal(syscall.SIGHUP); err != nil {
+		test.Skip("cannot send signal", err)
+	}
+
+	for retry := 0; retry < 1000; retry++ {
+		log.Info("second")
+
+		if _, err = os.Stat(name); err == nil {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	test.Error("log file is not reopened after signal")
+}
+
+func TestFileEmitBatch(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "batch.log")
+
+	file := logger.NewFile().SetName(path)
+	file.GetFormatter().SetFormat("{message}")
+
+	records := []*logger.Record{
+		{Message: "first"},
+		{Message: "second"},
+	}
+
+	if err := file.EmitBatch(records); err != nil {
+		test.Fatal("EmitBatch() returns an unexpected error", err)
+	}
+
+	if err := file.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		test.Fatal("os.ReadFile() returns an unexpected error", err)
+	}
+
+	if want := "first\nsecond\n"; string(data) != want {
+		test.Errorf("file content = %q; want %q", data, want)
+	}
+}
+
+func TestFileSetWriteBuffer(test *testing.T) {
+	log, name := newFileLogger(test)
+
+	defer log.CloseDefer()
+
+	handler, err := log.GetHandler("file")
+
+	if err != nil {
+		test.Fatal("GetHandler() returns an unexpected error", err)
+	}
+
+	file := handler.(*logger.File) // nolint:forcetypeassert
+
+	file.SetWriteBuffer(4096).SetFlushInterval(0)
+
+	if got := file.GetWriteBuffer(); got != 4096 {
+		test.Errorf("GetWriteBuffer() = %d; want 4096", got)
+	}
+
+	log.Info("buffered")
+
+	data, err := os.ReadFile(name)
+
+	if err != nil {
+		test.Fatal("ReadFile() returns an unexpected error", err)
+	}
+
+	if len(data) != 0 {
+		test.Errorf("file content = %q; want empty", data)
+	}
+
+	if err = file.Sync(); err != nil {
+		test.Fatal("Sync() returns an unexpected error", err)
+	}
+
+	if data, _ = os.ReadFile(name); string(data) != "buffered\n" {
+		test.Errorf("file content = %q; want \"buffered\\n\"", data)
+	}
+
+	log.Info("flushed")
+	log.Flush()
+
+	if data, _ = os.ReadFile(name); string(data) != "buffered\nflushed\n" {
+		test.Errorf("file content = %q; want \"buffered\\nflushed\\n\"", data)
+	}
+
+	log.Info("closed")
+
+	if err = log.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	if data, _ = os.ReadFile(name); string(data) != "buffered\nflushed\nclosed\n" {
+		test.Errorf("file content = %q; want \"buffered\\nflushed\\nclosed\\n\"", data)
+	}
+}
+
+func TestFileSetFlushInterval(test *testing.T) {
+	name := filepath.Join(test.TempDir(), "interval.log")
+
+	file := logger.NewFile().SetName(name).SetWriteBuffer(4096).SetFlushInterval(time.Millisecond)
+	file.GetFormatter().SetFormat("{message}")
+
+	defer file.Close()
+
+	if err := file.Emit(&logger.Record{Message: "periodic"}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		if data, _ := os.ReadFile(name); string(data) == "periodic\n" {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	test.Error("write buffer is not flushed periodically")
+}
+
+func BenchmarkFileWriteBuffer(benchmark *testing.B) {
+	for _, size := range []int{0, 64 * 1024} {
+		size := size
+
+		name := "unbuffered"
+
+		if size > 0 {
+			name = "buffered"
+		}
+
+		benchmark.Run(name, func(benchmark *testing.B) {
+			file := logger.NewFile().SetName(filepath.Join(benchmark.TempDir(), "bench.log")).SetWriteBuffer(size)
+			file.GetFormatter().SetFormat("{message}")
+
+			record := &logger.Record{Message: "Message"}
+
+			benchmark.ReportAllocs()
+			benchmark.ResetTimer()
+
+			for index := 0; index < benchmark.N; index++ {
+				if err := file.Emit(record); err != nil {
+					benchmark.Fatal("Emit() returns an unexpected error", err)
+				}
+			}
+
+			if err := file.Close(); err != nil {
+				benchmark.Fatal("Close() returns an unexpected error", err)
+			}
+		})
+	}
+}
+
+const (
+	testLockingFile    = "LOGGER_TEST_LOCKING_FILE"
+	testLockingWriter  = "LOGGER_TEST_LOCKING_WRITER"
+	testLockingWriters = 4
+	testLockingRecords = 200
+	testLockingLength  = 16 * 1024
+)
+
+func TestFileSetLocking(test *testing.T) {
+	if name := os.Getenv(testLockingFile); name != "" {
+		writer := os.Getenv(testLockingWriter)
+
+		file := logger.NewFile().SetName(name).SetLocking(true)
+		file.GetFormatter().SetFormat("{message}")
+
+		log := logger.New().SetHandler("file", file).SetSynchronous(true)
+
+		defer log.CloseDefer()
+
+		for index := 0; index < testLockingRecords; index++ {
+			log.Info(writer + strings.Repeat(writer, testLockingLength))
+		}
+
+		return
+	}
+
+	name := filepath.Join(test.TempDir(), "locking.log")
+
+	commands := make([]*exec.Cmd, testLockingWriters)
+
+	for index := range commands {
+		commands[index] = exec.Command(os.Args[0], "-test.run=^TestFileSetLocking$") // nolint:gosec
+		commands[index].Env = append(os.Environ(), testLockingFile+"="+name,
+			testLockingWriter+"="+strconv.Itoa(index))
+
+		if err := commands[index].Start(); err != nil {
+			test.Fatal("command.Start() returns an unexpected error", err)
+		}
+	}
+
+	for _, command := range commands {
+		if err := command.Wait(); err != nil {
+			test.Fatal("command.Wait() returns an unexpected error", err)
+		}
+	}
+
+	file, err := os.Open(name) // nolint:gosec
+
+	if err != nil {
+		test.Fatal("Open() returns an unexpected error", err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 2*testLockingLength)
+
+	lines := 0
+
+	for ; scanner.Scan(); lines++ {
+		line := scanner.Text()
+
+		if (len(line) != testLockingLength+1) || (strings.Trim(line, line[:1]) != "") {
+			test.Fatalf("line %d is torn", lines)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		test.Fatal("scanner.Err() returns an unexpected error", err)
+	}
+
+	if want := testLockingWriters * testLockingRecords; lines != want {
+		test.Errorf("lines = %d; want %d", lines, want)
+	}
+}
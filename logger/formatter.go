@@ -20,18 +20,22 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"text/template"
+	"text/template/parse"
 	"time"
+	"unicode"
 )
 
 // These constants define default values for Formatter.
 const (
 	DefaultDateFormat  = "{year}-{month}-{day} {hour}:{minute}:{second},{millisecond}"
-	DefaultFormat      = "{date} - {Level | printf \"%-8s\"} - {file}:{line}:{function}(): {message}"
+	DefaultFormat      = "{date} - {levelPadded} - {file}:{line}:{function}(): {message}"
 	DefaultPlaceholder = "p"
+	DefaultLevelWidth  = 8
 
 	kilo       = 1e3
 	mega       = 1e6
@@ -41,6 +45,10 @@ const (
 // FormatterFuncs defines map of template functions.
 type FormatterFuncs map[string]interface{}
 
+// ReplaceAttrFunc defines function that rewrites key and value of named value
+// or log record attribute before output. Returned false drops it.
+type ReplaceAttrFunc func(key string, value interface{}) (string, interface{}, bool)
+
 // A Formatter represents a formatter object used by log handler to format log
 // message.
 type Formatter struct {
@@ -48,11 +56,22 @@ type Formatter struct {
 	dateFormat    string
 	template      *template.Template
 	placeholder   string
+	levelWidth    int
+	colors        bool
 	timeBuffer    *bytes.Buffer
 	formatBuffer  *bytes.Buffer
 	messageBuffer *bytes.Buffer
 	mutex         sync.RWMutex
 	usedArguments map[int]bool
+	usedFields    map[fieldPosition]bool
+	usedDefaults  map[string]bool
+	replaceAttr   ReplaceAttrFunc
+}
+
+// fieldPosition defines position of named value from the Fields log argument.
+type fieldPosition struct {
+	argument int
+	field    int
 }
 
 // NewFormatter creates a new Formatter object with default format settings.
@@ -62,6 +81,7 @@ func NewFormatter() *Formatter {
 		dateFormat:    DefaultDateFormat,
 		template:      template.New("").Delims("{", "}"),
 		placeholder:   DefaultPlaceholder,
+		levelWidth:    DefaultLevelWidth,
 		timeBuffer:    new(bytes.Buffer),
 		formatBuffer:  new(bytes.Buffer),
 		messageBuffer: new(bytes.Buffer),
@@ -78,6 +98,7 @@ func (f *Formatter) Reset() *Formatter {
 	f.format = DefaultFormat
 	f.dateFormat = DefaultDateFormat
 	f.placeholder = DefaultPlaceholder
+	f.levelWidth = DefaultLevelWidth
 
 	return f
 }
@@ -102,6 +123,74 @@ func (f *Formatter) GetPlaceholder() string {
 	return f.placeholder
 }
 
+// SetLevelWidth sets width of level name used by the levelPadded template
+// function. Shorter level names are padded with spaces and longer level names
+// are truncated to keep log message columns aligned. Width less than or equal
+// to zero disables padding and truncation.
+func (f *Formatter) SetLevelWidth(width int) *Formatter {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.levelWidth = width
+
+	return f
+}
+
+// GetLevelWidth returns width of level name used by the levelPadded template
+// function.
+func (f *Formatter) GetLevelWidth() int {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.levelWidth
+}
+
+// SetColors enables or disables ANSI colors returned by the levelColor and
+// colorReset template functions. With disabled colors they return empty
+// strings and log messages are formatted as plain text. The Stdout and Stderr
+// log handlers enable colors only when the IsColorSupported function returns
+// true for their output. It is not changed by the Reset method.
+func (f *Formatter) SetColors(enabled bool) *Formatter {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.colors = enabled
+
+	return f
+}
+
+// IsColors returns true if ANSI colors are enabled.
+func (f *Formatter) IsColors() bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.colors
+}
+
+// SetReplaceAttr sets function that rewrites named values appended or
+// prepended to log message as key=value pairs. It is also used by the
+// JSONEncoder and LogfmtEncoder encoders for every log record attribute, like
+// for example level or message, and for every named value from log arguments
+// and default fields. Returned false drops named value or attribute. Nil
+// function disables rewriting.
+func (f *Formatter) SetReplaceAttr(replaceAttr ReplaceAttrFunc) *Formatter {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.replaceAttr = replaceAttr
+
+	return f
+}
+
+// GetReplaceAttr returns function that rewrites named values and log record
+// attributes.
+func (f *Formatter) GetReplaceAttr() ReplaceAttrFunc {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.replaceAttr
+}
+
 // AddFuncs adds template functions to format log message.
 func (f *Formatter) AddFuncs(funcs FormatterFuncs) *Formatter {
 	f.mutex.Lock()
@@ -182,7 +271,15 @@ func (f *Formatter) FormatTime(record *Record) (string, error) {
 }
 
 // FormatMessage returns formatted user message string based on provided log
-// record object.
+// record object. Log arguments that are not used in log message are appended
+// to it separated by spaces. Named values from the Named or Fields log
+// arguments that are not used are appended as key=value pairs, sorted by key
+// for Named and in the insertion order for Fields. Structure log arguments
+// are never appended. Without structure log argument the first map log
+// argument with string keys is used for object placeholders, like for example
+// log arguments unpacked by the Record.FromJSON method. Default fields of log
+// record are available as named placeholders and default fields that are not
+// used are prepended to log message as key=value pairs sorted by key.
 func (f *Formatter) FormatMessage(record *Record) (string, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
@@ -199,6 +296,45 @@ func (f *Formatter) FormatMessage(record *Record) (string, error) {
 // formatMessageUnsafe returns formatted user message string based on provided log
 // record object.
 func (f *Formatter) formatMessageRecord(record *Record) (string, error) {
+	f.usedDefaults = make(map[string]bool)
+
+	message, err := f.formatArguments(record)
+
+	if err != nil {
+		return "", err
+	}
+
+	return f.prependDefaults(message, record.Fields), nil
+}
+
+// prependDefaults prepends default fields that are not used in log message as
+// key=value pairs sorted by key.
+func (f *Formatter) prependDefaults(message string, defaults Named) string {
+	fields, _ := getNamedFields(defaults)
+
+	unused := make(Fields, 0, len(fields))
+
+	for _, field := range fields {
+		if !f.usedDefaults[field.Key] {
+			unused = append(unused, field)
+		}
+	}
+
+	prefix := ""
+
+	for _, field := range replaceFields(unused, f.replaceAttr) {
+		prefix += field.String() + " "
+	}
+
+	if message == "" {
+		return strings.TrimSuffix(prefix, " ")
+	}
+
+	return prefix + message
+}
+
+// formatArguments returns formatted user message string with log arguments.
+func (f *Formatter) formatArguments(record *Record) (string, error) {
 	if len(record.Arguments) == 0 {
 		return record.Message, nil
 	}
@@ -210,71 +346,265 @@ func (f *Formatter) formatMessageRecord(record *Record) (string, error) {
 	message := record.Message
 
 	f.usedArguments = make(map[int]bool)
+	f.usedFields = make(map[fieldPosition]bool)
 
 	funcMap := make(template.FuncMap)
 
+	for key, value := range record.Fields {
+		if isIdentifier(key) {
+			funcMap[key] = f.defaultValue(key, value)
+		}
+	}
+
 	funcMap[f.placeholder] = f.argumentAutomatic(record)
 
+	named := make(map[int]Fields)
+
+	objectPosition := -1
+
 	for position, argument := range record.Arguments {
 		placeholder := f.placeholder + strconv.Itoa(position)
 
 		funcMap[placeholder] = f.argumentValue(position, argument)
 
-		valueOf := reflect.ValueOf(argument)
+		if fields, ok := getNamedFields(argument); ok {
+			named[position] = fields
 
-		switch valueOf.Kind() {
-		case reflect.Map:
-			if reflect.TypeOf(argument).Key().Kind() == reflect.String {
-				for _, key := range valueOf.MapKeys() {
-					funcMap[key.String()] = f.argumentValue(position, valueOf.MapIndex(key).Interface())
+			for index, field := range fields {
+				if isIdentifier(field.Key) {
+					funcMap[field.Key] = f.fieldValue(fieldPosition{position, index}, field.Value)
 				}
 			}
-		case reflect.Struct:
+
+			if _, ok := argument.(Fields); !ok && (objectPosition < 0) {
+				objectPosition = position
+			}
+		} else if reflect.ValueOf(argument).Kind() == reflect.Struct {
 			object = argument
 		}
 	}
 
-	if message, err = f.formatString(
-		template.New("").Delims("{", "}").Funcs(f.getRecordFuncs(record)).Funcs(funcMap),
-		f.messageBuffer,
-		message,
-		object,
-	); err != nil {
+	if object == nil && (objectPosition >= 0) {
+		object = record.Arguments[objectPosition]
+	} else {
+		objectPosition = -1
+	}
+
+	templ := template.New("").Delims("{", "}").Funcs(f.getRecordFuncs(record)).Funcs(funcMap)
+
+	if message, err = f.formatString(templ, f.messageBuffer, message, object); err != nil {
 		return "", err
 	}
 
+	if (objectPosition >= 0) && (templ.Tree != nil) && referencesObject(templ.Tree.Root) {
+		f.usedArguments[objectPosition] = true
+	}
+
 	if len(f.usedArguments) >= len(record.Arguments) {
 		return message, nil
 	}
 
 	for position, argument := range record.Arguments {
-		if !f.isArgumentUsed(position, argument) {
-			if message != "" {
-				message += " "
-			}
+		if f.isArgumentUsed(position, argument) {
+			continue
+		}
 
-			message += fmt.Sprint(argument)
+		if fields, ok := named[position]; ok {
+			message = f.appendFields(message, position, fields)
+			continue
 		}
+
+		if message != "" {
+			message += " "
+		}
+
+		message += fmt.Sprint(argument)
 	}
 
 	return message, nil
 }
 
-func (f *Formatter) isArgumentUsed(position int, argument interface{}) bool {
-	valueOf := reflect.ValueOf(argument)
+// appendFields appends named values from the Fields or Named log argument that
+// are not used in log message as key=value pairs.
+func (f *Formatter) appendFields(message string, position int, fields Fields) string {
+	unused := make(Fields, 0, len(fields))
+
+	for index, field := range fields {
+		if !f.usedFields[fieldPosition{position, index}] {
+			unused = append(unused, field)
+		}
+	}
+
+	for _, field := range replaceFields(unused, f.replaceAttr) {
+		if message != "" {
+			message += " "
+		}
+
+		message += field.String()
+	}
+
+	return message
+}
+
+// replaceFields returns named values rewritten by provided function. Named
+// values are returned unchanged if function is nil.
+func replaceFields(fields Fields, replaceAttr ReplaceAttrFunc) Fields {
+	if replaceAttr == nil {
+		return fields
+	}
+
+	replaced := make(Fields, 0, len(fields))
 
-	switch valueOf.Kind() {
-	case reflect.Map:
-		if reflect.TypeOf(argument).Key().Kind() == reflect.String {
-			return true
+	for _, field := range fields {
+		if key, value, ok := replaceAttr(field.Key, field.Value); ok {
+			replaced = append(replaced, Field{Key: key, Value: value})
 		}
-	case reflect.Struct:
+	}
+
+	return replaced
+}
+
+// replaceArguments returns log arguments with named values from the Named or
+// Fields log arguments rewritten by provided function. Rewritten log
+// arguments are returned as Fields.
+func replaceArguments(arguments Arguments, replaceAttr ReplaceAttrFunc) Arguments {
+	if (replaceAttr == nil) || (arguments == nil) {
+		return arguments
+	}
+
+	replaced := make(Arguments, len(arguments))
+
+	for position, argument := range arguments {
+		replaced[position] = argument
+
+		if fields, ok := getNamedFields(argument); ok {
+			replaced[position] = replaceFields(fields, replaceAttr)
+		}
+	}
+
+	return replaced
+}
+
+func (f *Formatter) isArgumentUsed(position int, argument interface{}) bool {
+	if reflect.ValueOf(argument).Kind() == reflect.Struct {
 		return true
 	}
 
 	return f.usedArguments[position]
 }
 
+// referencesObject returns true if provided parsed template node references
+// fields of the template object.
+func referencesObject(node parse.Node) bool { // nolint:gocyclo
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, child := range n.Nodes {
+				if referencesObject(child) {
+					return true
+				}
+			}
+		}
+	case *parse.ActionNode:
+		return referencesObject(n.Pipe)
+	case *parse.PipeNode:
+		if n != nil {
+			for _, command := range n.Cmds {
+				if referencesObject(command) {
+					return true
+				}
+			}
+		}
+	case *parse.CommandNode:
+		for _, argument := range n.Args {
+			if referencesObject(argument) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return referencesObject(n.Node)
+	case *parse.IfNode:
+		return referencesObject(&n.BranchNode)
+	case *parse.RangeNode:
+		return referencesObject(&n.BranchNode)
+	case *parse.WithNode:
+		return referencesObject(&n.BranchNode)
+	case *parse.BranchNode:
+		return referencesObject(n.Pipe) || referencesObject(n.List) || referencesObject(n.ElseList)
+	case *parse.FieldNode, *parse.DotNode:
+		return true
+	}
+
+	return false
+}
+
+// isIdentifier returns true if provided name can be used as a named
+// placeholder. Named values with other names, like for example "user-id" or
+// "http.method", are always appended to log message as key=value pairs.
+func isIdentifier(name string) bool {
+	for index, character := range name {
+		if (character != '_') && !unicode.IsLetter(character) && ((index == 0) || !unicode.IsDigit(character)) {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// getNamedFields returns named values from the Fields log argument passed by
+// value or by pointer or from a map log argument with string keys like Named. Named values from a map are
+// sorted by their keys.
+func getNamedFields(argument interface{}) (Fields, bool) {
+	switch fields := argument.(type) {
+	case Fields:
+		return fields, true
+	case *Fields:
+		if fields == nil {
+			return nil, false
+		}
+
+		return *fields, true
+	}
+
+	valueOf := reflect.ValueOf(argument)
+
+	if (valueOf.Kind() != reflect.Map) || (valueOf.Type().Key().Kind() != reflect.String) {
+		return nil, false
+	}
+
+	fields := make(Fields, 0, valueOf.Len())
+
+	for _, key := range valueOf.MapKeys() {
+		fields = append(fields, Field{
+			Key:   key.String(),
+			Value: valueOf.MapIndex(key).Interface(),
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+
+	return fields, true
+}
+
+// fieldValue returns closure that returns named value from the Fields log
+// argument used in log message.
+func (f *Formatter) fieldValue(position fieldPosition, value interface{}) func() interface{} {
+	return func() interface{} {
+		f.usedFields[position] = true
+		return value
+	}
+}
+
+// defaultValue returns closure that returns default field used in log message.
+func (f *Formatter) defaultValue(key string, value interface{}) func() interface{} {
+	return func() interface{} {
+		f.usedDefaults[key] = true
+		return value
+	}
+}
+
 // argumentValue returns closure that returns log argument used in log message.
 func (f *Formatter) argumentValue(position int, argument interface{}) func() interface{} {
 	return func() interface{} {
@@ -329,6 +659,26 @@ func (*Formatter) formatString(templ *template.Template, buffer *bytes.Buffer, f
 	return message, nil
 }
 
+// formatLabels returns log record labels as comma-separated key=value pairs
+// sorted by keys.
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+
+	for index, key := range keys {
+		pairs[index] = key + "=" + labels[key]
+	}
+
+	return strings.Join(pairs, ",")
+}
+
 // getRecordFuncs sets default template functions used to formatting log message.
 func (f *Formatter) getRecordFuncs(record *Record) template.FuncMap {
 	return template.FuncMap{
@@ -368,16 +718,33 @@ func (f *Formatter) getRecordFuncs(record *Record) template.FuncMap {
 			return strings.ToLower(record.Level.Name)
 		},
 		"Level": func() string {
-			return strings.Title(strings.ToLower(record.Level.Name))
+			return toTitle(record.Level.Name)
 		},
 		"LEVEL": func() string {
 			return strings.ToUpper(record.Level.Name)
 		},
+		"levelPadded": func() string {
+			return padLevelName(toTitle(record.Level.Name), f.levelWidth)
+		},
+		"levelColor": func() string {
+			if !f.colors {
+				return ""
+			}
+
+			return getLevelColor(record.Level.Value)
+		},
+		"colorReset": func() string {
+			if !f.colors {
+				return ""
+			}
+
+			return ColorReset
+		},
 		"iso8601": func() string {
 			return record.Time.Format(time.RFC3339)
 		},
 		"id": func() interface{} {
-			return record.ID
+			return record.GetID()
 		},
 		"name": func() string {
 			return record.Name
@@ -391,6 +758,19 @@ func (f *Formatter) getRecordFuncs(record *Record) template.FuncMap {
 		"address": func() string {
 			return record.Address
 		},
+		"duration": func() string {
+			return record.Duration.String()
+		},
+		"errorCode": func() interface{} {
+			if code, ok := record.GetErrorCode(); ok {
+				return code
+			}
+
+			return ""
+		},
+		"labels": func() string {
+			return formatLabels(record.Labels)
+		},
 		"nanosecond": func() string {
 			return fmt.Sprintf("%09d", record.Time.Nanosecond())
 		},
@@ -430,5 +810,43 @@ func (f *Formatter) getRecordFuncs(record *Record) template.FuncMap {
 		"function": func() string {
 			return record.File.Function
 		},
+		"package": func() string {
+			if record.File.Package != "" {
+				return record.File.Package
+			}
+
+			pkg, _ := splitFunction(record.File.Function)
+
+			return pkg
+		},
+		"funcName": func() string {
+			_, name := splitFunction(record.File.Function)
+			return name
+		},
+		"callerShort": func() string {
+			pkg, name := splitFunction(record.File.Function)
+
+			if pkg == "" {
+				return getShortFunction(name)
+			}
+
+			return getPackageName(pkg) + "." + getShortFunction(name)
+		},
 	}
 }
+
+// padLevelName returns level name padded with spaces or truncated to provided
+// width.
+func padLevelName(name string, width int) string {
+	if width <= 0 {
+		return name
+	}
+
+	runes := []rune(name)
+
+	if len(runes) >= width {
+		return string(runes[:width])
+	}
+
+	return name + strings.Repeat(" ", width-len(runes))
+}
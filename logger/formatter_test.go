@@ -15,6 +15,7 @@
 package logger_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"gitlab.com/tymonx/go-logger/logger"
@@ -127,7 +128,7 @@ func TestFormatterFormatMessageAutoAppend(test *testing.T) {
 
 	var message string
 
-	want := "Test message 5 4 hello world 0.3 <nil> " + testError.Error() + " [x y]"
+	want := "Test message 5 4 hello world 0.3 <nil> named1=3 named3=[1 2] " + testError.Error() + " [x y]"
 
 	record := &logger.Record{
 		Message: "Test message {named2}",
@@ -137,6 +138,7 @@ func TestFormatterFormatMessageAutoAppend(test *testing.T) {
 			0.3,
 			nil,
 			logger.Named{
+				"named3": []int{1, 2},
 				"named1": 3,
 				"named2": 5,
 			},
@@ -186,3 +188,248 @@ func TestFormatterFormatMessageErrors(test *testing.T) {
 		test.Error("FormatMessage() =", message, "; want", want)
 	}
 }
+
+func TestFormatterLevelPadded(test *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{name: "info", width: logger.DefaultLevelWidth, want: "[Info    ]"},
+		{name: "critical", width: logger.DefaultLevelWidth, want: "[Critical]"},
+		{name: "verbose-debug", width: logger.DefaultLevelWidth, want: "[Verbose-]"},
+		{name: "verbose-debug", width: 0, want: "[Verbose-Debug]"},
+		{name: "info", width: 2, want: "[In]"},
+	}
+
+	for _, tt := range tests {
+		record := &logger.Record{
+			Level: logger.Level{Name: tt.name},
+		}
+
+		formatter := logger.NewFormatter().SetFormat("[{levelPadded}]").SetLevelWidth(tt.width)
+
+		message, err := formatter.Format(record)
+
+		if err != nil {
+			test.Error("Format() returns an unexpected error", err)
+		}
+
+		if message != tt.want {
+			test.Errorf("Format() = %q; want %q", message, tt.want)
+		}
+	}
+}
+
+func TestFormatterFormatMessageFields(test *testing.T) {
+	fields := logger.Fields{
+		{Key: "zulu", Value: 1},
+		{Key: "alpha", Value: "two"},
+		{Key: "mike", Value: 3.5},
+		{Key: "bravo", Value: nil},
+	}
+
+	record := &logger.Record{
+		Message:   "Test message {alpha}",
+		Arguments: []interface{}{fields, 4},
+	}
+
+	want := "Test message two zulu=1 mike=3.5 bravo=<nil> 4"
+
+	formatter := logger.NewFormatter()
+
+	for run := 0; run < 100; run++ {
+		message, err := formatter.FormatMessage(record)
+
+		if err != nil {
+			test.Fatal("FormatMessage() returns an unexpected error", err)
+		}
+
+		if message != want {
+			test.Fatalf("FormatMessage() = %q; want %q", message, want)
+		}
+	}
+
+	data, err := json.Marshal(fields)
+
+	if err != nil {
+		test.Fatal("json.Marshal() returns an unexpected error", err)
+	}
+
+	if want := `{"zulu":1,"alpha":"two","mike":3.5,"bravo":null}`; string(data) != want {
+		test.Errorf("json.Marshal() = %s; want %s", data, want)
+	}
+}
+
+func TestFormatterFormatMessageAutoAppendNamed(test *testing.T) {
+	tests := []struct {
+		message   string
+		arguments []interface{}
+		want      string
+	}{
+		{"{b}", []interface{}{logger.Named{"c": 3, "b": 2, "a": 1}}, "2 a=1 c=3"},
+		{"{a} {b} {c}", []interface{}{logger.Named{"c": 3, "b": 2, "a": 1}}, "1 2 3"},
+		{"{p0}", []interface{}{logger.Named{"a": 1}}, "map[a:1]"},
+		{"", []interface{}{logger.Named{}}, ""},
+		{"{p} {y}", []interface{}{1, logger.Named{"y": 2, "x": 3}, 4}, "1 2 x=3 4"},
+		{"{p1}", []interface{}{1, 2, logger.Named{"z": 5}, logger.Fields{{Key: "q", Value: 6}}}, "2 1 z=5 q=6"},
+		{"{.Name} {k}", []interface{}{struct{ Name string }{"object"}, map[string]string{"k": "v", "j": "w"}}, "object v j=w"},
+	}
+
+	formatter := logger.NewFormatter()
+
+	for _, tt := range tests {
+		message, err := formatter.FormatMessage(&logger.Record{
+			Message:   tt.message,
+			Arguments: tt.arguments,
+		})
+
+		if err != nil {
+			test.Errorf("FormatMessage(%q) returns an unexpected error %v", tt.message, err)
+		}
+
+		if message != tt.want {
+			test.Errorf("FormatMessage(%q) = %q; want %q", tt.message, message, tt.want)
+		}
+	}
+}
+
+func TestFormatterFormatMessageDefaultFields(test *testing.T) {
+	formatter := logger.NewFormatter()
+
+	for _, entry := range []struct {
+		record *logger.Record
+		want   string
+	}{
+		{
+			record: &logger.Record{
+				Message: "Started",
+				Fields:  logger.Named{"service": "api", "version": "1.0"},
+			},
+			want: "service=api version=1.0 Started",
+		},
+		{
+			record: &logger.Record{
+				Message:   "Started {service} {p}",
+				Arguments: logger.Arguments{1},
+				Fields:    logger.Named{"service": "api", "version": "1.0"},
+			},
+			want: "version=1.0 Started api 1",
+		},
+		{
+			record: &logger.Record{
+				Message:   "Started {service}",
+				Arguments: logger.Arguments{logger.Named{"service": "db"}},
+				Fields:    logger.Named{"service": "api"},
+			},
+			want: "service=api Started db",
+		},
+	} {
+		message, err := formatter.FormatMessage(entry.record)
+
+		if err != nil {
+			test.Fatal("FormatMessage() returns an unexpected error", err)
+		}
+
+		if message != entry.want {
+			test.Errorf("FormatMessage() = %q; want %q", message, entry.want)
+		}
+	}
+}
+
+func TestFormatterFormatMessageNamedKeys(test *testing.T) {
+	message, err := logger.NewFormatter().FormatMessage(&logger.Record{
+		Message:   "Request {user_id}",
+		Arguments: logger.Arguments{logger.Fields{{"user_id", 7}, {"http.method", "GET"}, {"trace-id", "a1"}}},
+	})
+
+	if err != nil {
+		test.Fatal("FormatMessage() returns an unexpected error", err)
+	}
+
+	if want := "Request 7 http.method=GET trace-id=a1"; message != want {
+		test.Errorf("FormatMessage() = %q; want %q", message, want)
+	}
+}
+
+func TestFormatterSetReplaceAttr(test *testing.T) {
+	formatter := logger.NewFormatter().SetReplaceAttr(func(key string, value interface{}) (string, interface{}, bool) {
+		switch key {
+		case "password":
+			return key, value, false
+		case "user":
+			return "username", value, true
+		}
+
+		return key, value, true
+	})
+
+	message, err := formatter.FormatMessage(&logger.Record{
+		Message:   "Login",
+		Arguments: logger.Arguments{logger.Fields{{"user", "admin"}, {"password", "secret"}}},
+		Fields:    logger.Named{"password": "default", "service": "api"},
+	})
+
+	if err != nil {
+		test.Fatal("FormatMessage() returns an unexpected error", err)
+	}
+
+	if want := "service=api Login username=admin"; message != want {
+		test.Errorf("FormatMessage() = %q; want %q", message, want)
+	}
+}
+
+func TestFormatterCallerNames(test *testing.T) {
+	for _, tt := range []struct {
+		source logger.Source
+		want   string
+	}{
+		{
+			logger.Source{Function: "gitlab.com/tymonx/go-logger/logger_test.Example"},
+			"gitlab.com/tymonx/go-logger/logger_test Example logger_test.Example",
+		},
+		{
+			logger.Source{Function: "example.com/a.b/pkg.(*Type).Method"},
+			"example.com/a.b/pkg (*Type).Method pkg.(*Type).Method",
+		},
+		{
+			logger.Source{Function: "example.com/pkg.(*Type).Method.func1.2"},
+			"example.com/pkg (*Type).Method.func1.2 pkg.(*Type).Method",
+		},
+		{
+			logger.Source{Function: "pkg.Map[...].func1"},
+			"pkg Map[...].func1 pkg.Map[...]",
+		},
+		{
+			logger.Source{Function: "pkg.Func.func3", Package: "example.com/pkg"},
+			"example.com/pkg Func.func3 pkg.Func",
+		},
+	} {
+		record := &logger.Record{File: tt.source}
+
+		message, err := logger.NewFormatter().SetFormat("{package} {funcName} {callerShort}").Format(record)
+
+		if err != nil {
+			test.Error("Format() returns an unexpected error", err)
+		}
+
+		if message != tt.want {
+			test.Errorf("Format(%q) = %q; want %q", tt.source.Function, message, tt.want)
+		}
+	}
+
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{package} {funcName} {callerShort}")
+
+	log := logger.New().SetSynchronous(true).SetHandler("buffer", buffer)
+
+	func() {
+		log.Info(testMessage)
+	}()
+
+	want := "gitlab.com/tymonx/go-logger/logger_test TestFormatterCallerNames.func1 logger_test.TestFormatterCallerNames\n"
+
+	if buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
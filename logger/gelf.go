@@ -0,0 +1,564 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandlerFactory("gelf", newGELFFromOptions)
+}
+
+// These constants define default values for GELF.
+const (
+	DefaultGELFPort      = 12201
+	DefaultGELFNetwork   = "udp"
+	DefaultGELFAddress   = "localhost"
+	DefaultGELFChunkSize = 1420
+	GELFVersion          = "1.1"
+)
+
+// These constants define GELF chunking over UDP.
+const (
+	gelfChunkHeaderSize = 12
+	gelfMaximumChunks   = 128
+)
+
+// A GELFEncoder represents an encoder that encodes log records in the GELF
+// (Graylog Extended Log Format) JSON format. Log level is encoded as syslog
+// severity like for the Syslog log handler, short_message is formatted log
+// message and full_message is log record formatted with formatter format.
+// Logger name, source location, log record ID, labels and named values are
+// encoded as additional fields prefixed with underscore. Named values are
+// rewritten by function set by the Formatter.SetReplaceAttr method. Characters
+// not allowed in names of additional fields are replaced with underscore and
+// the _id additional field reserved by GELF is skipped.
+type GELFEncoder struct {
+	formatter *Formatter
+}
+
+// A GELF represents a log handler object for logging messages to Graylog
+// server in the GELF format over UDP with chunking or over TCP with null byte
+// delimited messages.
+type GELF struct {
+	port      int
+	network   string
+	address   string
+	chunkSize int
+	stream    *Stream
+}
+
+// gelfChunkWriter represents UDP connection that splits written GELF messages
+// larger than chunk size into GELF chunks.
+type gelfChunkWriter struct {
+	net.Conn
+	chunkSize int
+}
+
+// NewGELFEncoder creates a new GELFEncoder object that formats log messages
+// using provided formatter. Nil formatter is replaced with a new default
+// formatter.
+func NewGELFEncoder(formatter *Formatter) *GELFEncoder {
+	if formatter == nil {
+		formatter = NewFormatter()
+	}
+
+	return &GELFEncoder{
+		formatter: formatter,
+	}
+}
+
+// GetFormatter returns formatter used to format log messages.
+func (e *GELFEncoder) GetFormatter() *Formatter {
+	return e.formatter
+}
+
+// Encode encodes provided log record in the GELF format.
+func (e *GELFEncoder) Encode(record *Record) ([]byte, error) {
+	message, err := e.formatter.FormatMessage(record)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot format message", err)
+	}
+
+	full, err := e.formatter.Format(record)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot format record", err)
+	}
+
+	attributes := Fields{
+		{Key: "version", Value: GELFVersion},
+		{Key: "host", Value: record.Hostname},
+		{Key: "short_message", Value: message},
+		{Key: "full_message", Value: full},
+		{Key: "timestamp", Value: float64(record.Time.UnixMicro()) / float64(time.Second/time.Microsecond)},
+		{Key: "level", Value: GetLevelSeverity(record.Level.Value)},
+	}
+
+	return append(attributes, e.getAdditionalFields(record)...).MarshalJSON()
+}
+
+// getAdditionalFields returns sorted GELF additional fields with names
+// prefixed with underscore. Named values override other additional fields.
+func (e *GELFEncoder) getAdditionalFields(record *Record) Fields {
+	additional := map[string]interface{}{
+		"name": record.Name,
+	}
+
+	if id := record.GetID(); id != "" {
+		additional["record_id"] = id
+	}
+
+	if record.File.Line != 0 {
+		additional["file"] = record.File.Name
+		additional["line"] = record.File.Line
+		additional["function"] = record.File.Function
+	}
+
+	for key, value := range record.Labels {
+		additional[key] = value
+	}
+
+	fields, _ := getNamedFields(record.Fields)
+
+	for _, field := range replaceFields(fields, e.formatter.GetReplaceAttr()) {
+		additional[field.Key] = field.Value
+	}
+
+	keys := make([]string, 0, len(additional))
+
+	for key := range additional {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	attributes := make(Fields, 0, len(keys))
+
+	for _, key := range keys {
+		if name := getGELFFieldName(key); name != "_id" {
+			attributes = append(attributes, Field{Key: name, Value: getGELFFieldValue(additional[key])})
+		}
+	}
+
+	return attributes
+}
+
+// getGELFFieldName returns GELF additional field name prefixed with underscore
+// with characters not allowed by GELF replaced with underscore.
+func getGELFFieldName(key string) string {
+	return "_" + strings.Map(func(character rune) rune {
+		switch {
+		case (character >= 'a') && (character <= 'z'),
+			(character >= 'A') && (character <= 'Z'),
+			(character >= '0') && (character <= '9'),
+			(character == '_'), (character == '.'), (character == '-'):
+			return character
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// getGELFFieldValue returns GELF additional field value. GELF supports only
+// strings and numbers and other values are converted to strings.
+func getGELFFieldValue(value interface{}) interface{} {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, ok := value.(fmt.Stringer); !ok {
+			return value
+		}
+	}
+
+	return fmt.Sprint(value)
+}
+
+// NewGELF creates a new GELF log handler object.
+func NewGELF() *GELF {
+	g := &GELF{
+		port:      DefaultGELFPort,
+		network:   DefaultGELFNetwork,
+		address:   DefaultGELFAddress,
+		chunkSize: DefaultGELFChunkSize,
+		stream:    NewStream(),
+	}
+
+	g.stream.SetEncoder(NewGELFEncoder(g.stream.GetFormatter()))
+	g.stream.SetLineEnding(getGELFDelimiter(g.network))
+	g.stream.SetOpener(g)
+
+	return g
+}
+
+// newGELFFromOptions creates a new GELF log handler object configured with
+// provided options: "address", "network", "port", "chunkSize", "writeTimeout",
+// "keepAlive", "retryAttempts", "retryBackoff" and "retryTimeout".
+func newGELFFromOptions(options HandlerOptions) (Handler, error) {
+	g := NewGELF()
+	o := newHandlerOptions(options)
+
+	o.setString("address", func(address string) { g.SetAddress(address) })
+	o.setString("network", func(network string) { g.SetNetwork(network) })
+	o.setInt("port", func(port int) { g.SetPort(port) })
+	o.setInt("chunkSize", func(size int) { g.SetChunkSize(size) })
+	o.setDuration("writeTimeout", func(timeout time.Duration) { g.SetWriteTimeout(timeout) })
+	o.setDuration("keepAlive", func(period time.Duration) { g.SetKeepAlive(period) })
+	o.setRetry(g.stream)
+
+	if err := o.err(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// getGELFDelimiter returns delimiter of GELF messages for provided network.
+// GELF messages sent over UDP are not delimited.
+func getGELFDelimiter(network string) string {
+	if strings.HasPrefix(network, "udp") {
+		return ""
+	}
+
+	return "\x00"
+}
+
+// Open opens new connection to Graylog server. It is called by stream when
+// the first log record is emitted or after reopening.
+func (g *GELF) Open() (io.WriteCloser, error) {
+	conn, err := net.Dial(g.network, net.JoinHostPort(g.address, strconv.Itoa(g.port)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(g.network, "udp") {
+		return &gelfChunkWriter{Conn: conn, chunkSize: g.chunkSize}, nil
+	}
+
+	return conn, nil
+}
+
+// SetHandlerName sets log handler name.
+func (g *GELF) SetHandlerName(name string) Handler {
+	g.stream.SetHandlerName(name)
+	return g
+}
+
+// GetHandlerName returns log handler name.
+func (g *GELF) GetHandlerName() string {
+	return g.stream.GetHandlerName()
+}
+
+// Enable enables log handler.
+func (g *GELF) Enable() Handler {
+	return g.stream.Enable()
+}
+
+// Disable disabled log handler.
+func (g *GELF) Disable() Handler {
+	return g.stream.Disable()
+}
+
+// IsEnabled returns if log handler is enabled.
+func (g *GELF) IsEnabled() bool {
+	return g.stream.IsEnabled()
+}
+
+// SetFormatter sets Formatter used to format GELF messages.
+func (g *GELF) SetFormatter(formatter *Formatter) Handler {
+	g.stream.SetFormatter(formatter)
+	g.stream.SetEncoder(NewGELFEncoder(formatter))
+
+	return g
+}
+
+// GetFormatter returns Formatter.
+func (g *GELF) GetFormatter() *Formatter {
+	return g.stream.GetFormatter()
+}
+
+// SetLevel sets log level.
+func (g *GELF) SetLevel(level int) Handler {
+	return g.stream.SetLevel(level)
+}
+
+// SetMinimumLevel sets minimum log level.
+func (g *GELF) SetMinimumLevel(level int) Handler {
+	return g.stream.SetMinimumLevel(level)
+}
+
+// GetMinimumLevel returns minimum log level.
+func (g *GELF) GetMinimumLevel() int {
+	return g.stream.GetMinimumLevel()
+}
+
+// SetMaximumLevel sets maximum log level.
+func (g *GELF) SetMaximumLevel(level int) Handler {
+	return g.stream.SetMaximumLevel(level)
+}
+
+// GetMaximumLevel returns maximum log level.
+func (g *GELF) GetMaximumLevel() int {
+	return g.stream.GetMaximumLevel()
+}
+
+// SetLevelRange sets minimum and maximum log level values.
+func (g *GELF) SetLevelRange(min, max int) Handler {
+	return g.stream.SetLevelRange(min, max)
+}
+
+// GetLevelRange returns minimum and maximum log level values.
+func (g *GELF) GetLevelRange() (min, max int) {
+	return g.stream.GetLevelRange()
+}
+
+// SetPort sets port number that is used to communicate with Graylog server.
+func (g *GELF) SetPort(port int) *GELF {
+	g.stream.Lock()
+	defer g.stream.Unlock()
+
+	if port <= 0 {
+		port = DefaultGELFPort
+	}
+
+	if g.port != port {
+		g.port = port
+		g.stream.reopen = true
+	}
+
+	return g
+}
+
+// GetPort returns port number that is used to communicate with Graylog server.
+func (g *GELF) GetPort() int {
+	g.stream.RLock()
+	defer g.stream.RUnlock()
+
+	return g.port
+}
+
+// SetNetwork sets network type "udp" or "tcp" that is used to communicate
+// with Graylog server. GELF messages are sent over UDP in chunks and over TCP
+// they are delimited with null byte.
+func (g *GELF) SetNetwork(network string) *GELF {
+	g.stream.Lock()
+	defer g.stream.Unlock()
+
+	if network == "" {
+		network = DefaultGELFNetwork
+	}
+
+	if g.network != network {
+		g.network = network
+		g.stream.lineEnding = getGELFDelimiter(network)
+		g.stream.reopen = true
+	}
+
+	return g
+}
+
+// GetNetwork returns network type that is used to communicate with Graylog
+// server.
+func (g *GELF) GetNetwork() string {
+	g.stream.RLock()
+	defer g.stream.RUnlock()
+
+	return g.network
+}
+
+// SetAddress sets IP address or hostname that is used to communicate with
+// Graylog server.
+func (g *GELF) SetAddress(address string) *GELF {
+	g.stream.Lock()
+	defer g.stream.Unlock()
+
+	if address == "" {
+		address = DefaultGELFAddress
+	}
+
+	if g.address != address {
+		g.address = address
+		g.stream.reopen = true
+	}
+
+	return g
+}
+
+// GetAddress returns IP address or hostname that is used to communicate with
+// Graylog server.
+func (g *GELF) GetAddress() string {
+	g.stream.RLock()
+	defer g.stream.RUnlock()
+
+	return g.address
+}
+
+// SetChunkSize sets maximum size of UDP datagrams. Larger GELF messages are
+// split into up to 128 GELF chunks. Chunk size not greater than the 12 bytes
+// of GELF chunk header restores DefaultGELFChunkSize.
+func (g *GELF) SetChunkSize(size int) *GELF {
+	g.stream.Lock()
+	defer g.stream.Unlock()
+
+	if size <= gelfChunkHeaderSize {
+		size = DefaultGELFChunkSize
+	}
+
+	if g.chunkSize != size {
+		g.chunkSize = size
+		g.stream.reopen = true
+	}
+
+	return g
+}
+
+// GetChunkSize returns maximum size of UDP datagrams.
+func (g *GELF) GetChunkSize() int {
+	g.stream.RLock()
+	defer g.stream.RUnlock()
+
+	return g.chunkSize
+}
+
+// SetWriteTimeout sets write timeout of connection like the Stream
+// SetWriteTimeout method.
+func (g *GELF) SetWriteTimeout(timeout time.Duration) *GELF {
+	g.stream.SetWriteTimeout(timeout)
+
+	return g
+}
+
+// GetWriteTimeout returns write timeout of connection.
+func (g *GELF) GetWriteTimeout() time.Duration {
+	return g.stream.GetWriteTimeout()
+}
+
+// SetKeepAlive sets TCP keep-alive period of connection like the Stream
+// SetKeepAlive method.
+func (g *GELF) SetKeepAlive(period time.Duration) *GELF {
+	g.stream.SetKeepAlive(period)
+
+	return g
+}
+
+// GetKeepAlive returns TCP keep-alive period of connection.
+func (g *GELF) GetKeepAlive() time.Duration {
+	return g.stream.GetKeepAlive()
+}
+
+// SetRetry sets number of retry attempts of failed writes and initial backoff
+// duration between them like the Stream SetRetry method. Connection to Graylog server
+// is opened again before every attempt.
+func (g *GELF) SetRetry(attempts int, backoff time.Duration) *GELF {
+	g.stream.SetRetry(attempts, backoff)
+
+	return g
+}
+
+// GetRetry returns number of retry attempts of failed writes and initial
+// backoff duration between them.
+func (g *GELF) GetRetry() (attempts int, backoff time.Duration) {
+	return g.stream.GetRetry()
+}
+
+// SetRetryTimeout sets maximum total duration of retrying failed write like
+// the Stream SetRetryTimeout method.
+func (g *GELF) SetRetryTimeout(timeout time.Duration) *GELF {
+	g.stream.SetRetryTimeout(timeout)
+
+	return g
+}
+
+// GetRetryTimeout returns maximum total duration of retrying failed write.
+func (g *GELF) GetRetryTimeout() time.Duration {
+	return g.stream.GetRetryTimeout()
+}
+
+// Reopen reconnects to Graylog server before emitting the next log record.
+func (g *GELF) Reopen() Handler {
+	g.stream.Reopen()
+	return g
+}
+
+// Emit logs messages from Logger to Graylog server.
+func (g *GELF) Emit(record *Record) error {
+	return g.stream.Emit(record)
+}
+
+// Profile returns time spent on formatting and writing of log records like
+// the Stream Profile method.
+func (g *GELF) Profile() Profile {
+	return g.stream.Profile()
+}
+
+// Close closes communication to Graylog server.
+func (g *GELF) Close() error {
+	return g.stream.Close()
+}
+
+// Write writes provided GELF message. GELF message larger than chunk size is
+// written in GELF chunks with the same random message ID.
+func (w *gelfChunkWriter) Write(data []byte) (int, error) {
+	if len(data) <= w.chunkSize {
+		return w.Conn.Write(data)
+	}
+
+	size := w.chunkSize - gelfChunkHeaderSize
+	count := (len(data) + size - 1) / size
+
+	if count > gelfMaximumChunks {
+		return 0, NewRuntimeError("cannot write GELF message, message is too long", len(data))
+	}
+
+	var id [8]byte
+
+	if _, err := io.ReadFull(rand.Reader, id[:]); err != nil {
+		return 0, NewRuntimeError("cannot generate GELF message ID", err)
+	}
+
+	chunk := make([]byte, 0, w.chunkSize)
+
+	for index := 0; index < count; index++ {
+		begin, end := index*size, (index+1)*size
+
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(index), byte(count))
+		chunk = append(chunk, data[begin:end]...)
+
+		if _, err := w.Conn.Write(chunk); err != nil {
+			return begin, err
+		}
+	}
+
+	return len(data), nil
+}
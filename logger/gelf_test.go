@@ -0,0 +1,225 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestGELFEncoder(test *testing.T) {
+	encoder := logger.NewGELFEncoder(nil)
+
+	encoder.GetFormatter().SetFormat("{level}: {message}")
+
+	record := &logger.Record{
+		ID:       "abc",
+		Name:     "app",
+		Time:     time.Unix(1583305567, 125000000),
+		Hostname: "host",
+		Message:  "Hello",
+		Level:    logger.Level{Value: logger.ErrorLevel, Name: logger.ErrorName},
+		Labels:   map[string]string{"env": "prod"},
+		Fields: logger.Named{
+			"user":    "bob",
+			"count":   3,
+			"id":      "reserved",
+			"bad key": true,
+			"elapsed": time.Second,
+		},
+	}
+
+	data, err := encoder.Encode(record)
+
+	if err != nil {
+		test.Fatal("Encode() returns an unexpected error", err)
+	}
+
+	var message map[string]interface{}
+
+	if err = json.Unmarshal(data, &message); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	want := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "host",
+		"short_message": "bad key=true count=3 elapsed=1s id=reserved user=bob Hello",
+		"full_message":  "error: bad key=true count=3 elapsed=1s id=reserved user=bob Hello",
+		"timestamp":     1583305567.125,
+		"level":         3.0,
+		"_name":         "app",
+		"_record_id":    "abc",
+		"_env":          "prod",
+		"_user":         "bob",
+		"_count":        3.0,
+		"_bad_key":      "true",
+		"_elapsed":      "1s",
+	}
+
+	if len(message) != len(want) {
+		test.Errorf("Encode() = %s; want %d fields", data, len(want))
+	}
+
+	for key, value := range want {
+		if message[key] != value {
+			test.Errorf("Encode()[%q] = %v; want %v", key, message[key], value)
+		}
+	}
+
+	if !bytes.HasPrefix(data, []byte(`{"version":"1.1","host":"host","short_message":`)) {
+		test.Errorf("Encode() = %s; want GELF fields first", data)
+	}
+}
+
+func TestGELFUDP(test *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		test.Skip("cannot listen on UDP", err)
+	}
+
+	defer conn.Close()
+
+	gelf := logger.NewGELF().SetAddress("127.0.0.1").SetPort(conn.LocalAddr().(*net.UDPAddr).Port).SetChunkSize(64)
+
+	defer gelf.Close()
+
+	gelf.GetFormatter().SetFormat("{message}")
+
+	long := strings.Repeat("x", 300)
+
+	if err = gelf.Emit(&logger.Record{Message: long}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	var chunks [][]byte
+
+	buffer := make([]byte, 128)
+
+	for count := 1; len(chunks) < count; {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		length, _, err := conn.ReadFrom(buffer)
+
+		if err != nil {
+			test.Fatal("ReadFrom() returns an unexpected error", err)
+		}
+
+		chunk := append([]byte(nil), buffer[:length]...)
+
+		if (length > 64) || (chunk[0] != 0x1e) || (chunk[1] != 0x0f) {
+			test.Fatalf("chunk = %q; want GELF chunk not larger than 64 bytes", chunk)
+		}
+
+		count = int(chunk[11])
+		chunks = append(chunks, chunk)
+	}
+
+	data := make([][]byte, len(chunks))
+
+	for _, chunk := range chunks {
+		if !bytes.Equal(chunk[2:10], chunks[0][2:10]) {
+			test.Fatal("GELF chunks have different message IDs")
+		}
+
+		data[chunk[10]] = chunk[12:]
+	}
+
+	var message map[string]interface{}
+
+	if err = json.Unmarshal(bytes.Join(data, nil), &message); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if message["short_message"] != long {
+		test.Errorf("short_message = %v; want %q", message["short_message"], long)
+	}
+}
+
+func TestGELFTCP(test *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		test.Skip("cannot listen on TCP", err)
+	}
+
+	defer listener.Close()
+
+	received := make(chan string, 2)
+
+	go func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+
+		for {
+			message, err := reader.ReadString(0)
+
+			if err != nil {
+				return
+			}
+
+			received <- message
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	handler, err := logger.CreateHandlerWithOptions("gelf", logger.HandlerOptions{
+		"network": "tcp",
+		"address": "127.0.0.1",
+		"port":    strconv.Itoa(port),
+	})
+
+	if err != nil {
+		test.Fatal("CreateHandlerWithOptions() returns an unexpected error", err)
+	}
+
+	defer handler.Close()
+
+	handler.GetFormatter().SetFormat("{message}")
+
+	for _, text := range []string{"first", "second"} {
+		if err = handler.Emit(&logger.Record{Message: text}); err != nil {
+			test.Fatal("Emit() returns an unexpected error", err)
+		}
+	}
+
+	for _, text := range []string{"first", "second"} {
+		select {
+		case message := <-received:
+			if !strings.HasSuffix(message, "\x00") || !strings.Contains(message, `"short_message":"`+text+`"`) {
+				test.Errorf("message = %q; want null byte delimited %q", message, text)
+			}
+		case <-time.After(5 * time.Second):
+			test.Fatal("GELF message is not received")
+		}
+	}
+}
@@ -15,8 +15,12 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"io"
 	"os"
 	"sync"
+	"time"
 )
 
 var gOnce sync.Once   // nolint:gochecknoglobals
@@ -51,6 +55,23 @@ func SetLevel(level int) *Logger {
 	return Get().SetLevel(level)
 }
 
+// SetLoggerLevel sets logger level. Log messages with log level lower than
+// logger level are dropped by the calling goroutine before creating log
+// record.
+func SetLoggerLevel(level int) *Logger {
+	return Get().SetLoggerLevel(level)
+}
+
+// GetLoggerLevel returns logger level.
+func GetLoggerLevel() int {
+	return Get().GetLoggerLevel()
+}
+
+// ResetLoggerLevel unsets logger level.
+func ResetLoggerLevel() *Logger {
+	return Get().ResetLoggerLevel()
+}
+
 // SetMinimumLevel sets minimum log level to all added log handlers.
 func SetMinimumLevel(level int) *Logger {
 	return Get().SetMinimumLevel(level)
@@ -66,6 +87,11 @@ func SetLevelRange(min, max int) *Logger {
 	return Get().SetLevelRange(min, max)
 }
 
+// GetLevelRange returns union of log level ranges of all enabled log handlers.
+func GetLevelRange() (min, max int) {
+	return Get().GetLevelRange()
+}
+
 // SetFormatter sets provided formatter to all added log handlers.
 func SetFormatter(formatter *Formatter) *Logger {
 	return Get().SetFormatter(formatter)
@@ -76,6 +102,16 @@ func SetFormat(format string) *Logger {
 	return Get().SetFormat(format)
 }
 
+// SetDefaultFormat sets default format string for log handlers added later.
+func SetDefaultFormat(format string) *Logger {
+	return Get().SetDefaultFormat(format)
+}
+
+// GetDefaultFormat returns default format string for log handlers added later.
+func GetDefaultFormat() string {
+	return Get().GetDefaultFormat()
+}
+
 // SetDateFormat sets provided date format string to all added log handlers.
 func SetDateFormat(format string) *Logger {
 	return Get().SetDateFormat(format)
@@ -96,6 +132,12 @@ func ResetFormatters() *Logger {
 	return Get().ResetFormatters()
 }
 
+// SetAddress sets IP address set in log records instead of cached local IP
+// address.
+func SetAddress(address string) *Logger {
+	return Get().SetAddress(address)
+}
+
 // SetErrorCode sets error code that is returned during Fatal call.
 // On default it is 1.
 func SetErrorCode(errorCode int) *Logger {
@@ -107,6 +149,124 @@ func GetErrorCode() int {
 	return Get().GetErrorCode()
 }
 
+// SetExitFunc sets function called by the Fatal method to exit the
+// application. On default it is os.Exit. Setting it to nil disables exiting
+// the application.
+func SetExitFunc(exitFunc ExitFunc) *Logger {
+	return Get().SetExitFunc(exitFunc)
+}
+
+// GetExitFunc returns function called by the Fatal method to exit the
+// application.
+func GetExitFunc() ExitFunc {
+	return Get().GetExitFunc()
+}
+
+// SetCallerSkip sets number of additional stack frames to skip when logger
+// captures source location of log message. It is useful for wrapper packages
+// that call logger methods on behalf of the real caller.
+func SetCallerSkip(delta int) *Logger {
+	return Get().SetCallerSkip(delta)
+}
+
+// GetCallerSkip returns number of additional stack frames to skip when logger
+// captures source location of log message.
+func GetCallerSkip() int {
+	return Get().GetCallerSkip()
+}
+
+// SetOverflowPolicy sets behavior of logger when logger worker thread queue
+// is full.
+func SetOverflowPolicy(policy OverflowPolicy) *Logger {
+	return Get().SetOverflowPolicy(policy)
+}
+
+// GetOverflowPolicy returns behavior of logger when logger worker thread
+// queue is full.
+func GetOverflowPolicy() OverflowPolicy {
+	return Get().GetOverflowPolicy()
+}
+
+// SetEnqueueTimeout sets maximum duration for which logging blocks when
+// logger worker thread queue is full.
+func SetEnqueueTimeout(timeout time.Duration) *Logger {
+	return Get().SetEnqueueTimeout(timeout)
+}
+
+// GetEnqueueTimeout returns maximum duration for which logging blocks when
+// logger worker thread queue is full.
+func GetEnqueueTimeout() time.Duration {
+	return Get().GetEnqueueTimeout()
+}
+
+// LevelCounts returns number of log records emitted by logger per log level
+// value.
+func LevelCounts() map[int]uint64 {
+	return Get().LevelCounts()
+}
+
+// ResetLevelCounts sets numbers of log records emitted by logger per log level
+// value to zero.
+func ResetLevelCounts() *Logger {
+	return Get().ResetLevelCounts()
+}
+
+// SetFlushLevel sets flush level. Logging message with log level greater than
+// or equal to flush level flushes all log messages before returning.
+func SetFlushLevel(level int) *Logger {
+	return Get().SetFlushLevel(level)
+}
+
+// GetFlushLevel returns flush level.
+func GetFlushLevel() int {
+	return Get().GetFlushLevel()
+}
+
+// SetCaptureCaller enables or disables capturing of source location of log
+// message.
+func SetCaptureCaller(capture bool) *Logger {
+	return Get().SetCaptureCaller(capture)
+}
+
+// IsCallerCaptured returns true if capturing of source location of log
+// message is enabled.
+func IsCallerCaptured() bool {
+	return Get().IsCallerCaptured()
+}
+
+// SetSynchronous enables or disables synchronous mode. In synchronous mode
+// log records are formatted and emitted directly by the calling goroutine
+// instead of separate logger worker thread and the Flush method does nothing.
+func SetSynchronous(synchronous bool) *Logger {
+	return Get().SetSynchronous(synchronous)
+}
+
+// IsSynchronous returns true if synchronous mode is enabled.
+func IsSynchronous() bool {
+	return Get().IsSynchronous()
+}
+
+// SetWorker sets logger worker thread used by logger. On default logger uses
+// the global logger worker thread shared with other loggers. Setting own
+// logger worker thread isolates logger from other loggers. Provided logger
+// worker thread is stopped by the Close method. Setting it to nil restores
+// the global logger worker thread.
+func SetWorker(worker *Worker) *Logger {
+	return Get().SetWorker(worker)
+}
+
+// StopWorker flushes all log messages and it stops logger worker thread.
+// Logged messages are emitted synchronously until the StartWorker function is
+// called.
+func StopWorker() *Logger {
+	return Get().StopWorker()
+}
+
+// StartWorker starts logger worker thread stopped by the StopWorker function.
+func StartWorker() *Logger {
+	return Get().StartWorker()
+}
+
 // SetName sets logger name.
 func SetName(name string) *Logger {
 	return Get().SetName(name)
@@ -117,6 +277,28 @@ func GetName() string {
 	return Get().GetName()
 }
 
+// SetLabels sets labels attached to every log record generated by logger.
+func SetLabels(labels map[string]string) *Logger {
+	return Get().SetLabels(labels)
+}
+
+// GetLabels returns labels attached to every log record generated by logger.
+func GetLabels() map[string]string {
+	return Get().GetLabels()
+}
+
+// SetDefaultFields sets named values attached to every log record generated
+// by logger.
+func SetDefaultFields(fields Named) *Logger {
+	return Get().SetDefaultFields(fields)
+}
+
+// GetDefaultFields returns named values attached to every log record
+// generated by logger.
+func GetDefaultFields() Named {
+	return Get().GetDefaultFields()
+}
+
 // AddHandler sets log handler under provided identifier name.
 func AddHandler(name string, handler Handler) *Logger {
 	return Get().AddHandler(name, handler)
@@ -163,6 +345,19 @@ func Reset() *Logger {
 	return Get().ResetHandlers()
 }
 
+// AddHook adds hook under provided identifier name. Hooks are called by
+// logger worker thread in the insertion order for every log record before
+// dispatching it to added log handlers. Adding hook with already used name
+// replaces previous hook and it keeps its position.
+func AddHook(name string, hook Hook) *Logger {
+	return Get().AddHook(name, hook)
+}
+
+// RemoveHook removes added hook by provided name.
+func RemoveHook(name string) *Logger {
+	return Get().RemoveHook(name)
+}
+
 // SetIDGenerator sets ID generator function that is called by logger to
 // generate ID for created log messages.
 func SetIDGenerator(idGenerator IDGenerator) *Logger {
@@ -175,33 +370,43 @@ func GetIDGenerator() IDGenerator {
 	return Get().GetIDGenerator()
 }
 
+// SetIDGeneration sets when logger generates IDs of log records.
+func SetIDGeneration(generation IDGeneration) *Logger {
+	return Get().SetIDGeneration(generation)
+}
+
+// GetIDGeneration returns when logger generates IDs of log records.
+func GetIDGeneration() IDGeneration {
+	return Get().GetIDGeneration()
+}
+
 // Trace logs finer-grained informational messages than the Debug. It creates
 // and sends lightweight not formatted log messages to separate running logger
 // thread for further formatting and I/O handling from different added log
 // handlers.
 func Trace(message string, arguments ...interface{}) {
-	Get().LogMessage(TraceLevel, TraceName, message, arguments...)
+	Get().logMessage(0, TraceLevel, TraceName, message, arguments)
 }
 
 // Debug logs debugging messages. It creates and sends lightweight not formatted
 // log messages to separate running logger thread for further formatting and
 // I/O handling from different added log handlers.
 func Debug(message string, arguments ...interface{}) {
-	Get().LogMessage(DebugLevel, DebugName, message, arguments...)
+	Get().logMessage(0, DebugLevel, DebugName, message, arguments)
 }
 
 // Info logs informational messages. It creates and sends lightweight not
 // formatted log messages to separate running logger thread for further
 // formatting and I/O handling from different added log handlers.
 func Info(message string, arguments ...interface{}) {
-	Get().LogMessage(InfoLevel, InfoName, message, arguments...)
+	Get().logMessage(0, InfoLevel, InfoName, message, arguments)
 }
 
 // Notice logs messages for significant conditions. It creates and sends
 // lightweight not formatted log messages to separate running logger thread for
 // further formatting and I/O handling from different added log handlers.
 func Notice(message string, arguments ...interface{}) {
-	Get().LogMessage(NoticeLevel, NoticeName, message, arguments...)
+	Get().logMessage(0, NoticeLevel, NoticeName, message, arguments)
 }
 
 // Warning logs messages for warning conditions that can be potentially harmful.
@@ -209,48 +414,53 @@ func Notice(message string, arguments ...interface{}) {
 // running logger thread for further formatting and I/O handling from different
 // added log handlers.
 func Warning(message string, arguments ...interface{}) {
-	Get().LogMessage(WarningLevel, WarningName, message, arguments...)
+	Get().logMessage(0, WarningLevel, WarningName, message, arguments)
 }
 
 // Error logs messages for error conditions. It creates and sends lightweight
 // not formatted log messages to separate running logger thread for further
 // formatting and I/O handling from different added log handlers.
 func Error(message string, arguments ...interface{}) {
-	Get().LogMessage(ErrorLevel, ErrorName, message, arguments...)
+	Get().logMessage(0, ErrorLevel, ErrorName, message, arguments)
 }
 
 // Critical logs messages for critical conditions. It creates and sends
 // lightweight not formatted log messages to separate running logger thread for
 // further formatting and I/O handling from different added log handlers.
 func Critical(message string, arguments ...interface{}) {
-	Get().LogMessage(CriticalLevel, CriticalName, message, arguments...)
+	Get().logMessage(0, CriticalLevel, CriticalName, message, arguments)
 }
 
 // Alert logs messages for alert conditions. It creates and sends lightweight
 // not formatted log messages to separate running logger thread for further
 // formatting and I/O handling from different added log handlers.
 func Alert(message string, arguments ...interface{}) {
-	Get().LogMessage(AlertLevel, AlertName, message, arguments...)
+	Get().logMessage(0, AlertLevel, AlertName, message, arguments)
 }
 
-// Fatal logs messages for fatal conditions. It stops logger worker thread and
-// it exists the application with an error code. It creates and sends
+// Fatal logs messages for fatal conditions. It closes logger instance and it
+// exists the application with an error code using function set by the
+// SetExitFunc method. It creates and sends
 // lightweight not formatted log messages to separate running logger thread for
 // further formatting and I/O handling from different added log handlers.
 func Fatal(message string, arguments ...interface{}) {
-	Get().LogMessage(FatalLevel, FatalName, message, arguments...)
+	Get().logMessage(0, FatalLevel, FatalName, message, arguments)
 	Close()
-	os.Exit(Get().GetErrorCode()) // revive:disable-line
+	Get().exit()
 }
 
-// Panic logs messages for fatal conditions. It stops logger worker thread and
-// it exists the application with a panic. It creates and sends lightweight not
+// Panic logs messages for fatal conditions. It closes logger instance and it
+// exists the application with a panic. Panic value is a PanicError object
+// with formatted log message. It creates and sends lightweight not
 // formatted log messages to separate running logger thread for further
 // formatting and I/O handling from different added log handlers.
 func Panic(message string, arguments ...interface{}) {
-	Get().LogMessage(PanicLevel, PanicName, message, arguments...)
+	record := Get().newRecord(loggerSkipCall-1, PanicLevel, PanicName, message, arguments)
+	err := NewPanicError(record)
+
+	Get().send(record)
 	Close()
-	panic(NewRuntimeError("Panic error"))
+	panic(err)
 }
 
 // Log logs messages with user defined log level value and name. It creates and
@@ -258,7 +468,81 @@ func Panic(message string, arguments ...interface{}) {
 // thread for further formatting and I/O handling from different added log
 // handlers.
 func Log(level int, levelName, message string, arguments ...interface{}) {
-	Get().LogMessage(level, levelName, message, arguments...)
+	Get().logMessage(0, level, levelName, message, arguments)
+}
+
+// TraceContext logs finer-grained informational messages like the Trace
+// function and it sets provided context to log record.
+func TraceContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, TraceLevel, TraceName, message, arguments)
+}
+
+// DebugContext logs debugging messages like the Debug function and it sets
+// provided context to log record.
+func DebugContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, DebugLevel, DebugName, message, arguments)
+}
+
+// InfoContext logs informational messages like the Info function and it sets
+// provided context to log record.
+func InfoContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, InfoLevel, InfoName, message, arguments)
+}
+
+// NoticeContext logs messages for significant conditions like the Notice
+// function and it sets provided context to log record.
+func NoticeContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, NoticeLevel, NoticeName, message, arguments)
+}
+
+// WarningContext logs messages for warning conditions like the Warning function
+// and it sets provided context to log record.
+func WarningContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, WarningLevel, WarningName, message, arguments)
+}
+
+// ErrorContext logs messages for error conditions like the Error method and
+// it sets provided context to log record.
+func ErrorContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, ErrorLevel, ErrorName, message, arguments)
+}
+
+// CriticalContext logs messages for critical conditions like the Critical
+// function and it sets provided context to log record.
+func CriticalContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, CriticalLevel, CriticalName, message, arguments)
+}
+
+// AlertContext logs messages for alert conditions like the Alert method and
+// it sets provided context to log record.
+func AlertContext(ctx context.Context, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, AlertLevel, AlertName, message, arguments)
+}
+
+// LogContext logs messages with user defined log level value and name like
+// the Log function and it sets provided context to log record.
+func LogContext(ctx context.Context, level int, levelName, message string, arguments ...interface{}) {
+	Get().logContext(ctx, 0, level, levelName, message, arguments)
+}
+
+// LogBase logs message with defined log level value and name. It skips
+// provided number of additional stack frames to report the source location.
+// Zero skip reports the LogBase caller.
+func LogBase(skip, level int, levelName, message string, arguments ...interface{}) {
+	Get().logMessage(skip, level, levelName, message, arguments)
+}
+
+// Measure returns function that logs message with defined log level value and
+// elapsed time since calling the Measure function. Elapsed time is available
+// in log message as the {duration} named placeholder.
+func Measure(level int, message string, arguments ...interface{}) func() {
+	return Get().measure(0, level, message, arguments)
+}
+
+// Writer returns writer that logs every written line as a separate log record
+// with provided log level value and name.
+func Writer(level int, levelName string) io.Writer {
+	return Get().Writer(level, levelName)
 }
 
 // Emit emits provided log record to logger worker thread for further
@@ -267,16 +551,84 @@ func Emit(record *Record) *Logger {
 	return Get().Emit(record)
 }
 
+// TryEmit emits provided log record to logger worker thread without blocking.
+// It returns false when the logger worker thread queue is full.
+func TryEmit(record *Record) bool {
+	return Get().TryEmit(record)
+}
+
+// Reopen reopens all added log handlers that implement the Reopener interface.
+func Reopen() *Logger {
+	return Get().Reopen()
+}
+
+// ReopenOnSignal reopens all added log handlers when one of provided signals
+// arrives.
+func ReopenOnSignal(signals ...os.Signal) *Logger {
+	return Get().ReopenOnSignal(signals...)
+}
+
+// WatchConfig configures global logger from provided configuration file and
+// it reapplies configuration file every time when it is modified.
+func WatchConfig(path string) (stop func(), err error) {
+	return Get().WatchConfig(path)
+}
+
 // Flush flushes all log messages.
 func Flush() *Logger {
 	return Get().Flush()
 }
 
-// Close closes all added log handlers.
+// FlushTimeout flushes all log messages but it waits at most provided timeout
+// duration.
+func FlushTimeout(timeout time.Duration) error {
+	return Get().FlushTimeout(timeout)
+}
+
+// FlushContext flushes all log messages but it waits until provided context
+// is done.
+func FlushContext(ctx context.Context) error {
+	return Get().FlushContext(ctx)
+}
+
+// CloseTimeout closes all added log handlers like the Close function but it
+// waits at most provided timeout duration for flushing all log messages.
+func CloseTimeout(timeout time.Duration) error {
+	if err := Get().CloseTimeout(timeout); err != nil {
+		return err
+	}
+
+	GetWorker().Stop()
+
+	return nil
+}
+
+// Shutdown shuts down global logger like the Logger Shutdown method. It stops
+// also the global logger worker thread like the StopWorker function, log
+// messages from other loggers are emitted synchronously after it.
+func Shutdown(ctx context.Context) error {
+	err := Get().Shutdown(ctx)
+
+	if haltErr := doContext(ctx, func() { GetWorker().halt() }); haltErr != nil {
+		return errors.Join(err, NewRuntimeError("cannot stop logger worker thread", haltErr))
+	}
+
+	return err
+}
+
+// Close closes all added log handlers. It stops also the global logger worker
+// thread. Next log message restarts it.
 func Close() {
 	err := Get().Close()
 
 	if err != nil {
-		printError(NewRuntimeError("cannot close logger"))
+		printError(NewRuntimeError("cannot close logger", err))
 	}
+
+	GetWorker().Stop()
+}
+
+// Stats returns statistics of the global logger worker thread.
+func Stats() WorkerStats {
+	return GetWorker().Stats()
 }
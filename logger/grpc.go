@@ -0,0 +1,157 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultGRPCCallerSkip defines default number of stack frames between gRPC
+// call site and GRPCLogger methods, the grpclog package function.
+const DefaultGRPCCallerSkip = 1
+
+// A GRPCLogger represents an adapter that implements the grpclog.LoggerV2
+// interface of the gRPC library without depending on it. Set it with the
+// grpclog.SetLoggerV2 function to log internal gRPC messages via logger.
+type GRPCLogger struct {
+	logger     *Logger
+	verbosity  int
+	callerSkip int32
+}
+
+// NewGRPCLogger creates a new GRPCLogger object that logs gRPC messages via
+// provided logger. Provided verbosity level is used by the V method.
+func NewGRPCLogger(l *Logger, verbosity int) *GRPCLogger {
+	return &GRPCLogger{
+		logger:     l,
+		verbosity:  verbosity,
+		callerSkip: DefaultGRPCCallerSkip,
+	}
+}
+
+// SetCallerSkip sets number of stack frames between gRPC call site and
+// GRPCLogger methods. On default it is DefaultGRPCCallerSkip.
+func (g *GRPCLogger) SetCallerSkip(skip int) *GRPCLogger {
+	atomic.StoreInt32(&g.callerSkip, int32(skip))
+	return g
+}
+
+// GetCallerSkip returns number of stack frames between gRPC call site and
+// GRPCLogger methods.
+func (g *GRPCLogger) GetCallerSkip() int {
+	return int(atomic.LoadInt32(&g.callerSkip))
+}
+
+// Info logs gRPC message with the InfoLevel log level. Arguments are handled
+// in the manner of fmt.Print.
+func (g *GRPCLogger) Info(arguments ...interface{}) {
+	g.log(InfoLevel, InfoName, fmt.Sprint(arguments...))
+}
+
+// Infoln logs gRPC message with the InfoLevel log level. Arguments are
+// handled in the manner of fmt.Println.
+func (g *GRPCLogger) Infoln(arguments ...interface{}) {
+	g.log(InfoLevel, InfoName, sprintln(arguments))
+}
+
+// Infof logs gRPC message with the InfoLevel log level. Arguments are handled
+// in the manner of fmt.Printf.
+func (g *GRPCLogger) Infof(format string, arguments ...interface{}) {
+	g.log(InfoLevel, InfoName, fmt.Sprintf(format, arguments...))
+}
+
+// Warning logs gRPC message with the WarningLevel log level. Arguments are
+// handled in the manner of fmt.Print.
+func (g *GRPCLogger) Warning(arguments ...interface{}) {
+	g.log(WarningLevel, WarningName, fmt.Sprint(arguments...))
+}
+
+// Warningln logs gRPC message with the WarningLevel log level. Arguments are
+// handled in the manner of fmt.Println.
+func (g *GRPCLogger) Warningln(arguments ...interface{}) {
+	g.log(WarningLevel, WarningName, sprintln(arguments))
+}
+
+// Warningf logs gRPC message with the WarningLevel log level. Arguments are
+// handled in the manner of fmt.Printf.
+func (g *GRPCLogger) Warningf(format string, arguments ...interface{}) {
+	g.log(WarningLevel, WarningName, fmt.Sprintf(format, arguments...))
+}
+
+// Error logs gRPC message with the ErrorLevel log level. Arguments are
+// handled in the manner of fmt.Print.
+func (g *GRPCLogger) Error(arguments ...interface{}) {
+	g.log(ErrorLevel, ErrorName, fmt.Sprint(arguments...))
+}
+
+// Errorln logs gRPC message with the ErrorLevel log level. Arguments are
+// handled in the manner of fmt.Println.
+func (g *GRPCLogger) Errorln(arguments ...interface{}) {
+	g.log(ErrorLevel, ErrorName, sprintln(arguments))
+}
+
+// Errorf logs gRPC message with the ErrorLevel log level. Arguments are
+// handled in the manner of fmt.Printf.
+func (g *GRPCLogger) Errorf(format string, arguments ...interface{}) {
+	g.log(ErrorLevel, ErrorName, fmt.Sprintf(format, arguments...))
+}
+
+// Fatal logs gRPC message with the FatalLevel log level like the Logger.Fatal
+// method, it closes logger and it exits the application. Arguments are
+// handled in the manner of fmt.Print.
+func (g *GRPCLogger) Fatal(arguments ...interface{}) {
+	g.fatal(fmt.Sprint(arguments...))
+}
+
+// Fatalln logs gRPC message with the FatalLevel log level like the
+// Logger.Fatal method, it closes logger and it exits the application.
+// Arguments are handled in the manner of fmt.Println.
+func (g *GRPCLogger) Fatalln(arguments ...interface{}) {
+	g.fatal(sprintln(arguments))
+}
+
+// Fatalf logs gRPC message with the FatalLevel log level like the
+// Logger.Fatal method, it closes logger and it exits the application.
+// Arguments are handled in the manner of fmt.Printf.
+func (g *GRPCLogger) Fatalf(format string, arguments ...interface{}) {
+	g.fatal(fmt.Sprintf(format, arguments...))
+}
+
+// V returns true if provided gRPC verbosity level is lower than or equal to
+// verbosity level of GRPCLogger.
+func (g *GRPCLogger) V(level int) bool {
+	return level <= g.verbosity
+}
+
+// log logs already formatted gRPC message. Source location is the gRPC call
+// site.
+func (g *GRPCLogger) log(level int, levelName, message string) {
+	g.logger.logMessage(g.GetCallerSkip()+1, level, levelName, message, nil)
+}
+
+// fatal logs already formatted gRPC message like the Logger.Fatal method.
+func (g *GRPCLogger) fatal(message string) {
+	g.logger.logMessage(g.GetCallerSkip()+1, FatalLevel, FatalName, message, nil)
+	g.logger.CloseDefer()
+	g.logger.exit()
+}
+
+// sprintln formats provided arguments in the manner of fmt.Println without
+// the trailing line ending.
+func sprintln(arguments []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(arguments...), "\n")
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+// loggerV2 defines the grpclog.LoggerV2 interface of the gRPC library.
+type loggerV2 interface {
+	Info(args ...interface{})
+	Infoln(args ...interface{})
+	Infof(format string, args ...interface{})
+	Warning(args ...interface{})
+	Warningln(args ...interface{})
+	Warningf(format string, args ...interface{})
+	Error(args ...interface{})
+	Errorln(args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fatal(args ...interface{})
+	Fatalln(args ...interface{})
+	Fatalf(format string, args ...interface{})
+	V(l int) bool
+}
+
+var _ loggerV2 = (*logger.GRPCLogger)(nil)
+
+// grpclogInfof mimics the grpclog.Infof function of the gRPC library.
+func grpclogInfof(log loggerV2, format string, args ...interface{}) {
+	log.Infof(format, args...)
+}
+
+func TestGRPCLogger(test *testing.T) {
+	var code int
+
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{level}:{file}:{line}:{message}")
+
+	log := logger.New().SetHandlers(logger.Handlers{"buffer": buffer}).SetSynchronous(true)
+
+	log.SetExitFunc(func(errorCode int) {
+		code = errorCode
+	})
+
+	grpc := logger.NewGRPCLogger(log, 2)
+
+	grpclogInfof(grpc, "Connected {%s}", "addr")
+	grpc.SetCallerSkip(0).Warningln("Retrying", 1)
+	grpc.Error("Failed ", 2)
+	grpc.Fatalf("Exit %d", 3)
+
+	want := "info:grpc_test.go:62:Connected {addr}\n" +
+		"warning:grpc_test.go:63:Retrying 1\n" +
+		"error:grpc_test.go:64:Failed 2\n" +
+		"fatal:grpc_test.go:65:Exit 3\n"
+
+	if buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	if code != logger.DefaultErrorCode {
+		test.Errorf("exit code = %d; want %d", code, logger.DefaultErrorCode)
+	}
+
+	if !grpc.V(2) || grpc.V(3) {
+		test.Errorf("V(2), V(3) = %t, %t; want true, false", grpc.V(2), grpc.V(3))
+	}
+}
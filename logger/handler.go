@@ -14,8 +14,23 @@
 
 package logger
 
-// Handler defines interface for log handlers.
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Handler defines interface for log handlers. Log handler name methods are
+// named SetHandlerName and GetHandlerName instead of SetName and Name, because
+// the File log handler already uses the SetName and GetName methods for its
+// file name. Embed the HandlerBase to implement custom log handler. Custom log
+// handler with own level range or enabled state must call the
+// InvalidateLevelCache function after changing them.
 type Handler interface {
+	SetHandlerName(name string) Handler
+
+	GetHandlerName() string
+
 	SetFormatter(formatter *Formatter) Handler
 
 	GetFormatter() *Formatter
@@ -47,3 +62,141 @@ type Handler interface {
 
 // Handlers defines map of log handlers.
 type Handlers map[string]Handler
+
+// HandlerConstructor defines function that creates a new log handler.
+type HandlerConstructor func() Handler
+
+// HandlerOptions defines options passed to log handler factory, like for
+// example file name of the File log handler or address of the Syslog log
+// handler.
+type HandlerOptions map[string]interface{}
+
+// HandlerFactory defines function that creates a new log handler configured
+// with provided options. It returns an error for unsupported option or for
+// option with invalid value.
+type HandlerFactory func(options HandlerOptions) (Handler, error)
+
+var gHandlersMutex sync.RWMutex                         // nolint:gochecknoglobals
+var gHandlerFactories = make(map[string]HandlerFactory) // nolint:gochecknoglobals
+
+// Reopener defines optional interface for log handlers that can reopen their
+// output, like for example a log file renamed by logrotate.
+type Reopener interface {
+	Reopen() Handler
+}
+
+// Flusher defines optional interface for log handlers that buffer written log
+// records, like for example File log handler with a write buffer.
+type Flusher interface {
+	Flush() error
+}
+
+// Retainer defines optional interface for log handlers that keep provided log
+// records after returning from the Emit method, like for example ring buffers
+// or log handlers capturing log records in tests. Logger worker thread provides
+// own copy of log record from the Record.Clone method to log handler that
+// retains log records. Such log record is never shared with other log handlers
+// and it is never returned to the log records pool.
+type Retainer interface {
+	RetainsRecords() bool
+}
+
+// BatchHandler defines optional interface for log handlers that can emit
+// multiple log records at once, like for example with a single write call.
+// Logger worker thread uses it when it finds multiple log records ready in
+// its queue. Other log handlers receive log records one by one using the Emit
+// method.
+//
+// Log record ID is generated once by logger worker thread before running
+// hooks and before collecting log records to a batch. Log handlers that
+// resend the same batch, like for example after a timeout, send the same log
+// record IDs and they can use them as idempotency keys to deduplicate log
+// records on the receiving side.
+type BatchHandler interface {
+	EmitBatch(records []*Record) error
+}
+
+// RegisterHandler registers log handler constructor under provided name. It
+// allows to create log handlers by their names using the CreateHandler
+// function, like for example from a configuration. Registering already
+// registered name replaces its log handler constructor. Log handler created by
+// provided constructor doesn't support any options.
+func RegisterHandler(name string, constructor HandlerConstructor) error {
+	if constructor == nil {
+		return NewRuntimeError("cannot register handler {p | printf \"%q\"}, constructor is nil", name)
+	}
+
+	return RegisterHandlerFactory(name, func(options HandlerOptions) (Handler, error) {
+		if err := newHandlerOptions(options).err(); err != nil {
+			return nil, err
+		}
+
+		return constructor(), nil
+	})
+}
+
+// RegisterHandlerFactory registers log handler factory under provided name. It
+// allows to create log handlers by their names with options using the
+// CreateHandlerWithOptions function. Registering already registered name
+// replaces its log handler factory.
+func RegisterHandlerFactory(name string, factory HandlerFactory) error {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return NewRuntimeError("cannot register handler with empty name")
+	}
+
+	if factory == nil {
+		return NewRuntimeError("cannot register handler {p | printf \"%q\"}, factory is nil", name)
+	}
+
+	gHandlersMutex.Lock()
+	defer gHandlersMutex.Unlock()
+
+	gHandlerFactories[name] = factory
+
+	return nil
+}
+
+// CreateHandler creates a new log handler using log handler constructor or
+// factory registered under provided name.
+func CreateHandler(name string) (Handler, error) {
+	return CreateHandlerWithOptions(name, nil)
+}
+
+// CreateHandlerWithOptions creates a new log handler using log handler factory
+// registered under provided name and configured with provided options.
+func CreateHandlerWithOptions(name string, options HandlerOptions) (Handler, error) {
+	gHandlersMutex.RLock()
+	factory, ok := gHandlerFactories[strings.TrimSpace(name)]
+	gHandlersMutex.RUnlock()
+
+	if !ok {
+		return nil, NewRuntimeError("cannot create handler {p | printf \"%q\"}, handler is not registered", name)
+	}
+
+	handler, err := factory(options)
+
+	if err != nil {
+		return nil, NewRuntimeError("cannot create handler {p | printf \"%q\"}", name, err)
+	}
+
+	return handler, nil
+}
+
+// RegisteredHandlers returns sorted names of all registered log handler
+// constructors and factories.
+func RegisteredHandlers() []string {
+	gHandlersMutex.RLock()
+	defer gHandlersMutex.RUnlock()
+
+	names := make([]string, 0, len(gHandlerFactories))
+
+	for name := range gHandlerFactories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+)
+
+// A HandlerBase represents a base for custom log handlers. It implements all
+// methods of the Handler interface with log handler name, formatter, level
+// range and enabled state. Its Emit and Close methods do nothing, custom log
+// handler embeds it and it implements own Emit method and optionally own Close
+// method. Zero value is ready to use, it emits all log levels with a default
+// formatter.
+//
+// Setters return log handler provided to the NewHandlerBase function. Embedded
+// zero value returns the HandlerBase itself that shares its state with custom
+// log handler.
+//
+//	type Custom struct {
+//		*logger.HandlerBase
+//	}
+//
+//	func NewCustom() *Custom {
+//		custom := &Custom{}
+//		custom.HandlerBase = logger.NewHandlerBase(custom)
+//
+//		return custom
+//	}
+//
+//	func (c *Custom) Emit(record *logger.Record) error {
+//		message, err := c.GetFormatter().Format(record)
+//		...
+//	}
+type HandlerBase struct {
+	mutex        sync.RWMutex
+	owner        Handler
+	name         string
+	formatter    *Formatter
+	minimumLevel int
+	maximumLevel int
+	hasLevels    bool
+	isDisabled   bool
+}
+
+// NewHandlerBase creates a new HandlerBase for provided log handler that
+// embeds it. Provided log handler is returned from setters.
+func NewHandlerBase(owner Handler) *HandlerBase {
+	return &HandlerBase{
+		owner: owner,
+	}
+}
+
+// self returns log handler returned from setters. Caller must hold the lock.
+func (b *HandlerBase) self() Handler {
+	if b.owner != nil {
+		return b.owner
+	}
+
+	return b
+}
+
+// SetHandlerName sets log handler name.
+func (b *HandlerBase) SetHandlerName(name string) Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.name = name
+
+	return b.self()
+}
+
+// GetHandlerName returns log handler name.
+func (b *HandlerBase) GetHandlerName() string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.name
+}
+
+// Enable enables log handler.
+func (b *HandlerBase) Enable() Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.isDisabled = false
+
+	InvalidateLevelCache()
+
+	return b.self()
+}
+
+// Disable disabled log handler.
+func (b *HandlerBase) Disable() Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.isDisabled = true
+
+	InvalidateLevelCache()
+
+	return b.self()
+}
+
+// IsEnabled returns if log handler is enabled.
+func (b *HandlerBase) IsEnabled() bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return !b.isDisabled
+}
+
+// SetFormatter sets Formatter.
+func (b *HandlerBase) SetFormatter(formatter *Formatter) Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.formatter = formatter
+
+	return b.self()
+}
+
+// GetFormatter returns Formatter. Zero value creates a default formatter.
+func (b *HandlerBase) GetFormatter() *Formatter {
+	b.mutex.RLock()
+	formatter := b.formatter
+	b.mutex.RUnlock()
+
+	if formatter != nil {
+		return formatter
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.formatter == nil {
+		b.formatter = NewFormatter()
+	}
+
+	return b.formatter
+}
+
+// SetLevel sets log level.
+func (b *HandlerBase) SetLevel(level int) Handler {
+	return b.SetLevelRange(level, level)
+}
+
+// SetMinimumLevel sets minimum log level.
+func (b *HandlerBase) SetMinimumLevel(level int) Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, max := b.getLevelRange()
+	b.setLevelRange(level, max)
+
+	return b.self()
+}
+
+// GetMinimumLevel returns minimum log level.
+func (b *HandlerBase) GetMinimumLevel() int {
+	min, _ := b.GetLevelRange()
+	return min
+}
+
+// SetMaximumLevel sets maximum log level.
+func (b *HandlerBase) SetMaximumLevel(level int) Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	min, _ := b.getLevelRange()
+	b.setLevelRange(min, level)
+
+	return b.self()
+}
+
+// GetMaximumLevel returns maximum log level.
+func (b *HandlerBase) GetMaximumLevel() int {
+	_, max := b.GetLevelRange()
+	return max
+}
+
+// SetLevelRange sets minimum and maximum log level values.
+func (b *HandlerBase) SetLevelRange(min, max int) Handler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.setLevelRange(min, max)
+
+	return b.self()
+}
+
+// GetLevelRange returns minimum and maximum log level values.
+func (b *HandlerBase) GetLevelRange() (min, max int) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.getLevelRange()
+}
+
+// Emit does nothing. Custom log handler implements own Emit method.
+func (*HandlerBase) Emit(*Record) error {
+	return nil
+}
+
+// Close does nothing.
+func (*HandlerBase) Close() error {
+	return nil
+}
+
+// setLevelRange sets minimum and maximum log level values. Caller must hold
+// the lock.
+func (b *HandlerBase) setLevelRange(min, max int) {
+	b.minimumLevel = min
+	b.maximumLevel = max
+	b.hasLevels = true
+
+	InvalidateLevelCache()
+}
+
+// getLevelRange returns minimum and maximum log level values. Zero value
+// returns the full log level range. Caller must hold the lock.
+func (b *HandlerBase) getLevelRange() (min, max int) {
+	if !b.hasLevels {
+		return MinimumLevel, MaximumLevel
+	}
+
+	return b.minimumLevel, b.maximumLevel
+}
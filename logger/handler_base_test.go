@@ -0,0 +1,88 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+type customHandler struct {
+	*logger.HandlerBase
+	observer *logger.Observer
+}
+
+func newCustomHandler() *customHandler {
+	custom := &customHandler{observer: logger.NewObserver()}
+	custom.HandlerBase = logger.NewHandlerBase(custom)
+
+	return custom
+}
+
+func (c *customHandler) Emit(record *logger.Record) error {
+	return c.observer.Emit(record)
+}
+
+type zeroHandler struct {
+	logger.HandlerBase
+}
+
+func TestHandlerBase(test *testing.T) {
+	custom := newCustomHandler()
+
+	if handler := custom.SetHandlerName("custom").SetLevel(logger.InfoLevel); handler != custom {
+		test.Error("SetLevel() doesn't return log handler provided to NewHandlerBase()")
+	}
+
+	log := logger.New().SetSynchronous(true).SetHandler("registered", custom)
+
+	log.Debug(testMessage)
+	log.Info(testMessage)
+
+	if name := custom.GetHandlerName(); name != "custom" {
+		test.Errorf("GetHandlerName() = %q; want %q", name, "custom")
+	}
+
+	if length := custom.observer.Len(); length != 1 {
+		test.Errorf("Len() = %d; want 1", length)
+	}
+}
+
+func TestHandlerBaseZeroValue(test *testing.T) {
+	var handler zeroHandler
+
+	if min, max := handler.GetLevelRange(); (min != logger.MinimumLevel) || (max != logger.MaximumLevel) {
+		test.Errorf("GetLevelRange() = %d, %d; want full range", min, max)
+	}
+
+	if handler.GetFormatter() == nil {
+		test.Error("GetFormatter() = nil; want default formatter")
+	}
+
+	handler.SetHandlerName("zero").SetMinimumLevel(logger.WarningLevel).Disable()
+
+	if name := handler.GetHandlerName(); name != "zero" {
+		test.Errorf("GetHandlerName() = %q; want %q", name, "zero")
+	}
+
+	if min, max := handler.GetLevelRange(); (min != logger.WarningLevel) || (max != logger.MaximumLevel) {
+		test.Errorf("GetLevelRange() = %d, %d; want %d, %d", min, max, logger.WarningLevel, logger.MaximumLevel)
+	}
+
+	if handler.IsEnabled() {
+		test.Error("IsEnabled() = true; want false")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// maxExactFloat defines maximum float value that can be exactly represented
+// as integer.
+const maxExactFloat = 1 << 53
+
+// handlerOptions represents options passed to log handler factory. It tracks
+// used options and the first error of invalid option value.
+type handlerOptions struct {
+	options HandlerOptions
+	used    map[string]bool
+	failed  error
+}
+
+// newHandlerOptions creates a new handlerOptions object.
+func newHandlerOptions(options HandlerOptions) *handlerOptions {
+	return &handlerOptions{
+		options: options,
+		used:    make(map[string]bool, len(options)),
+	}
+}
+
+// lookup returns option value and it marks option as used.
+func (o *handlerOptions) lookup(key string) (interface{}, bool) {
+	value, ok := o.options[key]
+
+	if ok {
+		o.used[key] = true
+	}
+
+	return value, ok
+}
+
+// invalid records error of option with invalid value.
+func (o *handlerOptions) invalid(key string, value interface{}) {
+	if o.failed == nil {
+		o.failed = NewRuntimeError("cannot use handler option {p | printf \"%q\"}, invalid value", key, value)
+	}
+}
+
+// setString calls provided setter with string option value if it is set.
+func (o *handlerOptions) setString(key string, setter func(value string)) {
+	if value, ok := o.lookup(key); ok {
+		if converted, ok := value.(string); ok {
+			setter(converted)
+		} else {
+			o.invalid(key, value)
+		}
+	}
+}
+
+// setInt calls provided setter with integer option value if it is set.
+// Integer can be provided as any integer or whole float number, JSON number
+// or string with decimal, octal or hexadecimal integer.
+func (o *handlerOptions) setInt(key string, setter func(value int)) {
+	if value, ok := o.lookup(key); ok {
+		if converted, ok := toInt(value); ok {
+			setter(converted)
+		} else {
+			o.invalid(key, value)
+		}
+	}
+}
+
+// setBool calls provided setter with boolean option value if it is set.
+func (o *handlerOptions) setBool(key string, setter func(value bool)) {
+	if value, ok := o.lookup(key); ok {
+		switch converted := value.(type) {
+		case bool:
+			setter(converted)
+		case string:
+			if parsed, err := strconv.ParseBool(converted); err == nil {
+				setter(parsed)
+			} else {
+				o.invalid(key, value)
+			}
+		default:
+			o.invalid(key, value)
+		}
+	}
+}
+
+// setDuration calls provided setter with duration option value if it is set.
+// Duration can be provided as string like "1.5s" or as integer nanoseconds.
+func (o *handlerOptions) setDuration(key string, setter func(value time.Duration)) {
+	if value, ok := o.lookup(key); ok {
+		if converted, ok := value.(string); ok {
+			if parsed, err := time.ParseDuration(converted); err == nil {
+				setter(parsed)
+			} else {
+				o.invalid(key, value)
+			}
+		} else if converted, ok := toInt(value); ok {
+			setter(time.Duration(converted))
+		} else {
+			o.invalid(key, value)
+		}
+	}
+}
+
+// setRetry sets retry of failed writes of provided stream from the
+// "retryAttempts", "retryBackoff" and "retryTimeout" options if they are set.
+func (o *handlerOptions) setRetry(stream *Stream) {
+	attempts, backoff := stream.GetRetry()
+
+	o.setInt("retryAttempts", func(value int) { attempts = value })
+	o.setDuration("retryBackoff", func(value time.Duration) { backoff = value })
+	o.setDuration("retryTimeout", func(timeout time.Duration) { stream.SetRetryTimeout(timeout) })
+
+	stream.SetRetry(attempts, backoff)
+}
+
+// err returns error of the first option with invalid value or of the first
+// unsupported option sorted by name.
+func (o *handlerOptions) err() error {
+	if o.failed != nil {
+		return o.failed
+	}
+
+	unused := make([]string, 0, len(o.options))
+
+	for key := range o.options {
+		if !o.used[key] {
+			unused = append(unused, key)
+		}
+	}
+
+	if len(unused) == 0 {
+		return nil
+	}
+
+	sort.Strings(unused)
+
+	return NewRuntimeError("cannot use handler option {p | printf \"%q\"}, option is not supported", unused[0])
+}
+
+// toInt converts provided value to integer.
+func toInt(value interface{}) (int, bool) {
+	switch converted := value.(type) {
+	case time.Duration:
+		return int(converted), true
+	case json.Number:
+		return toInt(converted.String())
+	case string:
+		parsed, err := strconv.ParseInt(converted, 0, 0)
+		return int(parsed), err == nil
+	}
+
+	valueOf := reflect.ValueOf(value)
+
+	switch valueOf.Kind() { // nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(valueOf.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if valueOf.Uint() <= math.MaxInt {
+			return int(valueOf.Uint()), true
+		}
+	case reflect.Float32, reflect.Float64:
+		if float := valueOf.Float(); (float == math.Trunc(float)) && (math.Abs(float) <= maxExactFloat) {
+			return int(float), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,345 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestAddHandlerName(test *testing.T) {
+	var errs []error
+
+	stream := logger.NewStream()
+
+	if err := stream.SetWriter(failingWriter{}); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	stream.SetHandlerName("stream")
+
+	log := logger.New().SetSynchronous(true).SetHandler("failing", stream)
+
+	log.SetInternalErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	if name := stream.GetHandlerName(); name != "stream" {
+		test.Error("GetHandlerName() =", name, "; want stream")
+	}
+
+	log.Info(testMessage)
+
+	if len(errs) != 1 {
+		test.Fatalf("len(errs) = %d; want 1", len(errs))
+	}
+
+	if !strings.Contains(errs[0].Error(), `cannot emit record via handler "failing"`) {
+		test.Error("Error() =", errs[0].Error(), "; want handler name")
+	}
+}
+
+func TestRegisterHandler(test *testing.T) {
+	names := logger.RegisteredHandlers()
+
+	for _, name := range []string{"buffer", "file", "gelf", "observer", "stderr", "stdout", "stream", "syslog"} {
+		handler, err := logger.CreateHandler(name)
+
+		if err != nil {
+			test.Errorf("CreateHandler(%q) returns an unexpected error %v", name, err)
+		}
+
+		if handler == nil {
+			test.Errorf("CreateHandler(%q) = nil; want handler", name)
+		}
+
+		if !strings.Contains(strings.Join(names, ","), name) {
+			test.Errorf("RegisteredHandlers() = %v; want %q", names, name)
+		}
+	}
+
+	if err := logger.RegisterHandler("test.observer", func() logger.Handler {
+		return logger.NewObserver()
+	}); err != nil {
+		test.Fatal("RegisterHandler() returns an unexpected error", err)
+	}
+
+	if handler, err := logger.CreateHandler("test.observer"); err != nil {
+		test.Error("CreateHandler() returns an unexpected error", err)
+	} else if _, ok := handler.(*logger.Observer); !ok {
+		test.Errorf("CreateHandler() = %T; want *logger.Observer", handler)
+	}
+}
+
+func TestRegisterHandlerInvalid(test *testing.T) {
+	constructor := func() logger.Handler {
+		return logger.NewObserver()
+	}
+
+	if err := logger.RegisterHandler(" ", constructor); err == nil {
+		test.Error("RegisterHandler() with empty name returns nil error")
+	}
+
+	if err := logger.RegisterHandler("nil", nil); err == nil {
+		test.Error("RegisterHandler() with nil constructor returns nil error")
+	}
+}
+
+func TestCreateHandlerUnknown(test *testing.T) {
+	handler, err := logger.CreateHandler("unknown")
+
+	if err == nil {
+		test.Error("CreateHandler() returns nil error for unknown handler")
+	}
+
+	if handler != nil {
+		test.Errorf("CreateHandler() = %v; want nil", handler)
+	}
+}
+
+func TestCreateHandlerWithOptions(test *testing.T) {
+	name := filepath.Join(test.TempDir(), "options.log")
+
+	handler, err := logger.CreateHandlerWithOptions("file", logger.HandlerOptions{
+		"name":          name,
+		"mode":          "0600",
+		"bufferSize":    4096.0,
+		"flushInterval": "10ms",
+		"locking":       true,
+	})
+
+	if err != nil {
+		test.Fatal("CreateHandlerWithOptions() returns an unexpected error", err)
+	}
+
+	file := handler.(*logger.File) // nolint:forcetypeassert
+
+	if (file.GetName() != name) || (file.GetMode() != 0o600) || (file.GetWriteBuffer() != 4096) ||
+		(file.GetFlushInterval() != 10*time.Millisecond) || !file.IsLocking() {
+		test.Errorf("CreateHandlerWithOptions() = %+v; want configured file", file)
+	}
+
+	var buffer bytes.Buffer
+
+	if handler, err = logger.CreateHandlerWithOptions("stream", logger.HandlerOptions{
+		"writer":        &buffer,
+		"writeTimeout":  "1s",
+		"retryAttempts": 2,
+		"retryBackoff":  "5ms",
+	}); err != nil {
+		test.Fatal("CreateHandlerWithOptions() returns an unexpected error", err)
+	}
+
+	if timeout := handler.(*logger.Stream).GetWriteTimeout(); timeout != time.Second { // nolint:forcetypeassert
+		test.Errorf("GetWriteTimeout() = %v; want %v", timeout, time.Second)
+	}
+
+	if attempts, backoff := handler.(*logger.Stream).GetRetry(); (attempts != 2) || (backoff != 5*time.Millisecond) { // nolint:forcetypeassert
+		test.Errorf("GetRetry() = %d, %v; want 2, %v", attempts, backoff, 5*time.Millisecond)
+	}
+
+	handler.GetFormatter().SetFormat("{message}")
+
+	if err = handler.Emit(&logger.Record{Message: testMessage}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	if want := testMessage + "\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestCreateHandlerWithOptionsInvalid(test *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		options logger.HandlerOptions
+		want    string
+	}{
+		{"syslog", logger.HandlerOptions{"port": "http"}, `"port", invalid value`},
+		{"syslog", logger.HandlerOptions{"port": 1.5}, `"port", invalid value`},
+		{"file", logger.HandlerOptions{"locking": 1}, `"locking", invalid value`},
+		{"file", logger.HandlerOptions{"name": "a", "zone": 1, "colors": 2}, `"colors", option is not supported`},
+		{"stdout", logger.HandlerOptions{"name": "a"}, `"name", option is not supported`},
+		{"stream", logger.HandlerOptions{"writer": "stdout"}, `"writer", invalid value`},
+	} {
+		if _, err := logger.CreateHandlerWithOptions(tt.name, tt.options); (err == nil) || !strings.Contains(err.Error(), tt.want) {
+			test.Errorf("CreateHandlerWithOptions(%q, %v) = %v; want %s", tt.name, tt.options, err, tt.want)
+		}
+	}
+}
+
+func TestLoggerSetHandler(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().AddHandler("buffer", logger.NewBuffer()).SetHandler("observer", observer)
+
+	handlers := log.GetHandlers()
+
+	if len(handlers) != 1 {
+		test.Fatalf("len(GetHandlers()) = %d; want 1", len(handlers))
+	}
+
+	if handlers["observer"] != observer {
+		test.Error("GetHandlers() doesn't contain set log handler")
+	}
+
+	if name := observer.GetHandlerName(); name != "observer" {
+		test.Errorf("GetHandlerName() = %q; want %q", name, "observer")
+	}
+}
+
+func TestLoggerNilHandler(test *testing.T) {
+	var errs []error
+
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetSynchronous(true)
+
+	defer log.Close()
+
+	log.SetInternalErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	log.SetHandlers(logger.Handlers{"buffer": buffer, "nil": nil})
+	log.AddHandler("added", nil)
+	log.SetHandler("set", nil)
+
+	if len(errs) != 3 {
+		test.Errorf("len(errs) = %d; want 3", len(errs))
+	}
+
+	if _, err := log.GetHandler("nil"); err == nil {
+		test.Error("SetHandlers() sets nil log handler")
+	}
+
+	log.GetHandlers()["injected"] = nil
+	log.Info(testMessage)
+	log.RemoveHandler("injected")
+
+	if want := testMessage + "\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestLoggerErrorHandlerLogs(test *testing.T) {
+	var reported int
+
+	observer := logger.NewObserver()
+
+	stream := logger.NewStream()
+
+	if err := stream.SetWriteCloser(failingCloser{err: testError}); err != nil {
+		test.Fatal("SetWriteCloser() returns an unexpected error", err)
+	}
+
+	log := logger.New().SetSynchronous(true)
+
+	log.SetInternalErrorHandler(func(err error) {
+		reported++
+		log.Warning("internal error: {p}", err)
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		log.SetHandlers(logger.Handlers{"observer": observer, "nil": nil})
+		log.AddHandler("added", nil)
+		log.SetHandler("set", nil)
+		log.AddHandler("stream", stream)
+		_ = log.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		test.Fatal("internal error handler logging through the same logger deadlocks")
+	}
+
+	if reported != 4 {
+		test.Errorf("reported = %d; want 4", reported)
+	}
+
+	if length := observer.Len(); length < 3 {
+		test.Errorf("Len() = %d; want at least 3", length)
+	}
+}
+
+func TestAddHandlerSharedName(test *testing.T) {
+	var errs []string
+
+	stream := logger.NewStream()
+
+	if err := stream.SetWriter(failingWriter{}); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	report := func(err error) {
+		errs = append(errs, err.Error())
+	}
+
+	first := logger.New().SetSynchronous(true).SetInternalErrorHandler(report).SetHandlers(logger.Handlers{
+		"first":  stream,
+		"second": stream,
+	})
+
+	third := logger.New().SetSynchronous(true).SetInternalErrorHandler(report).SetHandler("third", stream)
+
+	first.Info(testMessage)
+	third.Info(testMessage)
+
+	if len(errs) != 3 {
+		test.Fatalf("len(errs) = %d; want 3", len(errs))
+	}
+
+	if name := stream.GetHandlerName(); (name != "first") && (name != "second") {
+		test.Errorf("GetHandlerName() = %q; want name from first adding", name)
+	}
+
+	for _, name := range []string{"first", "second", "third"} {
+		found := false
+
+		for _, err := range errs {
+			found = found || strings.Contains(err, "cannot emit record via handler \""+name+"\"")
+		}
+
+		if !found {
+			test.Errorf("errs = %q; want error for handler %q", errs, name)
+		}
+	}
+}
+
+func TestLoggerSetHandlerWrapper(test *testing.T) {
+	handler := panickingHandler{logger.NewObserver()}
+
+	for _, log := range []*logger.Logger{
+		logger.New().SetHandler("wrapper", handler),
+		logger.New().AddHandler("wrapper", handler),
+		logger.New().SetHandlers(logger.Handlers{"wrapper": handler}),
+	} {
+		if _, ok := log.GetHandlers()["wrapper"].(panickingHandler); !ok {
+			test.Errorf("GetHandlers()[%q] = %T; want %T", "wrapper", log.GetHandlers()["wrapper"], handler)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+// Hook defines function that is called by logger worker thread for every log
+// record before dispatching it to added log handlers. It can mutate or enrich
+// provided log record. Returned error skips emission of log record.
+type Hook func(record *Record) error
+
+// namedHook defines added hook with its identifier name.
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// runHook calls provided hook and it recovers from a hook panic.
+func runHook(hook Hook, record *Record) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = NewRuntimeError("hook panicked", recovered)
+		}
+	}()
+
+	return hook(record)
+}
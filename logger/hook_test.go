@@ -0,0 +1,89 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestHookOrder(test *testing.T) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer)
+
+	log.AddHook("first", func(record *logger.Record) error {
+		record.Message += " first"
+		return nil
+	})
+
+	log.AddHook("second", func(record *logger.Record) error {
+		record.Message += " second"
+		return nil
+	})
+
+	log.AddHook("first", func(record *logger.Record) error {
+		record.Message += " replaced"
+		return nil
+	})
+
+	log.Info(testMessage)
+	log.Flush()
+
+	log.RemoveHook("second").Info(testMessage)
+	log.Flush()
+
+	want := testMessage + " replaced second\n" + testMessage + " replaced\n"
+
+	if buffer.String() != want {
+		test.Error("buffer.String() =", buffer.String(), "; want", want)
+	}
+}
+
+func TestHookSkip(test *testing.T) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer)
+
+	log.AddHook("skip", func(record *logger.Record) error {
+		if record.Message == "skip" {
+			return testError
+		}
+
+		return nil
+	})
+
+	log.AddHook("panic", func(record *logger.Record) error {
+		if record.Message == "panic" {
+			panic(testError)
+		}
+
+		return nil
+	})
+
+	log.Info("skip")
+	log.Info("panic")
+	log.Info(testMessage)
+	log.Flush()
+
+	want := testMessage + "\n"
+
+	if buffer.String() != want {
+		test.Error("buffer.String() =", buffer.String(), "; want", want)
+	}
+}
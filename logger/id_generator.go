@@ -14,7 +14,136 @@
 
 package logger
 
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // IDGenerator type that returns generated ID used in log messages.
 type IDGenerator interface {
 	Generate() (id string, err error)
 }
+
+// ContextIDGenerator defines ID generator that generates ID of log record using
+// context set by context-aware log methods like for example InfoContext.
+// Logger calls the GenerateFor method instead of the Generate method for ID
+// generators implementing it. Provided context is nil for log records logged
+// without context.
+type ContextIDGenerator interface {
+	IDGenerator
+	GenerateFor(ctx context.Context, record *Record) (id string, err error)
+}
+
+// IDGeneration defines when logger generates IDs of log records.
+type IDGeneration int
+
+// These constants define ID generation modes.
+const (
+	// IDGenerationAlways generates ID for every emitted log record before
+	// running hooks and emitting it to log handlers.
+	IDGenerationAlways IDGeneration = iota
+
+	// IDGenerationLazy generates ID only on the first call of the Record
+	// GetID method, like for example by the {id} template function or by
+	// the JSON output. Generated ID is memoized and shared by all log
+	// handlers and clones of the same log record.
+	IDGenerationLazy
+
+	// IDGenerationOff never generates IDs of log records.
+	IDGenerationOff
+)
+
+// lazyID represents ID of log record generated on the first access.
+type lazyID struct {
+	once      sync.Once
+	id        string
+	logger    *Logger
+	generator IDGenerator
+}
+
+// get returns ID generated for provided log record on the first call. It is
+// safe to call it concurrently.
+func (l *lazyID) get(record *Record) string {
+	l.once.Do(func() {
+		id, err := generateID(l.generator, record)
+
+		if err != nil {
+			l.logger.printError(NewRuntimeError("cannot generate ID", err))
+		}
+
+		l.id = id
+	})
+
+	return l.id
+}
+
+// generateID generates ID for provided log record using provided ID generator.
+func generateID(generator IDGenerator, record *Record) (id string, err error) {
+	if generator, ok := generator.(ContextIDGenerator); ok {
+		return generator.GenerateFor(record.Context, record)
+	}
+
+	return generator.Generate()
+}
+
+// IDGeneratorConstructor defines ID generator constructor function.
+type IDGeneratorConstructor func() IDGenerator
+
+var gIDGeneratorsMutex sync.RWMutex                         // nolint:gochecknoglobals
+var gIDGenerators = make(map[string]IDGeneratorConstructor) // nolint:gochecknoglobals
+
+// RegisterIDGenerator registers ID generator constructor under provided name.
+// It allows to create ID generators by their names using the CreateIDGenerator
+// function. Registering already registered name replaces its ID generator
+// constructor.
+func RegisterIDGenerator(name string, constructor IDGeneratorConstructor) error {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return NewRuntimeError("cannot register ID generator with empty name")
+	}
+
+	if constructor == nil {
+		return NewRuntimeError("cannot register ID generator {p | printf \"%q\"}, constructor is nil", name)
+	}
+
+	gIDGeneratorsMutex.Lock()
+	defer gIDGeneratorsMutex.Unlock()
+
+	gIDGenerators[name] = constructor
+
+	return nil
+}
+
+// CreateIDGenerator creates a new ID generator using ID generator constructor
+// registered under provided name.
+func CreateIDGenerator(name string) (IDGenerator, error) {
+	gIDGeneratorsMutex.RLock()
+	constructor, ok := gIDGenerators[strings.TrimSpace(name)]
+	gIDGeneratorsMutex.RUnlock()
+
+	if !ok {
+		return nil, NewRuntimeError("cannot create ID generator {p | printf \"%q\"}, ID generator is not registered", name)
+	}
+
+	return constructor(), nil
+}
+
+// RegisteredIDGenerators returns sorted names of all registered ID generator
+// constructors.
+func RegisteredIDGenerators() []string {
+	gIDGeneratorsMutex.RLock()
+	defer gIDGeneratorsMutex.RUnlock()
+
+	names := make([]string, 0, len(gIDGenerators))
+
+	for name := range gIDGenerators {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
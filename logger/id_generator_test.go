@@ -0,0 +1,119 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+type countingIDGenerator struct {
+	count uint64
+}
+
+func (c *countingIDGenerator) Generate() (string, error) {
+	return strings.Repeat("x", int(atomic.AddUint64(&c.count, 1))), nil
+}
+
+func TestIDGeneration(test *testing.T) {
+	for _, tt := range []struct {
+		generation logger.IDGeneration
+		format     string
+		count      uint64
+		want       string
+	}{
+		{logger.IDGenerationAlways, "{message}", 3, "a\nb\nc\n"},
+		{logger.IDGenerationLazy, "{message}", 0, "a\nb\nc\n"},
+		{logger.IDGenerationLazy, "{id}", 3, "x\nxx\nxxx\n"},
+		{logger.IDGenerationOff, "{id}:{message}", 0, ":a\n:b\n:c\n"},
+	} {
+		generator := &countingIDGenerator{}
+		buffer := logger.NewBuffer()
+		buffer.GetFormatter().SetFormat(tt.format)
+
+		log := logger.NewWithOptions(
+			logger.WithHandlers(logger.Handlers{"buffer": buffer}),
+			logger.WithIDGenerator(generator),
+			logger.WithIDGeneration(tt.generation),
+		).SetSynchronous(true)
+
+		if generation := log.GetIDGeneration(); generation != tt.generation {
+			test.Errorf("GetIDGeneration() = %v; want %v", generation, tt.generation)
+		}
+
+		log.Info("a")
+		log.Info("b")
+		log.Info("c")
+
+		if count := atomic.LoadUint64(&generator.count); count != tt.count {
+			test.Errorf("%v: Generate() called %d times; want %d", tt.generation, count, tt.count)
+		}
+
+		if buffer.String() != tt.want {
+			test.Errorf("%v: buffer.String() = %q; want %q", tt.generation, buffer.String(), tt.want)
+		}
+	}
+}
+
+func TestIDGenerationLazyParallel(test *testing.T) {
+	generator := &countingIDGenerator{}
+	buffers := make([]*logger.Buffer, 4)
+	handlers := make(logger.Handlers, len(buffers))
+
+	for index := range buffers {
+		buffers[index] = logger.NewBuffer()
+		buffers[index].GetFormatter().SetFormat("{id}")
+		handlers[strings.Repeat("b", index+1)] = buffers[index]
+	}
+
+	log := logger.New().
+		SetWorker(logger.NewWorker().SetParallelEmit(true)).
+		SetHandlers(handlers).
+		SetIDGenerator(generator).
+		SetIDGeneration(logger.IDGenerationLazy)
+
+	for index := 0; index < 8; index++ {
+		log.Info(testMessage)
+	}
+
+	if err := log.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	if count := atomic.LoadUint64(&generator.count); count != 8 {
+		test.Errorf("Generate() called %d times; want 8", count)
+	}
+
+	for _, buffer := range buffers[1:] {
+		if buffer.String() != buffers[0].String() {
+			test.Errorf("buffer.String() = %q; want %q", buffer.String(), buffers[0].String())
+		}
+	}
+}
+
+func TestRecordGetID(test *testing.T) {
+	record := &logger.Record{ID: "id"}
+
+	if id := record.GetID(); id != "id" {
+		test.Errorf("GetID() = %q; want %q", id, "id")
+	}
+
+	if id := (&logger.Record{}).GetID(); id != "" {
+		test.Errorf("GetID() = %q; want empty", id)
+	}
+}
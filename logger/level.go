@@ -14,8 +14,160 @@
 
 package logger
 
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// These constants define syslog severity values used by the level severity
+// mapping.
+const (
+	EmergencySeverity = 0
+	AlertSeverity     = 1
+	CriticalSeverity  = 2
+	ErrorSeverity     = 3
+	WarningSeverity   = 4
+	NoticeSeverity    = 5
+	InfoSeverity      = 6
+	DebugSeverity     = 7
+)
+
+var gLevelsMutex sync.RWMutex // nolint:gochecknoglobals
+
+var gLevelNames = map[int]string{ // nolint:gochecknoglobals
+	TraceLevel:    TraceName,
+	DebugLevel:    DebugName,
+	InfoLevel:     InfoName,
+	NoticeLevel:   NoticeName,
+	WarningLevel:  WarningName,
+	ErrorLevel:    ErrorName,
+	CriticalLevel: CriticalName,
+	AlertLevel:    AlertName,
+	FatalLevel:    FatalName,
+	PanicLevel:    PanicName,
+}
+
+var gLevelSeverities = map[int]int{ // nolint:gochecknoglobals
+	TraceLevel:    DebugSeverity,
+	DebugLevel:    DebugSeverity,
+	InfoLevel:     InfoSeverity,
+	NoticeLevel:   NoticeSeverity,
+	WarningLevel:  WarningSeverity,
+	ErrorLevel:    ErrorSeverity,
+	CriticalLevel: CriticalSeverity,
+	AlertLevel:    AlertSeverity,
+	FatalLevel:    EmergencySeverity,
+	PanicLevel:    EmergencySeverity,
+}
+
 // Level defines log level information fields.
 type Level struct {
 	Value int    `json:"value"`
 	Name  string `json:"name"`
 }
+
+// RegisterLevel registers custom log level value under provided name. Log
+// level name is used by the Log method when log level name is empty and it is
+// recognized by the ParseLevel function. Registering already registered log
+// level value replaces its name.
+func RegisterLevel(value int, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" {
+		return NewRuntimeError("cannot register level with empty name", value)
+	}
+
+	gLevelsMutex.Lock()
+	defer gLevelsMutex.Unlock()
+
+	for registered, registeredName := range gLevelNames {
+		if (registeredName == name) && (registered != value) {
+			return NewRuntimeError("cannot register level {p | printf \"%q\"}, name is already used", name, registered)
+		}
+	}
+
+	gLevelNames[value] = name
+
+	return nil
+}
+
+// SetLevelSeverity sets syslog severity for log level value. Log level value
+// without set severity uses severity of the nearest lower built-in log level.
+func SetLevelSeverity(value, severity int) error {
+	if (severity < EmergencySeverity) || (severity > DebugSeverity) {
+		return NewRuntimeError("cannot set level severity", value, severity)
+	}
+
+	gLevelsMutex.Lock()
+	defer gLevelsMutex.Unlock()
+
+	gLevelSeverities[value] = severity
+
+	return nil
+}
+
+// GetLevelName returns registered name of log level value. It returns an empty
+// string for not registered log level value.
+func GetLevelName(value int) string {
+	gLevelsMutex.RLock()
+	defer gLevelsMutex.RUnlock()
+
+	return gLevelNames[value]
+}
+
+// GetLevelSeverity returns syslog severity of log level value.
+func GetLevelSeverity(value int) int {
+	gLevelsMutex.RLock()
+	defer gLevelsMutex.RUnlock()
+
+	if severity, ok := gLevelSeverities[value]; ok {
+		return severity
+	}
+
+	levels := [...]int{
+		PanicLevel,
+		FatalLevel,
+		AlertLevel,
+		CriticalLevel,
+		ErrorLevel,
+		WarningLevel,
+		NoticeLevel,
+		InfoLevel,
+		DebugLevel,
+	}
+
+	for _, level := range levels {
+		if level <= value {
+			return gLevelSeverities[level]
+		}
+	}
+
+	return DebugSeverity
+}
+
+// ParseLevel returns log level value from provided registered log level name.
+// Log level name is case insensitive. It accepts also a decimal log level
+// value.
+func ParseLevel(name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	gLevelsMutex.RLock()
+
+	for value, registeredName := range gLevelNames {
+		if registeredName == name {
+			gLevelsMutex.RUnlock()
+			return value, nil
+		}
+	}
+
+	gLevelsMutex.RUnlock()
+
+	value, err := strconv.Atoi(name)
+
+	if err != nil {
+		return 0, NewRuntimeError("cannot parse level", name)
+	}
+
+	return value, nil
+}
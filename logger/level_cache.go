@@ -0,0 +1,92 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// A levelCache represents logger level and level ranges of enabled log
+// handlers cached by logger. It allows to drop log messages by the calling
+// goroutine without taking locks of all log handlers for every log message.
+type levelCache struct {
+	version  uint64
+	level    int
+	hasLevel bool
+	ranges   []levelRange
+}
+
+// A levelRange represents minimum and maximum log level values of log handler.
+type levelRange struct {
+	min int
+	max int
+}
+
+var gLevelVersion uint64 // nolint:gochecknoglobals
+
+// InvalidateLevelCache invalidates cached logger levels and level ranges of
+// log handlers of all loggers. Logger methods and log handlers embedding
+// the HandlerBase call it on every change. Custom log handlers with own level
+// range or enabled state must call it after changing them.
+func InvalidateLevelCache() {
+	atomic.AddUint64(&gLevelVersion, 1)
+}
+
+// isEnabled returns true if log record with provided log level passes cached
+// logger level and level range of at least one of log handlers.
+func (c *levelCache) isEnabled(level int) bool {
+	if c.hasLevel && (level < c.level) {
+		return false
+	}
+
+	for _, r := range c.ranges {
+		if (level >= r.min) && (level <= r.max) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getLevelCache returns cached logger level and level ranges of enabled log
+// handlers. It rebuilds them after invalidation by the InvalidateLevelCache
+// function.
+func (l *Logger) getLevelCache() *levelCache {
+	version := atomic.LoadUint64(&gLevelVersion)
+
+	if cache, ok := l.levels.Load().(*levelCache); ok && (cache.version == version) {
+		return cache
+	}
+
+	cache := &levelCache{
+		version: version,
+	}
+
+	l.mutex.RLock()
+	cache.level, cache.hasLevel = l.getLoggerLevel()
+	handlers := l.getHandlers()
+	l.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		if (handler != nil) && handler.IsEnabled() {
+			min, max := handler.GetLevelRange()
+			cache.ranges = append(cache.ranges, levelRange{min: min, max: max})
+		}
+	}
+
+	l.levels.Store(cache)
+
+	return cache
+}
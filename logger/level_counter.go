@@ -0,0 +1,56 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// levelCounter counts emitted log records per log level.
+type levelCounter struct {
+	counters sync.Map
+}
+
+// add increments counter of provided log level.
+func (c *levelCounter) add(level int) {
+	counter, ok := c.counters.Load(level)
+
+	if !ok {
+		counter, _ = c.counters.LoadOrStore(level, new(uint64))
+	}
+
+	atomic.AddUint64(counter.(*uint64), 1) // nolint:forcetypeassert
+}
+
+// get returns copy of all counters.
+func (c *levelCounter) get() map[int]uint64 {
+	counts := make(map[int]uint64)
+
+	c.counters.Range(func(level, counter interface{}) bool {
+		counts[level.(int)] = atomic.LoadUint64(counter.(*uint64)) // nolint:forcetypeassert
+		return true
+	})
+
+	return counts
+}
+
+// reset sets all counters to zero.
+func (c *levelCounter) reset() {
+	c.counters.Range(func(_, counter interface{}) bool {
+		atomic.StoreUint64(counter.(*uint64), 0) // nolint:forcetypeassert
+		return true
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestLoggerLevelCounts(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	buffer.SetMinimumLevel(logger.InfoLevel)
+
+	log := logger.New().SetHandlers(logger.Handlers{"buffer": buffer})
+
+	defer log.Close()
+
+	var wg sync.WaitGroup
+
+	for index := 0; index < 4; index++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			log.Debug(testMessage)
+			log.Info(testMessage)
+			log.Error(testMessage)
+			log.Error(testMessage)
+		}()
+	}
+
+	wg.Wait()
+	log.Flush()
+
+	want := map[int]uint64{
+		logger.InfoLevel:  4,
+		logger.ErrorLevel: 8,
+	}
+
+	if got := log.LevelCounts(); !reflect.DeepEqual(got, want) {
+		test.Errorf("LevelCounts() = %v; want %v", got, want)
+	}
+
+	log.ResetLevelCounts().Info(testMessage)
+	log.Flush()
+
+	want = map[int]uint64{
+		logger.InfoLevel:  1,
+		logger.ErrorLevel: 0,
+	}
+
+	if got := log.LevelCounts(); !reflect.DeepEqual(got, want) {
+		test.Errorf("LevelCounts() = %v; want %v", got, want)
+	}
+}
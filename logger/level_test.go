@@ -0,0 +1,114 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestParseLevel(test *testing.T) {
+	tests := map[string]int{
+		"trace":  logger.TraceLevel,
+		"Info":   logger.InfoLevel,
+		" ERROR": logger.ErrorLevel,
+		"panic":  logger.PanicLevel,
+		"25":     25,
+	}
+
+	for name, want := range tests {
+		got, err := logger.ParseLevel(name)
+
+		if err != nil {
+			test.Errorf("ParseLevel(%q) returns an unexpected error %v", name, err)
+		}
+
+		if got != want {
+			test.Errorf("ParseLevel(%q) = %d; want %d", name, got, want)
+		}
+	}
+
+	if _, err := logger.ParseLevel("unknown"); err == nil {
+		test.Error("ParseLevel() doesn't return an error for unknown level")
+	}
+}
+
+func TestRegisterLevel(test *testing.T) {
+	const verboseLevel = logger.DebugLevel + 5
+
+	if err := logger.RegisterLevel(verboseLevel, "Verbose"); err != nil {
+		test.Fatal("RegisterLevel() returns an unexpected error", err)
+	}
+
+	if err := logger.RegisterLevel(verboseLevel+1, "verbose"); err == nil {
+		test.Error("RegisterLevel() doesn't return an error for already used name")
+	}
+
+	if err := logger.RegisterLevel(verboseLevel+1, " "); err == nil {
+		test.Error("RegisterLevel() doesn't return an error for empty name")
+	}
+
+	if got, err := logger.ParseLevel("VERBOSE"); (err != nil) || (got != verboseLevel) {
+		test.Errorf("ParseLevel(\"VERBOSE\") = %d, %v; want %d", got, err, verboseLevel)
+	}
+
+	if got := logger.GetLevelName(verboseLevel); got != "verbose" {
+		test.Errorf("GetLevelName() = %q; want \"verbose\"", got)
+	}
+
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{level}: {message}")
+
+	log := logger.New().SetHandler("buffer", buffer)
+
+	log.Log(verboseLevel, "", "custom")
+	log.Flush()
+
+	if want := "verbose: custom\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestLevelSeverity(test *testing.T) {
+	tests := map[int]int{
+		logger.TraceLevel:       logger.DebugSeverity,
+		logger.InfoLevel:        logger.InfoSeverity,
+		logger.InfoLevel + 1:    logger.InfoSeverity,
+		logger.CriticalLevel:    logger.CriticalSeverity,
+		logger.PanicLevel:       logger.EmergencySeverity,
+		logger.MaximumLevel + 1: logger.EmergencySeverity,
+	}
+
+	for level, want := range tests {
+		if got := logger.GetLevelSeverity(level); got != want {
+			test.Errorf("GetLevelSeverity(%d) = %d; want %d", level, got, want)
+		}
+	}
+
+	const customLevel = logger.NoticeLevel + 3
+
+	if err := logger.SetLevelSeverity(customLevel, logger.WarningSeverity); err != nil {
+		test.Fatal("SetLevelSeverity() returns an unexpected error", err)
+	}
+
+	if got := logger.GetLevelSeverity(customLevel); got != logger.WarningSeverity {
+		test.Errorf("GetLevelSeverity() = %d; want %d", got, logger.WarningSeverity)
+	}
+
+	if err := logger.SetLevelSeverity(customLevel, logger.DebugSeverity+1); err == nil {
+		test.Error("SetLevelSeverity() doesn't return an error for invalid severity")
+	}
+}
@@ -15,9 +15,14 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"math"
 	"os"
+	"os/signal"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -56,39 +61,134 @@ const (
 
 	DefaultErrorCode = 1
 
+	DisabledFlushLevel = math.MaxInt32
+
 	loggerSkipCall = 2
+
+	maximumCallerDepth = 32
 )
 
+// ExitFunc defines function called by the Fatal method to exit the
+// application with provided error code.
+type ExitFunc func(code int)
+
 // A Logger represents an active logging object that generates log messages for
 // different added log handlers. Each logging operations creates and sends
 // lightweight not formatted log message to separate worker thread. It offloads
 // main code from unnecessary resource consuming formatting and I/O operations.
 type Logger struct {
-	name        string
-	handlers    Handlers
-	idGenerator IDGenerator
-	errorCode   int
-	mutex       sync.RWMutex
+	name           string
+	address        string
+	parent         *Logger
+	inherit        bool
+	handlers       Handlers
+	hooks          []namedHook
+	worker         *Worker
+	idGenerator    IDGenerator
+	idGeneration   IDGeneration
+	errorCode      int
+	callerSkip     int
+	callerOff      bool
+	level          int
+	hasLevel       bool
+	levels         atomic.Value
+	pooling        bool
+	flushLevel     int
+	overflow       OverflowPolicy
+	enqueueTimeout time.Duration
+	dropped        dropCounter
+	levelCounts    levelCounter
+	signals        chan os.Signal
+	helpers        map[string]struct{}
+	labels         map[string]string
+	defaultFields  Named
+	defaultFormat  string
+	exitFunc       ExitFunc
+	synchronous    bool
+	shutdown       uint32
+	errorReporter  errorReporter
+	mutex          sync.RWMutex
 }
 
 // New creates new logger instance with default handlers.
 func New() *Logger {
 	return &Logger{
-		handlers: Handlers{
-			"stdout": NewStdout(),
-			"stderr": NewStderr(),
-		},
+		handlers:    newDefaultHandlers(),
 		errorCode:   DefaultErrorCode,
+		flushLevel:  DisabledFlushLevel,
+		exitFunc:    os.Exit,
 		idGenerator: NewUUID4(),
 	}
 }
 
+// Clone creates a new independent logger instance with copied configuration
+// from logger. Handlers map is copied but added log handlers are shared
+// between both loggers, including their formatters and underlying opened
+// file descriptors or network connections. They are not reopened. Adding,
+// removing or replacing log handlers in a cloned logger doesn't affect
+// the original one. Closing a cloned logger closes shared log handlers.
+//
+// Name, address, error code, caller skip, exit function and synchronous mode
+// are copied. ID generator, hooks, internal error handler and logger worker
+// thread are shared. Changing configuration of shared log handlers, like
+// for example by the SetLevel or SetFormat methods, affects both loggers.
+func (l *Logger) Clone() *Logger {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	var handlers Handlers
+
+	if l.handlers != nil {
+		handlers = make(Handlers, len(l.handlers))
+
+		for name, handler := range l.handlers {
+			handlers[name] = handler
+		}
+	}
+
+	clone := &Logger{
+		name:           l.name,
+		parent:         l.parent,
+		inherit:        l.inherit,
+		handlers:       handlers,
+		hooks:          l.hooks,
+		idGenerator:    l.idGenerator,
+		idGeneration:   l.idGeneration,
+		errorCode:      l.errorCode,
+		callerSkip:     l.callerSkip,
+		callerOff:      l.callerOff,
+		level:          l.level,
+		hasLevel:       l.hasLevel,
+		pooling:        l.pooling,
+		flushLevel:     l.flushLevel,
+		overflow:       l.overflow,
+		enqueueTimeout: l.enqueueTimeout,
+		helpers:        l.helpers,
+		labels:         l.labels,
+		defaultFields:  l.defaultFields,
+		defaultFormat:  l.defaultFormat,
+		address:        l.address,
+		exitFunc:       l.exitFunc,
+		synchronous:    l.synchronous,
+		worker:         l.worker,
+	}
+
+	clone.errorReporter.set(l.errorReporter.get())
+
+	return clone
+}
+
+// newDefaultHandlers creates default log handlers.
+func newDefaultHandlers() Handlers {
+	return Handlers{
+		"stdout": NewStdout().SetHandlerName("stdout"),
+		"stderr": NewStderr().SetHandlerName("stderr"),
+	}
+}
+
 // Enable enables all added log handlers.
 func (l *Logger) Enable() *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("Enable") {
 		handler.Enable()
 	}
 
@@ -97,10 +197,7 @@ func (l *Logger) Enable() *Logger {
 
 // Disable disabled all added log handlers.
 func (l *Logger) Disable() *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("Disable") {
 		handler.Disable()
 	}
 
@@ -112,7 +209,7 @@ func (l *Logger) IsEnabled() bool {
 	l.mutex.RLock()
 	defer l.mutex.RUnlock()
 
-	for _, handler := range l.handlers {
+	for _, handler := range l.getHandlers() {
 		if handler.IsEnabled() {
 			return true
 		}
@@ -123,22 +220,73 @@ func (l *Logger) IsEnabled() bool {
 
 // SetLevel sets log level to all added log handlers.
 func (l *Logger) SetLevel(level int) *Logger {
+	for _, handler := range l.ownHandlers("SetLevel") {
+		handler.SetLevel(level)
+	}
+
+	return l
+}
+
+// SetLoggerLevel sets logger level. Log messages with log level lower than
+// logger level are dropped by the calling goroutine before creating log
+// record. It is independent from log handlers levels. Without set logger level
+// log messages are dropped when none of log handlers would emit them. Named
+// logger without own logger level inherits it from the nearest parent logger.
+func (l *Logger) SetLoggerLevel(level int) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	for _, handler := range l.handlers {
-		handler.SetLevel(level)
-	}
+	l.level = level
+	l.hasLevel = true
+
+	InvalidateLevelCache()
 
 	return l
 }
 
-// SetMinimumLevel sets minimum log level to all added log handlers.
-func (l *Logger) SetMinimumLevel(level int) *Logger {
+// GetLoggerLevel returns logger level. It returns the minimum log level value
+// if logger level is not set.
+func (l *Logger) GetLoggerLevel() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if level, ok := l.getLoggerLevel(); ok {
+		return level
+	}
+
+	return MinimumLevel
+}
+
+// ResetLoggerLevel unsets logger level.
+func (l *Logger) ResetLoggerLevel() *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	for _, handler := range l.handlers {
+	l.hasLevel = false
+
+	InvalidateLevelCache()
+
+	return l
+}
+
+// getLoggerLevel returns own logger level or logger level inherited from
+// the nearest parent logger. Caller must hold the logger lock.
+func (l *Logger) getLoggerLevel() (int, bool) {
+	if l.hasLevel || !l.inherit {
+		return l.level, l.hasLevel
+	}
+
+	parent := l.getParent()
+
+	parent.mutex.RLock()
+	defer parent.mutex.RUnlock()
+
+	return parent.getLoggerLevel()
+}
+
+// SetMinimumLevel sets minimum log level to all added log handlers.
+func (l *Logger) SetMinimumLevel(level int) *Logger {
+	for _, handler := range l.ownHandlers("SetMinimumLevel") {
 		handler.SetMinimumLevel(level)
 	}
 
@@ -147,10 +295,7 @@ func (l *Logger) SetMinimumLevel(level int) *Logger {
 
 // SetMaximumLevel sets maximum log level to all added log handlers.
 func (l *Logger) SetMaximumLevel(level int) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("SetMaximumLevel") {
 		handler.SetMaximumLevel(level)
 	}
 
@@ -159,46 +304,96 @@ func (l *Logger) SetMaximumLevel(level int) *Logger {
 
 // SetLevelRange sets minimum and maximum log level values to all added log handlers.
 func (l *Logger) SetLevelRange(min, max int) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("SetLevelRange") {
 		handler.SetLevelRange(min, max)
 	}
 
 	return l
 }
 
+// GetLevelRange returns union of log level ranges of all enabled log handlers,
+// the lowest minimum and the highest maximum log level values. Without
+// enabled log handlers it returns inverted range with maximum lower than
+// minimum, nothing is logged. Named logger without own log handlers returns
+// level range of log handlers inherited from its parent logger.
+func (l *Logger) GetLevelRange() (min, max int) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	min, max = math.MaxInt32, math.MinInt32
+
+	for _, handler := range l.getHandlers() {
+		if handler.IsEnabled() {
+			handlerMin, handlerMax := handler.GetLevelRange()
+
+			if handlerMin < min {
+				min = handlerMin
+			}
+
+			if handlerMax > max {
+				max = handlerMax
+			}
+		}
+	}
+
+	return min, max
+}
+
 // SetFormatter sets provided formatter to all added log handlers.
 func (l *Logger) SetFormatter(formatter *Formatter) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("SetFormatter") {
 		handler.SetFormatter(formatter)
 	}
 
 	return l
 }
 
-// SetFormat sets provided format string to all added log handlers.
+// SetFormat sets provided format string to all added log handlers. Log
+// handlers added later keep their own format string, use the SetDefaultFormat
+// method to set format string also for them.
 func (l *Logger) SetFormat(format string) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("SetFormat") {
 		handler.GetFormatter().SetFormat(format)
 	}
 
 	return l
 }
 
-// SetDateFormat sets provided date format string to all added log handlers.
-func (l *Logger) SetDateFormat(format string) *Logger {
+// SetDefaultFormat sets default format string for log handlers added later by
+// the AddHandler, SetHandler, SetHandlers or ResetHandlers methods. Unlike
+// the SetFormat method that changes format string only of already added log
+// handlers, it doesn't change them. Empty format string keeps format of added
+// log handlers unchanged and it is the default.
+func (l *Logger) SetDefaultFormat(format string) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	for _, handler := range l.handlers {
+	l.defaultFormat = format
+
+	return l
+}
+
+// GetDefaultFormat returns default format string for log handlers added later.
+func (l *Logger) GetDefaultFormat() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.defaultFormat
+}
+
+// applyDefaultFormat sets default format string to provided log handler.
+// Caller must hold the logger lock.
+func (l *Logger) applyDefaultFormat(handler Handler) Handler {
+	if l.defaultFormat != "" {
+		handler.GetFormatter().SetFormat(l.defaultFormat)
+	}
+
+	return handler
+}
+
+// SetDateFormat sets provided date format string to all added log handlers.
+func (l *Logger) SetDateFormat(format string) *Logger {
+	for _, handler := range l.ownHandlers("SetDateFormat") {
 		handler.GetFormatter().SetDateFormat(format)
 	}
 
@@ -207,10 +402,7 @@ func (l *Logger) SetDateFormat(format string) *Logger {
 
 // SetPlaceholder sets provided placeholder string to all added log handlers.
 func (l *Logger) SetPlaceholder(placeholder string) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("SetPlaceholder") {
 		handler.GetFormatter().SetPlaceholder(placeholder)
 	}
 
@@ -219,10 +411,7 @@ func (l *Logger) SetPlaceholder(placeholder string) *Logger {
 
 // AddFuncs adds template functions to format log message to all added log handlers.
 func (l *Logger) AddFuncs(funcs FormatterFuncs) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("AddFuncs") {
 		handler.GetFormatter().AddFuncs(funcs)
 	}
 
@@ -231,10 +420,7 @@ func (l *Logger) AddFuncs(funcs FormatterFuncs) *Logger {
 
 // ResetFormatters resets all formatters from added log handlers.
 func (l *Logger) ResetFormatters() *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, handler := range l.handlers {
+	for _, handler := range l.ownHandlers("ResetFormatters") {
 		handler.GetFormatter().Reset()
 	}
 
@@ -260,292 +446,1314 @@ func (l *Logger) GetErrorCode() int {
 	return l.errorCode
 }
 
-// SetName sets logger name.
-func (l *Logger) SetName(name string) *Logger {
+// SetInternalErrorHandler sets internal error handler for logger. It receives
+// internal errors like for example cannot emit record, cannot generate ID or
+// cannot format. Setting it to nil restores the global internal error handler.
+func (l *Logger) SetInternalErrorHandler(handler ErrorHandler) *Logger {
+	l.errorReporter.set(handler)
+
+	return l
+}
+
+// GetInternalErrorHandler returns internal error handler.
+func (l *Logger) GetInternalErrorHandler() ErrorHandler {
+	return l.errorReporter.get()
+}
+
+// SetExitFunc sets function called by the Fatal method to exit the
+// application. On default it is os.Exit. Setting it to nil disables exiting
+// the application.
+func (l *Logger) SetExitFunc(exitFunc ExitFunc) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	l.name = name
+	l.exitFunc = exitFunc
 
 	return l
 }
 
-// GetName returns logger name.
-func (l *Logger) GetName() string {
+// GetExitFunc returns function called by the Fatal method to exit the
+// application.
+func (l *Logger) GetExitFunc() ExitFunc {
 	l.mutex.RLock()
 	defer l.mutex.RUnlock()
 
-	return l.name
+	return l.exitFunc
 }
 
-// AddHandler sets log handler under provided identifier name.
-func (l *Logger) AddHandler(name string, handler Handler) *Logger {
+// SetCallerSkip sets number of additional stack frames to skip when logger
+// captures source location of log message. It is useful for wrapper packages
+// that call logger methods on behalf of the real caller.
+func (l *Logger) SetCallerSkip(delta int) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	l.handlers[name] = handler
+	l.callerSkip = delta
 
 	return l
 }
 
-// SetHandler sets a single log handler for logger. It is equivalent to
-// logger.RemoveHandlers().SetHandlers(logger.Handlers{name: handler}).
-func (l *Logger) SetHandler(name string, handler Handler) *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	l.handlers = Handlers{name: handler}
+// GetCallerSkip returns number of additional stack frames to skip when logger
+// captures source location of log message.
+func (l *Logger) GetCallerSkip() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
 
-	return l
+	return l.callerSkip
 }
 
-// SetHandlers sets log handlers for logger.
-func (l *Logger) SetHandlers(handlers Handlers) *Logger {
+// SetCaptureCaller enables or disables capturing of source location of log
+// message. On default it is enabled. With disabled capturing log record file
+// fields are zeroed and related template functions are rendered empty.
+// Log records rejected by logger level or by all log handlers are not created
+// at all. Panic log records capture source location always.
+func (l *Logger) SetCaptureCaller(capture bool) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	l.handlers = handlers
+	l.callerOff = !capture
 
 	return l
 }
 
-// GetHandler returns added log handler by provided name.
-func (l *Logger) GetHandler(name string) (Handler, error) {
+// IsCallerCaptured returns true if capturing of source location of log
+// message is enabled.
+func (l *Logger) IsCallerCaptured() bool {
 	l.mutex.RLock()
 	defer l.mutex.RUnlock()
 
-	handler, ok := l.handlers[name]
+	return !l.callerOff
+}
 
-	if !ok {
-		return nil, NewRuntimeError("cannot get handler", name)
-	}
+// WithCallerSkip returns a cloned logger with number of additional stack
+// frames to skip increased by provided delta value.
+func (l *Logger) WithCallerSkip(delta int) *Logger {
+	clone := l.Clone()
+	clone.callerSkip += delta
 
-	return handler, nil
+	return clone
 }
 
-// GetHandlers returns all added log handlers.
-func (l *Logger) GetHandlers() Handlers {
-	l.mutex.RLock()
-	defer l.mutex.RUnlock()
+// Helper marks the calling function as a logger helper function. When logger
+// captures source location of log message, marked functions are skipped like
+// with the testing.T.Helper method.
+func (l *Logger) Helper() {
+	pc, _, _, ok := runtime.Caller(1)
 
-	return l.handlers
-}
+	if !ok {
+		return
+	}
+
+	name := runtime.FuncForPC(pc).Name()
 
-// RemoveHandler removes added log handler by provided name.
-func (l *Logger) RemoveHandler(name string) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	delete(l.handlers, name)
+	if _, ok := l.helpers[name]; ok {
+		return
+	}
 
-	return l
+	helpers := make(map[string]struct{}, len(l.helpers)+1)
+
+	for helper := range l.helpers {
+		helpers[helper] = struct{}{}
+	}
+
+	helpers[name] = struct{}{}
+	l.helpers = helpers
 }
 
-// RemoveHandlers removes all added log handlers.
-func (l *Logger) RemoveHandlers() *Logger {
+// SetFlushLevel sets flush level. Logging message with log level greater than
+// or equal to flush level flushes all log messages before returning. It trades
+// throughput for durability of important log messages. On default it is
+// DisabledFlushLevel.
+func (l *Logger) SetFlushLevel(level int) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	l.handlers = make(Handlers)
+	l.flushLevel = level
 
 	return l
 }
 
-// ResetHandlers sets logger default log handlers.
-func (l *Logger) ResetHandlers() *Logger {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	l.handlers = Handlers{
-		"stdout": NewStdout(),
-		"stderr": NewStderr(),
-	}
+// GetFlushLevel returns flush level.
+func (l *Logger) GetFlushLevel() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
 
-	return l
+	return l.flushLevel
 }
 
-// Reset resets logger to default state and default log handlers.
-func (l *Logger) Reset() *Logger {
+// SetOverflowPolicy sets behavior of logger when logger worker thread queue
+// is full. On default it is Block. Dropped log records are counted and
+// periodically reported by a synthesized warning log record.
+func (l *Logger) SetOverflowPolicy(policy OverflowPolicy) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	l.idGenerator = NewUUID4()
-	l.errorCode = DefaultErrorCode
-	l.handlers = Handlers{
-		"stdout": NewStdout(),
-		"stderr": NewStderr(),
-	}
+	l.overflow = policy
 
 	return l
 }
 
-// SetIDGenerator sets ID generator function that is called by logger to
-// generate ID for created log messages.
-func (l *Logger) SetIDGenerator(idGenerator IDGenerator) *Logger {
+// GetOverflowPolicy returns behavior of logger when logger worker thread
+// queue is full.
+func (l *Logger) GetOverflowPolicy() OverflowPolicy {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.overflow
+}
+
+// SetEnqueueTimeout sets maximum duration for which logging blocks when
+// logger worker thread queue is full. After that log record is dropped
+// according to the overflow policy, the Block overflow policy drops the newest
+// log record. It allows to prefer latency over losing log records without
+// stalling the calling goroutine forever. Zero disables it.
+func (l *Logger) SetEnqueueTimeout(timeout time.Duration) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	l.idGenerator = idGenerator
+	l.enqueueTimeout = timeout
 
 	return l
 }
 
-// GetIDGenerator returns ID generator function that is called by logger to
-// generate ID for created log messages.
-func (l *Logger) GetIDGenerator() IDGenerator {
+// GetEnqueueTimeout returns maximum duration for which logging blocks when
+// logger worker thread queue is full.
+func (l *Logger) GetEnqueueTimeout() time.Duration {
 	l.mutex.RLock()
 	defer l.mutex.RUnlock()
 
-	return l.idGenerator
+	return l.enqueueTimeout
 }
 
-// Trace logs finer-grained informational messages than the Debug. It creates
-// and sends lightweight not formatted log messages to separate running logger
-// thread for further formatting and I/O handling from different added log
-// handlers.
-func (l *Logger) Trace(message string, arguments ...interface{}) {
-	l.LogMessage(TraceLevel, TraceName, message, arguments...)
+// GetDroppedCount returns total number of dropped log records.
+func (l *Logger) GetDroppedCount() uint64 {
+	return atomic.LoadUint64(&l.dropped.total)
 }
 
-// Debug logs debugging messages. It creates and sends lightweight not formatted
-// log messages to separate running logger thread for further formatting and
-// I/O handling from different added log handlers.
-func (l *Logger) Debug(message string, arguments ...interface{}) {
-	l.LogMessage(DebugLevel, DebugName, message, arguments...)
+// LevelCounts returns number of log records emitted by logger per log level
+// value. Log record is counted when it passes log level range of at least one
+// enabled log handler.
+func (l *Logger) LevelCounts() map[int]uint64 {
+	return l.levelCounts.get()
 }
 
-// Info logs informational messages. It creates and sends lightweight not
-// formatted log messages to separate running logger thread for further
-// formatting and I/O handling from different added log handlers.
-func (l *Logger) Info(message string, arguments ...interface{}) {
-	l.LogMessage(InfoLevel, InfoName, message, arguments...)
+// ResetLevelCounts sets numbers of log records emitted by logger per log level
+// value to zero.
+func (l *Logger) ResetLevelCounts() *Logger {
+	l.levelCounts.reset()
+	return l
 }
 
-// Notice logs messages for significant conditions. It creates and sends
-// lightweight not formatted log messages to separate running logger thread for
-// further formatting and I/O handling from different added log handlers.
-func (l *Logger) Notice(message string, arguments ...interface{}) {
-	l.LogMessage(NoticeLevel, NoticeName, message, arguments...)
-}
+// SetRecordPooling enables or disables reusing of log records. With enabled
+// pooling log records are taken from a shared pool and they are returned to it
+// after emitting them to all log handlers. Hooks and log handlers must not
+// keep provided log record after returning unless they call the Record.Retain
+// method or they keep a copy from the Record.Clone method. Panic log records
+// and log records provided to the Emit method are never pooled.
+func (l *Logger) SetRecordPooling(pooling bool) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 
-// Warning logs messages for warning conditions that can be potentially harmful.
-// It creates and sends lightweight not formatted log messages to separate
-// running logger thread for further formatting and I/O handling from different
-// added log handlers.
-func (l *Logger) Warning(message string, arguments ...interface{}) {
-	l.LogMessage(WarningLevel, WarningName, message, arguments...)
-}
+	l.pooling = pooling
 
-// Error logs messages for error conditions. It creates and sends lightweight
-// not formatted log messages to separate running logger thread for further
-// formatting and I/O handling from different log handlers.
-func (l *Logger) Error(message string, arguments ...interface{}) {
-	l.LogMessage(ErrorLevel, ErrorName, message, arguments...)
+	return l
 }
 
-// Critical logs messages for critical conditions. It creates and sends
-// lightweight not formatted log messages to separate running logger thread for
-// further formatting and I/O handling from different added log handlers.
-func (l *Logger) Critical(message string, arguments ...interface{}) {
-	l.LogMessage(CriticalLevel, CriticalName, message, arguments...)
-}
+// IsRecordPooling returns true if reusing of log records is enabled.
+func (l *Logger) IsRecordPooling() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
 
-// Alert logs messages for alert conditions. It creates and sends lightweight
-// not formatted log messages to separate running logger thread for further
-// formatting and I/O handling from different added log handlers.
-func (l *Logger) Alert(message string, arguments ...interface{}) {
-	l.LogMessage(AlertLevel, AlertName, message, arguments...)
+	return l.pooling
 }
 
-// Fatal logs messages for fatal conditions. It stops logger worker thread and
-// it exists the application with an error code. It creates and sends
-// lightweight not formatted log messages to separate running logger thread for
-// further formatting and I/O handling from different added log handlers.
-func (l *Logger) Fatal(message string, arguments ...interface{}) {
-	l.LogMessage(FatalLevel, FatalName, message, arguments...)
-	Close()
-	os.Exit(l.errorCode) // revive:disable-line
-}
+// SetSynchronous enables or disables synchronous mode. In synchronous mode
+// log records are formatted and emitted directly by the calling goroutine
+// instead of separate logger worker thread and the Flush method does nothing.
+func (l *Logger) SetSynchronous(synchronous bool) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 
-// Panic logs messages for fatal conditions. It stops logger worker thread and
-// it exists the application with a panic. It creates and sends lightweight not
-// formatted log messages to separate running logger thread for further
-// formatting and I/O handling from different added log handlers.
-func (l *Logger) Panic(message string, arguments ...interface{}) {
-	l.LogMessage(PanicLevel, PanicName, message, arguments...)
-	Close()
-	panic(NewRuntimeError("Panic error"))
-}
+	l.synchronous = synchronous
 
-// Log logs messages with user defined log level value and name. It creates and
-// sends lightweight not formatted log messages to separate running logger
-// thread for further formatting and I/O handling from different added log
-// handlers.
-func (l *Logger) Log(level int, levelName, message string, arguments ...interface{}) {
-	l.LogMessage(level, levelName, message, arguments...)
+	return l
 }
 
-// Flush flushes all log messages.
-func (l *Logger) Flush() *Logger {
-	GetWorker().Flush()
+// IsSynchronous returns true if synchronous mode is enabled.
+func (l *Logger) IsSynchronous() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
 
-	return l
+	return l.synchronous
 }
 
-// Close closes all added log handlers.
-func (l *Logger) Close() error {
-	GetWorker().Flush()
-
+// SetWorker sets logger worker thread used by logger. On default logger uses
+// the global logger worker thread shared with other loggers. Setting own
+// logger worker thread isolates logger from other loggers. Provided logger
+// worker thread is stopped by the Close method. Setting it to nil restores
+// the global logger worker thread.
+func (l *Logger) SetWorker(worker *Worker) *Logger {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	var err error
+	l.worker = worker
 
-	for _, handler := range l.handlers {
-		handlerError := handler.Close()
+	return l
+}
 
-		if handlerError != nil {
-			err = NewRuntimeError("cannot close log handler", handlerError)
-			printError(err)
-		}
-	}
+// StopWorker flushes all log messages and it stops logger worker thread used
+// by logger. Unlike the Worker Stop method, stopped logger worker thread is
+// not restarted by logged messages and they are emitted synchronously by
+// calling goroutines, so no log messages are lost or blocked after it, like
+// for example during application shutdown. The global logger worker thread is
+// shared by loggers and stopping it affects all of them.
+func (l *Logger) StopWorker() *Logger {
+	l.GetWorker().halt()
 
-	return err
+	return l
 }
 
-// CloseDefer is a small helper function that invokes the .Close() method
-// and it does an error checking with logging. Useful when using with
-// the defer keyword to avoid creating an anonymous function wrapper only
-// to check for an error manually and passing the errcheck linter.
+// StartWorker starts logger worker thread used by logger stopped by
+// the StopWorker method.
+func (l *Logger) StartWorker() *Logger {
+	l.GetWorker().Start()
+
+	return l
+}
+
+// GetWorker returns logger worker thread used by logger.
+func (l *Logger) GetWorker() *Worker {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if l.worker == nil {
+		return GetWorker()
+	}
+
+	return l.worker
+}
+
+// SetName sets logger name.
+func (l *Logger) SetName(name string) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.name = name
+
+	return l
+}
+
+// GetName returns logger name.
+func (l *Logger) GetName() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.name
+}
+
+// SetAddress sets IP address set in log records instead of cached local IP
+// address resolved from local network interfaces. Empty address restores
+// the cached local IP address.
+func (l *Logger) SetAddress(address string) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.address = address
+
+	return l
+}
+
+// GetAddress returns IP address set in log records. It returns cached local
+// IP address if it is not set by the SetAddress method.
+func (l *Logger) GetAddress() string {
+	address, _ := l.getAddress()
+	return address
+}
+
+// getAddress returns IP address set by the SetAddress method or cached local
+// IP address.
+func (l *Logger) getAddress() (string, error) {
+	l.mutex.RLock()
+	address := l.address
+	l.mutex.RUnlock()
+
+	if address != "" {
+		return address, nil
+	}
+
+	return gAddress.get()
+}
+
+// AddHandler sets log handler under provided identifier name. Identifier name
+// is kept by logger and it is used in reported errors, so the same log handler
+// can be added under different identifier names or to different loggers. Log
+// handler without name gets provided identifier name as its name.
+func (l *Logger) AddHandler(name string, handler Handler) *Logger {
+	if handler == nil {
+		l.printError(NewRuntimeError("cannot add nil handler {p | printf \"%q\"}", name))
+		return l
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	handlers := copyHandlers(l.handlers, 1)
+	handlers[name] = l.applyDefaultFormat(nameHandler(name, handler))
+	l.handlers = handlers
+
+	InvalidateLevelCache()
+
+	return l
+}
+
+// SetHandler sets a single log handler for logger. It is equivalent to
+// logger.RemoveHandlers().SetHandlers(logger.Handlers{name: handler}).
+func (l *Logger) SetHandler(name string, handler Handler) *Logger {
+	if handler == nil {
+		l.printError(NewRuntimeError("cannot set nil handler {p | printf \"%q\"}", name))
+		return l
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.handlers = Handlers{name: l.applyDefaultFormat(nameHandler(name, handler))}
+
+	InvalidateLevelCache()
+
+	return l
+}
+
+// SetHandlers sets log handlers for logger. Setting it to nil for a named
+// logger created by the GetLogger function restores inheriting of log
+// handlers from its parent logger. Nil log handlers are reported as errors
+// and they are not set.
+func (l *Logger) SetHandlers(handlers Handlers) *Logger {
+	for name, handler := range handlers {
+		if handler == nil {
+			l.printError(NewRuntimeError("cannot set nil handler {p | printf \"%q\"}", name))
+		}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if handlers == nil {
+		l.handlers = nil
+		InvalidateLevelCache()
+
+		return l
+	}
+
+	handlers = copyHandlers(handlers, 0)
+
+	for name, handler := range handlers {
+		l.applyDefaultFormat(nameHandler(name, handler))
+	}
+
+	l.handlers = handlers
+
+	InvalidateLevelCache()
+
+	return l
+}
+
+// nameHandler sets provided identifier name as log handler name if log handler
+// has no name yet. Log handler name set before adding it or by first adding is
+// kept.
+func nameHandler(name string, handler Handler) Handler {
+	if handler.GetHandlerName() == "" {
+		handler.SetHandlerName(name)
+	}
+
+	return handler
+}
+
+// copyHandlers returns copy of provided log handlers without nil log handlers
+// with capacity for extra log handlers. Added log handlers are never changed
+// in place, they are replaced by a changed copy. It allows to iterate them
+// after releasing the logger lock, like for example by child loggers that
+// inherit them.
+func copyHandlers(handlers Handlers, extra int) Handlers {
+	copied := make(Handlers, len(handlers)+extra)
+
+	for name, handler := range handlers {
+		if handler != nil {
+			copied[name] = handler
+		}
+	}
+
+	return copied
+}
+
+// GetHandler returns added log handler by provided name.
+func (l *Logger) GetHandler(name string) (Handler, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	handler, ok := l.getHandlers()[name]
+
+	if !ok {
+		return nil, NewRuntimeError("cannot get handler", name)
+	}
+
+	return handler, nil
+}
+
+// GetHandlers returns all added log handlers. Named logger without own log
+// handlers returns log handlers inherited from the nearest parent logger.
+func (l *Logger) GetHandlers() Handlers {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.getHandlers()
+}
+
+// ownHandlers returns own log handlers for provided method that changes all
+// added log handlers. Named logger that inherits log handlers from its parent
+// logger has no own log handlers and it reports an error, because changing
+// inherited log handlers would change them also for its parent logger. Error
+// is reported without holding the logger lock. Added log handlers are never
+// changed in place, so returned log handlers can be used without the logger
+// lock.
+func (l *Logger) ownHandlers(method string) Handlers {
+	l.mutex.RLock()
+	handlers, inherited := l.handlers, (l.handlers == nil) && l.inherit
+	l.mutex.RUnlock()
+
+	if inherited {
+		l.printError(NewRuntimeError("cannot call {p} on inherited log handlers, set own log handlers first", method))
+	}
+
+	return handlers
+}
+
+// getHandlers returns own log handlers or log handlers inherited from
+// the nearest parent logger. Caller must hold the logger lock.
+func (l *Logger) getHandlers() Handlers {
+	if (l.handlers != nil) || !l.inherit {
+		return l.handlers
+	}
+
+	return l.getParent().GetHandlers()
+}
+
+// SetLabels sets labels attached to every log record generated by logger.
+// Named logger created by the GetLogger function merges them with labels of
+// its parent logger and own labels override parent labels with the same key.
+// Setting it to nil removes own labels.
+func (l *Logger) SetLabels(labels map[string]string) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.labels = nil
+
+	if labels != nil {
+		l.labels = make(map[string]string, len(labels))
+
+		for key, value := range labels {
+			l.labels[key] = value
+		}
+	}
+
+	return l
+}
+
+// GetLabels returns labels attached to every log record generated by logger
+// including labels inherited from parent loggers.
+func (l *Logger) GetLabels() map[string]string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.getLabels()
+}
+
+// getLabels returns new map with own labels merged over labels inherited from
+// parent loggers. It returns nil without any labels. Caller must hold the
+// logger lock.
+func (l *Logger) getLabels() map[string]string {
+	var labels map[string]string
+
+	if l.inherit {
+		labels = l.getParent().GetLabels()
+	}
+
+	if (labels == nil) && (len(l.labels) != 0) {
+		labels = make(map[string]string, len(l.labels))
+	}
+
+	for key, value := range l.labels {
+		labels[key] = value
+	}
+
+	return labels
+}
+
+// SetDefaultFields sets named values attached to every log record generated
+// by logger, like for example service name or version. They are rendered by
+// formatter before log message and they are available as named placeholders.
+// Named values from log arguments override default fields with the same key.
+// Named logger created by the GetLogger function merges them with default
+// fields of its parent logger. Setting it to nil removes own default fields.
+func (l *Logger) SetDefaultFields(fields Named) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.defaultFields = nil
+
+	if fields != nil {
+		l.defaultFields = make(Named, len(fields))
+
+		for key, value := range fields {
+			l.defaultFields[key] = value
+		}
+	}
+
+	return l
+}
+
+// GetDefaultFields returns named values attached to every log record
+// generated by logger including default fields inherited from parent loggers.
+func (l *Logger) GetDefaultFields() Named {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.getDefaultFields()
+}
+
+// getDefaultFields returns new map with own default fields merged over default
+// fields inherited from parent loggers. It returns nil without any default
+// fields. Caller must hold the logger lock.
+func (l *Logger) getDefaultFields() Named {
+	var fields Named
+
+	if l.inherit {
+		fields = l.getParent().GetDefaultFields()
+	}
+
+	if (fields == nil) && (len(l.defaultFields) != 0) {
+		fields = make(Named, len(l.defaultFields))
+	}
+
+	for key, value := range l.defaultFields {
+		fields[key] = value
+	}
+
+	return fields
+}
+
+// getParent returns parent logger of named logger. Top-level named loggers
+// have the global logger as their parent.
+func (l *Logger) getParent() *Logger {
+	if l.parent == nil {
+		return Get()
+	}
+
+	return l.parent
+}
+
+// RemoveHandler removes added log handler by provided name.
+func (l *Logger) RemoveHandler(name string) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.handlers[name]; ok {
+		handlers := copyHandlers(l.handlers, 0)
+		delete(handlers, name)
+		l.handlers = handlers
+
+		InvalidateLevelCache()
+	}
+
+	return l
+}
+
+// RemoveHandlers removes all added log handlers.
+func (l *Logger) RemoveHandlers() *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.handlers = make(Handlers)
+
+	InvalidateLevelCache()
+
+	return l
+}
+
+// ResetHandlers sets logger default log handlers. Named logger created by
+// the GetLogger function inherits log handlers from its parent logger again.
+func (l *Logger) ResetHandlers() *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.resetHandlers()
+
+	return l
+}
+
+// Reset resets logger to default state and default log handlers.
+func (l *Logger) Reset() *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.hooks = nil
+	l.synchronous = false
+	l.errorReporter.set(nil)
+	l.idGenerator = NewUUID4()
+	l.idGeneration = IDGenerationAlways
+	l.errorCode = DefaultErrorCode
+	l.callerSkip = 0
+	l.callerOff = false
+	l.hasLevel = false
+	l.pooling = false
+	l.flushLevel = DisabledFlushLevel
+	l.overflow = Block
+	l.enqueueTimeout = 0
+	l.helpers = nil
+	l.labels = nil
+	l.defaultFields = nil
+	l.defaultFormat = ""
+	l.address = ""
+	l.exitFunc = os.Exit
+	l.resetHandlers()
+
+	atomic.StoreUint32(&l.shutdown, 0)
+
+	return l
+}
+
+// resetHandlers sets logger default log handlers. Caller must hold the logger
+// lock.
+func (l *Logger) resetHandlers() {
+	defer InvalidateLevelCache()
+
+	if l.inherit {
+		l.handlers = nil
+	} else {
+		l.handlers = newDefaultHandlers()
+
+		for _, handler := range l.handlers {
+			l.applyDefaultFormat(handler)
+		}
+	}
+}
+
+// AddHook adds hook under provided identifier name. Hooks are called by
+// logger worker thread in the insertion order for every log record before
+// dispatching it to added log handlers. Adding hook with already used name
+// replaces previous hook and it keeps its position.
+func (l *Logger) AddHook(name string, hook Hook) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	hooks := make([]namedHook, 0, len(l.hooks)+1)
+	replaced := false
+
+	for _, entry := range l.hooks {
+		if entry.name == name {
+			entry.hook = hook
+			replaced = true
+		}
+
+		hooks = append(hooks, entry)
+	}
+
+	if !replaced {
+		hooks = append(hooks, namedHook{name: name, hook: hook})
+	}
+
+	l.hooks = hooks
+
+	return l
+}
+
+// RemoveHook removes added hook by provided name.
+func (l *Logger) RemoveHook(name string) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	hooks := make([]namedHook, 0, len(l.hooks))
+
+	for _, entry := range l.hooks {
+		if entry.name != name {
+			hooks = append(hooks, entry)
+		}
+	}
+
+	l.hooks = hooks
+
+	return l
+}
+
+// SetIDGenerator sets ID generator function that is called by logger to
+// generate ID for created log messages.
+func (l *Logger) SetIDGenerator(idGenerator IDGenerator) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.idGenerator = idGenerator
+
+	return l
+}
+
+// GetIDGenerator returns ID generator function that is called by logger to
+// generate ID for created log messages.
+func (l *Logger) GetIDGenerator() IDGenerator {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.idGenerator
+}
+
+// SetIDGeneration sets when logger generates IDs of log records. On default
+// it is IDGenerationAlways. With IDGenerationLazy IDs are generated only for
+// log records with ID requested by log handlers via the Record GetID method.
+func (l *Logger) SetIDGeneration(generation IDGeneration) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.idGeneration = generation
+
+	return l
+}
+
+// GetIDGeneration returns when logger generates IDs of log records.
+func (l *Logger) GetIDGeneration() IDGeneration {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.idGeneration
+}
+
+// Trace logs finer-grained informational messages than the Debug. It creates
+// and sends lightweight not formatted log messages to separate running logger
+// thread for further formatting and I/O handling from different added log
+// handlers.
+func (l *Logger) Trace(message string, arguments ...interface{}) {
+	l.logMessage(0, TraceLevel, TraceName, message, arguments)
+}
+
+// Debug logs debugging messages. It creates and sends lightweight not formatted
+// log messages to separate running logger thread for further formatting and
+// I/O handling from different added log handlers.
+func (l *Logger) Debug(message string, arguments ...interface{}) {
+	l.logMessage(0, DebugLevel, DebugName, message, arguments)
+}
+
+// Info logs informational messages. It creates and sends lightweight not
+// formatted log messages to separate running logger thread for further
+// formatting and I/O handling from different added log handlers.
+func (l *Logger) Info(message string, arguments ...interface{}) {
+	l.logMessage(0, InfoLevel, InfoName, message, arguments)
+}
+
+// Notice logs messages for significant conditions. It creates and sends
+// lightweight not formatted log messages to separate running logger thread for
+// further formatting and I/O handling from different added log handlers.
+func (l *Logger) Notice(message string, arguments ...interface{}) {
+	l.logMessage(0, NoticeLevel, NoticeName, message, arguments)
+}
+
+// Warning logs messages for warning conditions that can be potentially harmful.
+// It creates and sends lightweight not formatted log messages to separate
+// running logger thread for further formatting and I/O handling from different
+// added log handlers.
+func (l *Logger) Warning(message string, arguments ...interface{}) {
+	l.logMessage(0, WarningLevel, WarningName, message, arguments)
+}
+
+// Error logs messages for error conditions. It creates and sends lightweight
+// not formatted log messages to separate running logger thread for further
+// formatting and I/O handling from different log handlers.
+func (l *Logger) Error(message string, arguments ...interface{}) {
+	l.logMessage(0, ErrorLevel, ErrorName, message, arguments)
+}
+
+// Critical logs messages for critical conditions. It creates and sends
+// lightweight not formatted log messages to separate running logger thread for
+// further formatting and I/O handling from different added log handlers.
+func (l *Logger) Critical(message string, arguments ...interface{}) {
+	l.logMessage(0, CriticalLevel, CriticalName, message, arguments)
+}
+
+// Alert logs messages for alert conditions. It creates and sends lightweight
+// not formatted log messages to separate running logger thread for further
+// formatting and I/O handling from different added log handlers.
+func (l *Logger) Alert(message string, arguments ...interface{}) {
+	l.logMessage(0, AlertLevel, AlertName, message, arguments)
+}
+
+// Fatal logs messages for fatal conditions. It closes logger instance and it
+// exists the application with an error code using function set by the
+// SetExitFunc method. It creates and sends
+// lightweight not formatted log messages to separate running logger thread for
+// further formatting and I/O handling from different added log handlers.
+func (l *Logger) Fatal(message string, arguments ...interface{}) {
+	l.logMessage(0, FatalLevel, FatalName, message, arguments)
+	l.CloseDefer()
+	l.exit()
+}
+
+// Panic logs messages for fatal conditions. It closes logger instance and it
+// exists the application with a panic. Panic value is a PanicError object
+// with formatted log message. It creates and sends lightweight not
+// formatted log messages to separate running logger thread for further
+// formatting and I/O handling from different added log handlers.
+func (l *Logger) Panic(message string, arguments ...interface{}) {
+	record := l.newRecord(loggerSkipCall-1, PanicLevel, PanicName, message, arguments)
+	err := NewPanicError(record)
+
+	l.send(record)
+	l.CloseDefer()
+	panic(err)
+}
+
+// Log logs messages with user defined log level value and name. It creates and
+// sends lightweight not formatted log messages to separate running logger
+// thread for further formatting and I/O handling from different added log
+// handlers. Empty log level name is replaced with a name registered by
+// the RegisterLevel function.
+func (l *Logger) Log(level int, levelName, message string, arguments ...interface{}) {
+	l.logMessage(0, level, levelName, message, arguments)
+}
+
+// Reopen reopens all added log handlers that implement the Reopener interface,
+// like for example File or Syslog log handlers.
+func (l *Logger) Reopen() *Logger {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	for _, handler := range l.getHandlers() {
+		if reopener, ok := handler.(Reopener); ok {
+			reopener.Reopen()
+		}
+	}
+
+	return l
+}
+
+// ReopenOnSignal reopens all added log handlers when one of provided signals
+// arrives, like for example syscall.SIGHUP sent by logrotate. Next call
+// replaces previously provided signals. Calling it without signals or the
+// Close method stops reopening on signals.
+func (l *Logger) ReopenOnSignal(signals ...os.Signal) *Logger {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.stopReopenOnSignal()
+
+	if len(signals) == 0 {
+		return l
+	}
+
+	l.signals = make(chan os.Signal, 1)
+
+	signal.Notify(l.signals, signals...)
+
+	go func(received <-chan os.Signal) {
+		for range received {
+			l.Reopen()
+		}
+	}(l.signals)
+
+	return l
+}
+
+// stopReopenOnSignal stops reopening log handlers on signals. Caller must hold
+// the logger lock.
+func (l *Logger) stopReopenOnSignal() {
+	if l.signals != nil {
+		signal.Stop(l.signals)
+		close(l.signals)
+		l.signals = nil
+	}
+}
+
+// Flush flushes all log messages. It flushes also all added log handlers that
+// implement the Flusher interface, like for example File log handler with
+// a write buffer.
+func (l *Logger) Flush() *Logger {
+	if !l.IsSynchronous() {
+		l.GetWorker().Flush()
+	}
+
+	l.flushHandlers()
+
+	return l
+}
+
+// flushHandlers flushes all added log handlers that implement the Flusher
+// interface.
+func (l *Logger) flushHandlers() {
+	for _, handler := range l.GetHandlers() {
+		if flusher, ok := handler.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				l.printError(NewRuntimeError("cannot flush log handler", err))
+			}
+		}
+	}
+}
+
+// FlushTimeout flushes all log messages like the Flush method but it waits
+// at most provided timeout duration. On timeout it returns an error that
+// wraps context.DeadlineExceeded and queued log messages are left intact.
+func (l *Logger) FlushTimeout(timeout time.Duration) error {
+	if l.IsSynchronous() {
+		return nil
+	}
+
+	return l.GetWorker().FlushTimeout(timeout)
+}
+
+// FlushContext flushes all log messages like the Flush method but it waits
+// until provided context is done. When context is done before flushing all
+// log messages it returns an error that wraps the context error and queued
+// log messages are left intact.
+func (l *Logger) FlushContext(ctx context.Context) error {
+	if l.IsSynchronous() {
+		return nil
+	}
+
+	return l.GetWorker().FlushContext(ctx)
+}
+
+// CloseTimeout closes logger like the Close method but it waits at most
+// provided timeout duration for flushing all log messages. On timeout it
+// returns an error that wraps context.DeadlineExceeded and log handlers are
+// not closed.
+func (l *Logger) CloseTimeout(timeout time.Duration) error {
+	if err := l.FlushTimeout(timeout); err != nil {
+		return NewRuntimeError("cannot close logger", err)
+	}
+
+	return l.Close()
+}
+
+// Close closes all added log handlers. It stops also logger worker thread
+// set by the SetWorker method. Returned error aggregates errors from all log
+// handlers that cannot be closed and each of them can be inspected with
+// errors.Is or errors.As.
+func (l *Logger) Close() error {
+	l.Flush()
+
+	l.mutex.RLock()
+	worker := l.worker
+	l.mutex.RUnlock()
+
+	if worker != nil {
+		worker.Stop()
+	}
+
+	if errs := l.closeHandlers(); len(errs) != 0 {
+		return NewRuntimeError("cannot close log handlers", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// closeHandlers closes all added log handlers and it stops reopening them on
+// signals. It returns errors from log handlers that cannot be closed. Log
+// handlers are closed and errors are reported without holding the logger lock.
+func (l *Logger) closeHandlers() []error {
+	l.mutex.Lock()
+	l.stopReopenOnSignal()
+	handlers := l.handlers
+	l.mutex.Unlock()
+
+	var errs []error
+
+	for name, handler := range handlers {
+		if err := handler.Close(); err != nil {
+			err = NewRuntimeError("cannot close log handler {p | printf \"%q\"}", name, err)
+			l.printError(err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Shutdown shuts down logger. It flushes all log messages logged before it
+// until provided context is done, it stops logger worker thread set by the SetWorker method
+// and it closes all added log handlers. Log messages logged after it are
+// discarded until the Reset method is called. It is safe to call it multiple
+// times, only the first call shuts down logger and next calls return nil.
+// Returned error aggregates the flush error and errors from log handlers that
+// cannot be closed and each of them can be inspected with errors.Is or
+// errors.As. Log handlers are closed even if flushing failed. Stopping logger
+// worker thread and closing log handlers are bounded by provided context and
+// on cancellation they continue in background.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	if !atomic.CompareAndSwapUint32(&l.shutdown, 0, 1) {
+		return nil
+	}
+
+	var errs []error
+
+	if err := l.FlushContext(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	l.mutex.RLock()
+	worker := l.worker
+	l.mutex.RUnlock()
+
+	if worker != nil {
+		if err := doContext(ctx, func() { worker.Stop() }); err != nil {
+			errs = append(errs, NewRuntimeError("cannot stop logger worker thread", err))
+		}
+	}
+
+	var closeErrs []error
+
+	if err := doContext(ctx, func() { closeErrs = l.closeHandlers() }); err != nil {
+		errs = append(errs, NewRuntimeError("cannot close log handlers", err))
+	} else {
+		errs = append(errs, closeErrs...)
+	}
+
+	if len(errs) != 0 {
+		return NewRuntimeError("cannot shut down logger", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// IsShutdown returns true if logger was shut down by the Shutdown method.
+func (l *Logger) IsShutdown() bool {
+	return atomic.LoadUint32(&l.shutdown) != 0
+}
+
+// doContext calls provided function in background and it waits until it
+// returns or provided context is done. On cancellation provided function
+// continues in background and it returns the context error.
+func doContext(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+
+	go func() {
+		fn()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseDefer is a small helper function that invokes the .Close() method
+// and it does an error checking with logging. Useful when using with
+// the defer keyword to avoid creating an anonymous function wrapper only
+// to check for an error manually and passing the errcheck linter.
 func (l *Logger) CloseDefer() {
 	if err := l.Close(); err != nil {
-		printError(NewRuntimeError("cannot close logger", err))
+		l.printError(NewRuntimeError("cannot close logger", err))
+	}
+}
+
+// exit exits the application with an error code using function set by the
+// SetExitFunc method.
+func (l *Logger) exit() {
+	if exitFunc := l.GetExitFunc(); exitFunc != nil {
+		exitFunc(l.GetErrorCode())
+	}
+}
+
+// printError reports error using logger internal error handler. It fallbacks
+// to the global internal error handler.
+func (l *Logger) printError(err error) {
+	if !l.errorReporter.report(err) {
+		printError(err)
 	}
 }
 
 // LogMessage logs message with defined log level value and name. It creates and
 // sends lightweight not formatted log messages to separate running logger
 // thread for further formatting and I/O handling from different added log
-// handlers. Use this method in custom log wrapper methods.
+// handlers. Use this method in custom log wrapper methods. Reported source
+// location is the caller of the custom log wrapper method.
 func (l *Logger) LogMessage(level int, levelName, message string, arguments ...interface{}) {
+	l.logMessage(1, level, levelName, message, arguments)
+}
+
+// LogBase logs message with defined log level value and name like the
+// LogMessage method but it skips provided number of additional stack frames
+// to report the source location. Zero skip reports the LogBase caller. Use
+// this method in log wrappers that call it through several layers of
+// functions, every layer adds one to skip.
+func (l *Logger) LogBase(skip, level int, levelName, message string, arguments ...interface{}) {
+	l.logMessage(skip, level, levelName, message, arguments)
+}
+
+// Measure returns function that logs message with defined log level value and
+// elapsed time since calling the Measure method. Elapsed time is available
+// in log message as the {duration} named placeholder and it is stored in the
+// Record.Duration field. Returned function can be called multiple times, for
+// example with defer, and every call logs message. Reported source location
+// is the caller of the Measure method.
+func (l *Logger) Measure(level int, message string, arguments ...interface{}) func() {
+	return l.measure(0, level, message, arguments)
+}
+
+// measure returns function that logs message with elapsed time. Skip call
+// value defines number of additional stack frames to skip above the caller of
+// the Measure method.
+func (l *Logger) measure(skipCall, level int, message string, arguments []interface{}) func() {
+	start := time.Now()
+
+	var source Source
+
+	captured := l.isCallerCaptured(level)
+
+	if captured {
+		source = l.getSource(loggerSkipCall + skipCall)
+	}
+
+	return func() {
+		if !l.isLevelEnabled(level) {
+			return
+		}
+
+		duration := time.Since(start)
+
+		measured := make([]interface{}, 0, len(arguments)+1)
+		measured = append(measured, arguments...)
+		measured = append(measured, Named{"duration": duration})
+
+		record := l.createRecord(level, "", message, measured)
+		record.Duration = duration
+
+		if captured {
+			record.File = source
+		}
+
+		l.logRecord(record)
+	}
+}
+
+// logMessage logs message with defined log level value and name. Skip call
+// value defines number of additional stack frames to skip above the caller of
+// the logMessage caller.
+func (l *Logger) logMessage(skipCall, level int, levelName, message string, arguments []interface{}) {
+	if l.isLevelEnabled(level) {
+		l.logRecord(l.newRecord(loggerSkipCall+skipCall, level, levelName, message, arguments))
+	}
+}
+
+// logContext logs message with defined log level value and name like the
+// logMessage method and it sets provided context to log record.
+func (l *Logger) logContext(ctx context.Context, skipCall, level int, levelName, message string, arguments []interface{}) {
+	if l.isLevelEnabled(level) {
+		record := l.newRecord(loggerSkipCall+skipCall, level, levelName, message, arguments)
+		record.Context = ctx
+
+		l.logRecord(record)
+	}
+}
+
+// logRecord sends log record to logger worker thread and it flushes all log
+// messages if log record log level is greater than or equal to flush level.
+// Sent log record may be already released and it must not be accessed.
+func (l *Logger) logRecord(record *Record) {
+	level := record.Level.Value
+
+	l.send(record)
+
+	if level >= l.GetFlushLevel() {
+		l.Flush()
+	}
+}
+
+// newRecord creates a new log record with the current time and source
+// location. Skip call value is counted from the newRecord caller.
+func (l *Logger) newRecord(skipCall, level int, levelName, message string, arguments []interface{}) *Record {
+	record := l.createRecord(level, levelName, message, arguments)
+
+	if l.isCallerCaptured(level) {
+		record.File = l.getSource(skipCall + 1)
+	}
+
+	return record
+}
+
+// isCallerCaptured returns true if source location is captured for log
+// message with provided log level.
+func (l *Logger) isCallerCaptured(level int) bool {
+	return (level >= PanicLevel) || l.IsCallerCaptured()
+}
+
+// createRecord creates a new log record with the current time and without
+// source location.
+func (l *Logger) createRecord(level int, levelName, message string, arguments []interface{}) *Record {
 	now := time.Now()
 
-	pc, path, line, _ := runtime.Caller(loggerSkipCall)
+	if levelName == "" {
+		levelName = GetLevelName(level)
+	}
 
-	GetWorker().records <- &Record{
-		Time:      now,
-		Message:   message,
-		Arguments: arguments,
-		Level: Level{
-			Name:  levelName,
-			Value: level,
-		},
-		File: Source{
+	var record *Record
+
+	if (level < PanicLevel) && l.IsRecordPooling() {
+		record = newPooledRecord()
+	} else {
+		record = new(Record)
+	}
+
+	record.Time = now
+	record.Message = message
+	record.Arguments = arguments
+	record.Level.Name = levelName
+	record.Level.Value = level
+	record.logger = l
+	record.internal = isReportingError()
+
+	return record
+}
+
+// isLevelEnabled returns true if log record with provided log level passes
+// logger level and at least one of log handlers would emit it. Without set
+// logger level only log handlers level ranges are checked.
+func (l *Logger) isLevelEnabled(level int) bool {
+	return l.getLevelCache().isEnabled(level)
+}
+
+// getSource returns source location of the caller. Skip call value is counted
+// from the getSource caller. It skips functions marked by the Helper method.
+func (l *Logger) getSource(skipCall int) Source {
+	l.mutex.RLock()
+	skipCall += l.callerSkip
+	helpers := l.helpers
+	l.mutex.RUnlock()
+
+	if len(helpers) == 0 {
+		pc, path, line, _ := runtime.Caller(skipCall + 1)
+
+		return Source{
 			Line:     line,
 			Path:     path,
 			Function: runtime.FuncForPC(pc).Name(),
-		},
-		logger: l,
+		}
+	}
+
+	var pcs [maximumCallerDepth]uintptr
+
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skipCall+2, pcs[:])])
+
+	for {
+		frame, more := frames.Next()
+
+		if _, ok := helpers[frame.Function]; !ok || !more {
+			return Source{
+				Line:     frame.Line,
+				Path:     frame.File,
+				Function: frame.Function,
+			}
+		}
 	}
 }
 
@@ -553,7 +1761,145 @@ func (l *Logger) LogMessage(level int, levelName, message string, arguments ...i
 // formatting and I/O handling from different addded log handlers.
 func (l *Logger) Emit(record *Record) *Logger {
 	record.logger = l
-	GetWorker().records <- record
+	l.send(record)
 
 	return l
 }
+
+// TryEmit emits provided log record to logger worker thread like the Emit
+// method but it never blocks. It returns false without emitting log record
+// when the logger worker thread queue is full. It bypasses the Block overflow
+// policy and log records that cannot be queued are not counted as dropped. In
+// synchronous mode log record is emitted directly and it always returns true.
+func (l *Logger) TryEmit(record *Record) bool {
+	record.logger = l
+
+	return l.enqueue(record, false, 0)
+}
+
+// send sends log record to logger worker thread. In synchronous mode log
+// record is emitted directly by the calling goroutine. Stopped logger worker
+// thread is restarted.
+func (l *Logger) send(record *Record) {
+	l.enqueue(record, true, 0)
+}
+
+// enqueue sends log record to logger worker thread. In synchronous mode log
+// record is emitted directly by the calling goroutine. Stopped logger worker
+// thread is restarted, if it was stopped by the StopWorker method log record
+// is emitted directly by the calling goroutine. Log record is discarded after
+// the Shutdown method. Without blocking it returns
+// false when log record cannot be queued. Provided fallback timeout is used
+// with the Block overflow policy when enqueue timeout is not set.
+func (l *Logger) enqueue(record *Record, block bool, fallback time.Duration) bool {
+	if l.IsShutdown() {
+		record.release()
+		return true
+	}
+
+	worker := l.GetWorker()
+
+	if l.IsSynchronous() {
+		worker.process(record, nil)
+		return true
+	}
+
+	stopped, running := worker.acquire()
+
+	if !running {
+		worker.process(record, nil)
+		return true
+	}
+
+	policy := l.GetOverflowPolicy()
+
+	timeout := l.GetEnqueueTimeout()
+
+	if timeout == 0 {
+		timeout = fallback
+	}
+
+	if block && (timeout > 0) {
+		if sendTimeout(worker, record, timeout) {
+			return true
+		}
+
+		if policy == Block {
+			l.drop(worker, record)
+			return false
+		}
+	} else if block && (policy == Block) {
+		if !worker.send(record, stopped) {
+			worker.process(record, nil)
+		}
+
+		return true
+	}
+
+	for {
+		if worker.send(record, nil) {
+			return true
+		}
+
+		if !block {
+			return false
+		}
+
+		if policy == DropNewest {
+			l.drop(worker, record)
+			return false
+		}
+
+		if oldest := worker.getQueue().receive(); oldest != nil {
+			oldest.logger.drop(worker, oldest)
+		}
+	}
+}
+
+// sendTimeout sends provided log record to logger worker thread queue. It
+// returns false if log record cannot be queued within provided timeout.
+func sendTimeout(worker *Worker, record *Record, timeout time.Duration) bool {
+	if worker.send(record, nil) {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return worker.send(record, ctx.Done())
+}
+
+// drop counts dropped log record and it releases it.
+func (l *Logger) drop(worker *Worker, record *Record) {
+	atomic.AddUint64(&worker.stats.dropped, 1)
+	atomic.AddUint64(&l.dropped.total, 1)
+	atomic.AddUint64(&l.dropped.pending, 1)
+	record.release()
+}
+
+// reportDropped emits a warning log record with number of dropped log
+// records. It is reported at most once per DroppedReportInterval.
+func (l *Logger) reportDropped(worker *Worker, b *batch) {
+	if atomic.LoadUint64(&l.dropped.pending) == 0 {
+		return
+	}
+
+	now := time.Now()
+
+	if now.Sub(time.Unix(0, atomic.LoadInt64(&l.dropped.reported))) < DroppedReportInterval {
+		return
+	}
+
+	atomic.StoreInt64(&l.dropped.reported, now.UnixNano())
+
+	worker.emit(l, &Record{
+		Time:      now,
+		Message:   DroppedMessage,
+		Arguments: Arguments{atomic.SwapUint64(&l.dropped.pending, 0)},
+		Level: Level{
+			Value: WarningLevel,
+			Name:  WarningName,
+		},
+		logger: l,
+	}, b)
+}
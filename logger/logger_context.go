@@ -0,0 +1,73 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+)
+
+// TraceContext logs finer-grained informational messages like the Trace
+// method and it sets provided context to log record.
+func (l *Logger) TraceContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, TraceLevel, TraceName, message, arguments)
+}
+
+// DebugContext logs debugging messages like the Debug method and it sets
+// provided context to log record.
+func (l *Logger) DebugContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, DebugLevel, DebugName, message, arguments)
+}
+
+// InfoContext logs informational messages like the Info method and it sets
+// provided context to log record.
+func (l *Logger) InfoContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, InfoLevel, InfoName, message, arguments)
+}
+
+// NoticeContext logs messages for significant conditions like the Notice
+// method and it sets provided context to log record.
+func (l *Logger) NoticeContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, NoticeLevel, NoticeName, message, arguments)
+}
+
+// WarningContext logs messages for warning conditions like the Warning method
+// and it sets provided context to log record.
+func (l *Logger) WarningContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, WarningLevel, WarningName, message, arguments)
+}
+
+// ErrorContext logs messages for error conditions like the Error method and
+// it sets provided context to log record.
+func (l *Logger) ErrorContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, ErrorLevel, ErrorName, message, arguments)
+}
+
+// CriticalContext logs messages for critical conditions like the Critical
+// method and it sets provided context to log record.
+func (l *Logger) CriticalContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, CriticalLevel, CriticalName, message, arguments)
+}
+
+// AlertContext logs messages for alert conditions like the Alert method and
+// it sets provided context to log record.
+func (l *Logger) AlertContext(ctx context.Context, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, AlertLevel, AlertName, message, arguments)
+}
+
+// LogContext logs messages with user defined log level value and name like
+// the Log method and it sets provided context to log record.
+func (l *Logger) LogContext(ctx context.Context, level int, levelName, message string, arguments ...interface{}) {
+	l.logContext(ctx, 0, level, levelName, message, arguments)
+}
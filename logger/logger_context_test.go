@@ -0,0 +1,72 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+type contextKey struct{}
+
+func TestLoggerLogContext(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandlers(logger.Handlers{"observer": observer})
+
+	defer log.Close()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "tenant"))
+
+	log.InfoContext(ctx, testMessage)
+	log.LogContext(ctx, logger.ErrorLevel, "", testMessage)
+	log.Info(testMessage)
+	cancel()
+	log.Flush()
+
+	logs := observer.All()
+
+	if logs.Len() != 3 {
+		test.Fatalf("Len() = %d; want 3", logs.Len())
+	}
+
+	for _, entry := range logs[:2] {
+		if entry.Context != ctx {
+			test.Fatal("Context is not propagated to log handler")
+		}
+
+		if value := entry.Context.Value(contextKey{}); value != "tenant" {
+			test.Errorf("Context.Value() = %v; want %q", value, "tenant")
+		}
+	}
+
+	if logs[2].Context != nil {
+		test.Error("Context is set without context-aware log method")
+	}
+}
+
+func TestLoggerLogContextCaller(test *testing.T) {
+	want := "logger_context_test.go:67:logger_test.TestLoggerLogContextCaller()\n"
+
+	log, buffer := newCallerLogger()
+
+	log.InfoContext(context.Background(), testMessage)
+
+	if buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+// Option defines functional option that configures logger created by the
+// NewWithOptions function.
+type Option func(l *Logger)
+
+// NewWithOptions creates a new logger instance like the New function and it
+// configures it with provided options applied from left to right.
+//
+//	log := logger.NewWithOptions(
+//	    logger.WithName("app"),
+//	    logger.WithoutDefaultHandlers(),
+//	    logger.WithHandlers(logger.Handlers{"file": logger.NewFile()}),
+//	    logger.WithLevel(logger.InfoLevel),
+//	)
+func NewWithOptions(options ...Option) *Logger {
+	l := New()
+
+	for _, option := range options {
+		option(l)
+	}
+
+	return l
+}
+
+// WithHandlers returns option that adds provided log handlers. Added log
+// handlers replace log handlers with the same identifier names.
+func WithHandlers(handlers Handlers) Option {
+	return func(l *Logger) {
+		for name, handler := range handlers {
+			l.AddHandler(name, handler)
+		}
+	}
+}
+
+// WithoutDefaultHandlers returns option that removes all log handlers,
+// including default Stdout and Stderr log handlers added by the New function.
+func WithoutDefaultHandlers() Option {
+	return func(l *Logger) {
+		l.RemoveHandlers()
+	}
+}
+
+// WithName returns option that sets logger name.
+func WithName(name string) Option {
+	return func(l *Logger) {
+		l.SetName(name)
+	}
+}
+
+// WithLevel returns option that sets logger level like the SetLoggerLevel
+// method. Log messages with lower log level are dropped regardless of log
+// handlers added before or after it.
+func WithLevel(level int) Option {
+	return func(l *Logger) {
+		l.SetLoggerLevel(level)
+	}
+}
+
+// WithIDGenerator returns option that sets ID generator.
+func WithIDGenerator(idGenerator IDGenerator) Option {
+	return func(l *Logger) {
+		l.SetIDGenerator(idGenerator)
+	}
+}
+
+// WithIDGeneration returns option that sets when logger generates IDs of log
+// records.
+func WithIDGeneration(generation IDGeneration) Option {
+	return func(l *Logger) {
+		l.SetIDGeneration(generation)
+	}
+}
+
+// WithFormat returns option that sets format string to all added log handlers
+// and to log handlers added after it like the SetDefaultFormat method.
+func WithFormat(format string) Option {
+	return func(l *Logger) {
+		l.SetDefaultFormat(format).SetFormat(format)
+	}
+}
+
+// WithAddress returns option that sets IP address set in log records.
+func WithAddress(address string) Option {
+	return func(l *Logger) {
+		l.SetAddress(address)
+	}
+}
+
+// WithErrorCode returns option that sets error code used by the Fatal methods.
+func WithErrorCode(errorCode int) Option {
+	return func(l *Logger) {
+		l.SetErrorCode(errorCode)
+	}
+}
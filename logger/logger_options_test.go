@@ -0,0 +1,72 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestNewWithOptions(test *testing.T) {
+	buffer := logger.NewBuffer()
+	generator := logger.NewUUID4()
+
+	log := logger.NewWithOptions(
+		logger.WithFormat("{name}:{level}:{message}"),
+		logger.WithoutDefaultHandlers(),
+		logger.WithHandlers(logger.Handlers{"buffer": buffer}),
+		logger.WithName("app"),
+		logger.WithLevel(logger.InfoLevel),
+		logger.WithErrorCode(3),
+		logger.WithIDGenerator(generator),
+		logger.WithName("service"),
+	).SetSynchronous(true)
+
+	if handlers := log.GetHandlers(); (len(handlers) != 1) || (handlers["buffer"] != buffer) {
+		test.Errorf("GetHandlers() = %v; want only buffer", handlers)
+	}
+
+	if got := log.GetErrorCode(); got != 3 {
+		test.Errorf("GetErrorCode() = %d; want 3", got)
+	}
+
+	if log.GetIDGenerator() != generator {
+		test.Error("GetIDGenerator() returns unexpected ID generator")
+	}
+
+	log.Debug("dropped")
+	log.Info("logged")
+
+	if want := "service:info:logged\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestNewWithOptionsDefaults(test *testing.T) {
+	handlers := logger.NewWithOptions().GetHandlers()
+
+	for _, name := range []string{"stdout", "stderr"} {
+		if _, ok := handlers[name]; !ok {
+			test.Errorf("GetHandlers() = %v; want %q", handlers, name)
+		}
+	}
+
+	handlers = logger.NewWithOptions(logger.WithHandlers(logger.Handlers{"buffer": logger.NewBuffer()})).GetHandlers()
+
+	if len(handlers) != 3 {
+		test.Errorf("len(GetHandlers()) = %d; want 3", len(handlers))
+	}
+}
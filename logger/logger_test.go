@@ -135,3 +135,467 @@ func TestGetErrorCode(test *testing.T) {
 		test.Errorf("logger.GetErrorCode() = %d; want %d", errorCode, logger.DefaultErrorCode)
 	}
 }
+
+func TestClone(test *testing.T) {
+	log := logger.New().SetName("original").SetErrorCode(3)
+
+	clone := log.Clone().SetName("clone").RemoveHandler("stdout")
+
+	if log.GetName() != "original" {
+		test.Error("log.GetName() =", log.GetName(), "; want original")
+	}
+
+	if clone.GetErrorCode() != 3 {
+		test.Errorf("clone.GetErrorCode() = %d; want 3", clone.GetErrorCode())
+	}
+
+	if len(log.GetHandlers()) != 2 {
+		test.Errorf("len(log.GetHandlers()) = %d; want 2", len(log.GetHandlers()))
+	}
+
+	if len(clone.GetHandlers()) != 1 {
+		test.Errorf("len(clone.GetHandlers()) = %d; want 1", len(clone.GetHandlers()))
+	}
+
+	if log.GetHandlers()["stderr"] != clone.GetHandlers()["stderr"] {
+		test.Error("clone doesn't share log handlers")
+	}
+}
+
+func TestCloneConcurrent(test *testing.T) {
+	log := logger.New().SetHandler("buffer", logger.NewBuffer().Disable())
+	clone := log.Clone()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for index := 0; index < 100; index++ {
+			clone.AddHandler("other", logger.NewBuffer().Disable()).SetName("clone").RemoveHandler("other")
+		}
+	}()
+
+	for index := 0; index < 100; index++ {
+		for name := range log.GetHandlers() {
+			if name != "buffer" {
+				test.Error("log.GetHandlers() contains an unexpected handler", name)
+			}
+		}
+
+		log.Info("original")
+	}
+
+	<-done
+
+	log.Flush()
+
+	if log.GetName() != "" {
+		test.Error("log.GetName() =", log.GetName(), "; want empty")
+	}
+}
+
+func TestDisableDefaultHandlers(test *testing.T) {
+	log := logger.New().Disable().SetLevel(logger.InfoLevel)
+
+	if log.IsEnabled() {
+		test.Error("log.IsEnabled() = true; want false")
+	}
+
+	for name, handler := range log.GetHandlers() {
+		if handler.IsEnabled() {
+			test.Errorf("handlers[%q].IsEnabled() = true; want false", name)
+		}
+	}
+
+	if !log.Enable().SetLevelRange(logger.MinimumLevel, logger.MaximumLevel).IsEnabled() {
+		test.Error("log.IsEnabled() = false; want true")
+	}
+}
+
+func TestSetSynchronous(test *testing.T) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer).SetSynchronous(true)
+
+	if !log.IsSynchronous() {
+		test.Error("log.IsSynchronous() = false; want true")
+	}
+
+	log.Info(testMessage)
+
+	if buffer.String() != testMessage+"\n" {
+		test.Error("buffer.String() =", buffer.String(), "; want", testMessage)
+	}
+}
+
+func TestSetLoggerLevel(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetLoggerLevel(logger.WarningLevel)
+
+	log.Info("dropped")
+	log.Warning("emitted")
+	log.Flush()
+
+	if observer.Len() != 1 {
+		test.Errorf("observer.Len() = %d; want 1", observer.Len())
+	}
+
+	if log.GetLoggerLevel() != logger.WarningLevel {
+		test.Errorf("GetLoggerLevel() = %d; want %d", log.GetLoggerLevel(), logger.WarningLevel)
+	}
+
+	log.ResetLoggerLevel().Info("emitted")
+	log.Flush()
+
+	if observer.Len() != 2 {
+		test.Errorf("observer.Len() = %d; want 2", observer.Len())
+	}
+}
+
+func TestLoggerLevelAggregate(test *testing.T) {
+	created := 0
+
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetSynchronous(true).AddHook("count", func(*logger.Record) error {
+		created++
+		return nil
+	})
+
+	observer.SetLevel(logger.ErrorLevel)
+	log.Debug("dropped")
+
+	if created != 0 {
+		test.Errorf("created = %d; want 0", created)
+	}
+
+	observer.SetLevelRange(logger.MinimumLevel, logger.MaximumLevel)
+	log.Debug("emitted")
+
+	if created != 1 {
+		test.Errorf("created = %d; want 1", created)
+	}
+}
+
+func TestLoggerLevelInherited(test *testing.T) {
+	observer := logger.NewObserver()
+
+	parent := logger.GetLogger("level.parent").SetLoggerLevel(logger.ErrorLevel)
+	child := logger.GetLogger("level.parent.child").SetHandler("observer", observer).ResetLoggerLevel()
+
+	child.Info("dropped")
+	child.Flush()
+
+	if observer.Len() != 0 {
+		test.Errorf("observer.Len() = %d; want 0", observer.Len())
+	}
+
+	child.SetLoggerLevel(logger.InfoLevel).Info("emitted")
+	child.Flush()
+
+	if observer.Len() != 1 {
+		test.Errorf("observer.Len() = %d; want 1", observer.Len())
+	}
+
+	if parent.GetLoggerLevel() != logger.ErrorLevel {
+		test.Errorf("parent.GetLoggerLevel() = %d; want %d", parent.GetLoggerLevel(), logger.ErrorLevel)
+	}
+}
+
+func TestSetRecordPooling(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetRecordPooling(true)
+
+	for index := 0; index < 100; index++ {
+		log.Info("Message {p}", index)
+	}
+
+	log.Flush()
+
+	for index, entry := range observer.All() {
+		if want := "Message " + big.NewInt(int64(index)).String(); entry.Message != want {
+			test.Errorf("entry.Message = %q; want %q", entry.Message, want)
+		}
+	}
+
+	if !log.IsRecordPooling() {
+		test.Error("IsRecordPooling() = false; want true")
+	}
+}
+
+func BenchmarkLoggerRecordPooling(benchmark *testing.B) {
+	for _, pooling := range []bool{false, true} {
+		pooling := pooling
+
+		name := "disabled"
+
+		if pooling {
+			name = "enabled"
+		}
+
+		benchmark.Run(name, func(benchmark *testing.B) {
+			log := logger.New().SetHandler("zero", new(zeroHandler)).SetRecordPooling(pooling).SetCaptureCaller(false)
+
+			benchmark.ReportAllocs()
+			benchmark.ResetTimer()
+
+			for index := 0; index < benchmark.N; index++ {
+				log.Info("Message")
+			}
+
+			log.Flush()
+		})
+	}
+}
+
+func TestTryEmit(test *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	log := logger.New().SetHandler("observer", logger.NewObserver()).SetWorker(logger.NewWorker())
+
+	log.AddHook("block", func(*logger.Record) error {
+		select {
+		case started <- struct{}{}:
+			<-release
+		default:
+		}
+
+		return nil
+	})
+
+	if !log.TryEmit(&logger.Record{}) {
+		test.Fatal("TryEmit() = false; want true")
+	}
+
+	<-started
+
+	for index := 0; index < logger.DefaultQueueLength; index++ {
+		if !log.TryEmit(&logger.Record{}) {
+			test.Fatal("TryEmit() = false; want true for record", index)
+		}
+	}
+
+	if log.TryEmit(&logger.Record{}) {
+		test.Error("TryEmit() = true; want false")
+	}
+
+	close(release)
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestSetFlushLevel(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(logger.NewWorker()).SetFlushLevel(logger.ErrorLevel)
+
+	defer log.CloseDefer()
+
+	log.Info(testMessage)
+	log.Error(testMessage)
+
+	if observer.Len() != 2 {
+		test.Errorf("observer.Len() = %d; want 2", observer.Len())
+	}
+
+	if log.GetFlushLevel() != logger.ErrorLevel {
+		test.Errorf("GetFlushLevel() = %d; want %d", log.GetFlushLevel(), logger.ErrorLevel)
+	}
+}
+
+func TestRecordRetain(test *testing.T) {
+	var retained, cloned []*logger.Record
+
+	log := logger.New().SetHandler("observer", logger.NewObserver()).SetRecordPooling(true)
+
+	log.AddHook("retain", func(record *logger.Record) error {
+		retained = append(retained, record.Retain())
+		cloned = append(cloned, record.Clone())
+
+		return nil
+	})
+
+	for index := 0; index < 100; index++ {
+		log.Info("Message {p}", index)
+	}
+
+	log.Flush()
+
+	for index := range retained {
+		for _, record := range []*logger.Record{retained[index], cloned[index]} {
+			if (record.Message != "Message {p}") || (len(record.Arguments) != 1) || (record.Arguments[0] != index) {
+				test.Errorf("record = %+v; want message with argument %d", record, index)
+			}
+		}
+	}
+
+	if len(retained) != 100 {
+		test.Errorf("len(retained) = %d; want 100", len(retained))
+	}
+}
+
+func TestRecordClone(test *testing.T) {
+	record := &logger.Record{
+		Message:   testMessage,
+		Arguments: logger.Arguments{1, 2},
+	}
+
+	clone := record.Clone()
+	clone.Arguments[0] = 3
+
+	if record.Arguments[0] != 1 {
+		test.Error("Clone() doesn't copy log arguments")
+	}
+
+	if clone.Message != testMessage {
+		test.Errorf("clone.Message = %q; want %q", clone.Message, testMessage)
+	}
+}
+
+func TestLoggerSetDefaultFields(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandlers(logger.Handlers{"buffer": buffer})
+
+	defer log.Close()
+
+	log.SetDefaultFields(logger.Named{"service": "api", "version": "1.0"})
+	log.Info("Started {service}", logger.Named{"service": "db"})
+	log.Info("Stopped")
+	log.Flush()
+
+	if want := "version=1.0 Started db\nservice=api version=1.0 Stopped\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	if got := log.GetDefaultFields()["service"]; got != "api" {
+		test.Errorf("GetDefaultFields()[\"service\"] = %v; want %q", got, "api")
+	}
+}
+
+func TestLoggerMeasure(test *testing.T) {
+	var records []*logger.Record
+
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{message}|{duration}")
+
+	log := logger.New().SetHandlers(logger.Handlers{"buffer": buffer}).SetSynchronous(true)
+
+	defer log.Close()
+
+	log.AddHook("measure", func(record *logger.Record) error {
+		records = append(records, record)
+		return nil
+	})
+
+	log.Measure(logger.InfoLevel, "Took {duration} for {p}", 1)()
+
+	if len(records) != 1 {
+		test.Fatalf("len(records) = %d; want 1", len(records))
+	}
+
+	duration := records[0].Duration.String()
+
+	if want := "Took " + duration + " for 1|" + duration + "\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestLoggerGetLevelRange(test *testing.T) {
+	info := logger.NewBuffer().SetLevelRange(logger.InfoLevel, logger.ErrorLevel)
+	debug := logger.NewBuffer().SetLevelRange(logger.DebugLevel, logger.WarningLevel)
+	trace := logger.NewBuffer().SetLevelRange(logger.TraceLevel, logger.PanicLevel).Disable()
+
+	log := logger.New().SetHandlers(logger.Handlers{
+		"info":  info,
+		"debug": debug,
+		"trace": trace,
+	})
+
+	defer log.Close()
+
+	if min, max := log.GetLevelRange(); (min != logger.DebugLevel) || (max != logger.ErrorLevel) {
+		test.Errorf("GetLevelRange() = %d, %d; want %d, %d", min, max, logger.DebugLevel, logger.ErrorLevel)
+	}
+
+	log.RemoveHandlers()
+
+	if min, max := log.GetLevelRange(); max >= min {
+		test.Errorf("GetLevelRange() = %d, %d; want inverted range", min, max)
+	}
+}
+
+func TestLoggerSetDefaultFormat(test *testing.T) {
+	added := logger.NewBuffer()
+	existing := logger.NewBuffer()
+
+	log := logger.New().SetHandlers(logger.Handlers{"existing": existing}).SetSynchronous(true)
+
+	defer log.Close()
+
+	log.SetDefaultFormat("{level}:{message}")
+
+	if log.GetDefaultFormat() != "{level}:{message}" {
+		test.Errorf("GetDefaultFormat() = %q; want %q", log.GetDefaultFormat(), "{level}:{message}")
+	}
+
+	log.AddHandler("added", added)
+	log.Info(testMessage)
+
+	if want := "info:" + testMessage + "\n"; added.String() != want {
+		test.Errorf("added = %q; want %q", added.String(), want)
+	}
+
+	if existing.String() == added.String() {
+		test.Error("SetDefaultFormat() changes format of already added log handlers")
+	}
+}
+
+type levelHandler struct {
+	*logger.Observer
+	level *int
+}
+
+func (l levelHandler) GetLevelRange() (min, max int) {
+	return *l.level, logger.MaximumLevel
+}
+
+func TestLoggerLevelCache(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetSynchronous(true)
+
+	observer.Disable()
+	log.Error("dropped")
+
+	observer.Enable()
+	log.Error("emitted")
+
+	if length := observer.Len(); length != 1 {
+		test.Errorf("Len() = %d; want 1", length)
+	}
+
+	level := logger.ErrorLevel
+	custom := levelHandler{Observer: logger.NewObserver(), level: &level}
+
+	log.SetHandlers(logger.Handlers{"custom": custom})
+	log.Info("dropped")
+
+	level = logger.MinimumLevel
+	logger.InvalidateLevelCache()
+	log.Info("emitted")
+
+	if length := custom.Len(); length != 1 {
+		test.Errorf("Len() = %d; want 1 after InvalidateLevelCache()", length)
+	}
+}
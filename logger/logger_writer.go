@@ -0,0 +1,65 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// DefaultWriterTimeout defines maximum duration for which writing to writer
+// returned by the Logger.Writer method blocks with the Block overflow policy
+// when enqueue timeout is not set.
+const DefaultWriterTimeout = time.Second
+
+// loggerWriter represents a writer that logs every written line as a log
+// record via logger.
+type loggerWriter struct {
+	logger    *Logger
+	level     int
+	levelName string
+}
+
+// Writer returns writer that logs every written line as a separate log record
+// with provided log level value and name. It allows other libraries to write
+// into logger, like for example the ErrorLog of the net/http.Server. Every
+// write is split on line endings and the trailing line ending is stripped.
+// Source location is not captured. Returned writer is safe for concurrent
+// use. When logger worker thread queue is full, log records are dropped
+// according to the overflow policy and the Block overflow policy blocks at
+// most the enqueue timeout or DefaultWriterTimeout if it is not set.
+func (l *Logger) Writer(level int, levelName string) io.Writer {
+	return &loggerWriter{
+		logger:    l,
+		level:     level,
+		levelName: levelName,
+	}
+}
+
+// Write logs every line from provided data as a log record.
+func (w *loggerWriter) Write(data []byte) (int, error) {
+	if (len(data) == 0) || !w.logger.isLevelEnabled(w.level) {
+		return len(data), nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		record := w.logger.createRecord(w.level, w.levelName, strings.TrimSuffix(line, "\r"), nil)
+
+		w.logger.enqueue(record, true, DefaultWriterTimeout)
+	}
+
+	return len(data), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestLoggerWriter(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{level}:{file}:{message}")
+
+	log := logger.New().SetHandlers(logger.Handlers{"buffer": buffer}).SetSynchronous(true)
+
+	defer log.Close()
+
+	writer := log.Writer(logger.WarningLevel, "")
+
+	var wg sync.WaitGroup
+
+	for index := 0; index < 8; index++ {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+
+			if _, err := fmt.Fprintf(writer, "first %d\r\nsecond %d\n", index, index); err != nil {
+				test.Error("Write() returns an unexpected error", err)
+			}
+		}(index)
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+
+	sort.Strings(lines)
+
+	want := make([]string, 0, 16)
+
+	for index := 0; index < 8; index++ {
+		want = append(want, fmt.Sprintf("warning::first %d", index), fmt.Sprintf("warning::second %d", index))
+	}
+
+	sort.Strings(want)
+
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		test.Errorf("buffer = %q; want %q", lines, want)
+	}
+}
+
+func TestLoggerWriterQueueFull(test *testing.T) {
+	log, release := newBlockedLogger(logger.New())
+
+	defer log.Close()
+	defer release()
+
+	for index := 0; index < logger.DefaultQueueLength; index++ {
+		log.Info(testMessage)
+	}
+
+	start := time.Now()
+
+	if _, err := log.Writer(logger.InfoLevel, logger.InfoName).Write([]byte("dropped\n")); err != nil {
+		test.Error("Write() returns an unexpected error", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < logger.DefaultWriterTimeout {
+		test.Errorf("Write() returns after %v; want at least %v", elapsed, logger.DefaultWriterTimeout)
+	}
+
+	if got := log.GetDroppedCount(); got != 1 {
+		test.Errorf("GetDroppedCount() = %d; want 1", got)
+	}
+}
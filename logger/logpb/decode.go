@@ -0,0 +1,355 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logpb
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrUnexpectedEnd is returned when encoded data ends unexpectedly.
+	ErrUnexpectedEnd = errors.New("unexpected end of protocol buffers data") // nolint:gochecknoglobals
+
+	// ErrInvalidWireType is returned when field is encoded with invalid or
+	// unsupported wire type.
+	ErrInvalidWireType = errors.New("invalid protocol buffers wire type") // nolint:gochecknoglobals
+
+	// ErrVarintOverflow is returned when encoded varint overflows 64 bits.
+	ErrVarintOverflow = errors.New("protocol buffers varint overflows 64 bits") // nolint:gochecknoglobals
+)
+
+// A decoder represents protocol buffers decoder state.
+type decoder struct {
+	data []byte
+}
+
+// Unmarshal decodes the protocol buffers encoding of log record message.
+// Unknown fields are skipped.
+func (r *Record) Unmarshal(data []byte) error {
+	*r = Record{}
+
+	return decode(data, func(d *decoder, field, wireType int) (err error) {
+		switch field {
+		case 1:
+			r.ID, err = d.string(wireType)
+		case 2:
+			r.Type, err = d.string(wireType)
+		case 3:
+			r.Name, err = d.string(wireType)
+		case 4:
+			r.TimeUnixNano, err = d.int64(wireType)
+		case 5:
+			err = d.message(wireType, r.Level.decode)
+		case 6:
+			r.Address, err = d.string(wireType)
+		case 7:
+			r.Hostname, err = d.string(wireType)
+		case 8:
+			r.Message, err = d.string(wireType)
+		case 9:
+			err = d.message(wireType, r.File.decode)
+		case 10:
+			err = d.message(wireType, r.decodeLabel)
+		case 11:
+			err = d.message(wireType, r.decodeField)
+		default:
+			err = d.skip(wireType)
+		}
+
+		return err
+	})
+}
+
+// decodeLabel decodes labels map entry.
+func (r *Record) decodeLabel(data []byte) error {
+	var key, value string
+
+	if err := decode(data, func(d *decoder, field, wireType int) (err error) {
+		switch field {
+		case 1:
+			key, err = d.string(wireType)
+		case 2:
+			value, err = d.string(wireType)
+		default:
+			err = d.skip(wireType)
+		}
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if r.Labels == nil {
+		r.Labels = make(map[string]string)
+	}
+
+	r.Labels[key] = value
+
+	return nil
+}
+
+// decodeField decodes fields map entry.
+func (r *Record) decodeField(data []byte) error {
+	var (
+		key   string
+		value Value
+	)
+
+	if err := decode(data, func(d *decoder, field, wireType int) (err error) {
+		switch field {
+		case 1:
+			key, err = d.string(wireType)
+		case 2:
+			err = d.message(wireType, value.decode)
+		default:
+			err = d.skip(wireType)
+		}
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if r.Fields == nil {
+		r.Fields = make(map[string]Value)
+	}
+
+	r.Fields[key] = value
+
+	return nil
+}
+
+// decode decodes log level message.
+func (l *Level) decode(data []byte) error {
+	return decode(data, func(d *decoder, field, wireType int) (err error) {
+		switch field {
+		case 1:
+			l.Value, err = d.sint64(wireType)
+		case 2:
+			l.Name, err = d.string(wireType)
+		default:
+			err = d.skip(wireType)
+		}
+
+		return err
+	})
+}
+
+// decode decodes source location message.
+func (s *Source) decode(data []byte) error {
+	return decode(data, func(d *decoder, field, wireType int) (err error) {
+		switch field {
+		case 1:
+			s.Line, err = d.int64(wireType)
+		case 2:
+			s.Name, err = d.string(wireType)
+		case 3:
+			s.Path, err = d.string(wireType)
+		case 4:
+			s.Function, err = d.string(wireType)
+		default:
+			err = d.skip(wireType)
+		}
+
+		return err
+	})
+}
+
+// decode decodes named value message. The last decoded oneof field wins.
+func (v *Value) decode(data []byte) error {
+	return decode(data, func(d *decoder, field, wireType int) (err error) {
+		switch field {
+		case 1:
+			*v = Value{Kind: KindString}
+			v.String, err = d.string(wireType)
+		case 2:
+			*v = Value{Kind: KindInt}
+			v.Int, err = d.sint64(wireType)
+		case 3:
+			*v = Value{Kind: KindUint}
+			v.Uint, err = d.uint64(wireType)
+		case 4:
+			*v = Value{Kind: KindDouble}
+			v.Double, err = d.double(wireType)
+		case 5:
+			var value uint64
+
+			*v = Value{Kind: KindBool}
+			value, err = d.uint64(wireType)
+			v.Bool = value != 0
+		default:
+			err = d.skip(wireType)
+		}
+
+		return err
+	})
+}
+
+// decode decodes all fields from provided data using provided function.
+func decode(data []byte, decodeField func(d *decoder, field, wireType int) error) error {
+	d := &decoder{data: data}
+
+	for len(d.data) != 0 {
+		key, err := d.varint()
+
+		if err != nil {
+			return err
+		}
+
+		if key>>3 == 0 {
+			return ErrInvalidWireType
+		}
+
+		if err := decodeField(d, int(key>>3), int(key&0x7)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// varint decodes unsigned integer encoded as varint.
+func (d *decoder) varint() (uint64, error) {
+	var value uint64
+
+	for index := 0; index < len(d.data); index++ {
+		if index == 9 && d.data[index] > 1 {
+			return 0, ErrVarintOverflow
+		}
+
+		value |= uint64(d.data[index]&0x7F) << (7 * index)
+
+		if d.data[index] < 0x80 {
+			d.data = d.data[index+1:]
+			return value, nil
+		}
+	}
+
+	return 0, ErrUnexpectedEnd
+}
+
+// fixed decodes little-endian value with provided size in bytes.
+func (d *decoder) fixed(size int) (uint64, error) {
+	var value uint64
+
+	if len(d.data) < size {
+		return 0, ErrUnexpectedEnd
+	}
+
+	for index := 0; index < size; index++ {
+		value |= uint64(d.data[index]) << (8 * index)
+	}
+
+	d.data = d.data[size:]
+
+	return value, nil
+}
+
+// bytes decodes length-delimited data.
+func (d *decoder) bytes() ([]byte, error) {
+	length, err := d.varint()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if length > uint64(len(d.data)) {
+		return nil, ErrUnexpectedEnd
+	}
+
+	data := d.data[:length]
+	d.data = d.data[length:]
+
+	return data, nil
+}
+
+// skip skips field value encoded with provided wire type.
+func (d *decoder) skip(wireType int) (err error) {
+	switch wireType {
+	case wireVarint:
+		_, err = d.varint()
+	case wireFixed64:
+		_, err = d.fixed(8)
+	case wireBytes:
+		_, err = d.bytes()
+	case wireFixed32:
+		_, err = d.fixed(4)
+	default:
+		err = ErrInvalidWireType
+	}
+
+	return err
+}
+
+// uint64 decodes unsigned integer field value.
+func (d *decoder) uint64(wireType int) (uint64, error) {
+	if wireType != wireVarint {
+		return 0, ErrInvalidWireType
+	}
+
+	return d.varint()
+}
+
+// int64 decodes integer field value.
+func (d *decoder) int64(wireType int) (int64, error) {
+	value, err := d.uint64(wireType)
+
+	return int64(value), err
+}
+
+// sint64 decodes integer field value encoded with zigzag encoding.
+func (d *decoder) sint64(wireType int) (int64, error) {
+	value, err := d.uint64(wireType)
+
+	return int64(value>>1) ^ -int64(value&1), err
+}
+
+// double decodes floating-point field value.
+func (d *decoder) double(wireType int) (float64, error) {
+	if wireType != wireFixed64 {
+		return 0, ErrInvalidWireType
+	}
+
+	value, err := d.fixed(8)
+
+	return math.Float64frombits(value), err
+}
+
+// string decodes string field value.
+func (d *decoder) string(wireType int) (string, error) {
+	if wireType != wireBytes {
+		return "", ErrInvalidWireType
+	}
+
+	data, err := d.bytes()
+
+	return string(data), err
+}
+
+// message decodes embedded message field value using provided function.
+func (d *decoder) message(wireType int, decode func(data []byte) error) error {
+	if wireType != wireBytes {
+		return ErrInvalidWireType
+	}
+
+	data, err := d.bytes()
+
+	if err != nil {
+		return err
+	}
+
+	return decode(data)
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logpb
+
+import (
+	"math"
+	"sort"
+)
+
+// These constants define protocol buffers wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// An encoder represents protocol buffers encoder state.
+type encoder struct {
+	buffer []byte
+}
+
+// Marshal returns the protocol buffers encoding of log record message. Map
+// entries are encoded sorted by keys and encoding is deterministic.
+func (r *Record) Marshal() ([]byte, error) {
+	e := &encoder{}
+
+	e.string(1, r.ID)
+	e.string(2, r.Type)
+	e.string(3, r.Name)
+	e.int64(4, r.TimeUnixNano)
+	e.message(5, false, r.Level.encode)
+	e.string(6, r.Address)
+	e.string(7, r.Hostname)
+	e.string(8, r.Message)
+	e.message(9, false, r.File.encode)
+
+	for _, key := range sortedKeys(r.Labels) {
+		value := r.Labels[key]
+
+		e.message(10, true, func(e *encoder) {
+			e.string(1, key)
+			e.string(2, value)
+		})
+	}
+
+	fields := make(map[string]struct{}, len(r.Fields))
+
+	for key := range r.Fields {
+		fields[key] = struct{}{}
+	}
+
+	for _, key := range sortedKeys(fields) {
+		value := r.Fields[key]
+
+		e.message(11, true, func(e *encoder) {
+			e.string(1, key)
+			e.message(2, true, value.encode)
+		})
+	}
+
+	return e.buffer, nil
+}
+
+// encode encodes log level message fields.
+func (l *Level) encode(e *encoder) {
+	e.sint64(1, l.Value)
+	e.string(2, l.Name)
+}
+
+// encode encodes source location message fields.
+func (s *Source) encode(e *encoder) {
+	e.int64(1, s.Line)
+	e.string(2, s.Name)
+	e.string(3, s.Path)
+	e.string(4, s.Function)
+}
+
+// encode encodes named value message field selected by kind. Selected field
+// is encoded also with zero value.
+func (v *Value) encode(e *encoder) {
+	switch v.Kind {
+	case KindString:
+		e.tag(1, wireBytes)
+		e.bytes([]byte(v.String))
+	case KindInt:
+		e.tag(2, wireVarint)
+		e.varint(uint64(v.Int<<1) ^ uint64(v.Int>>63))
+	case KindUint:
+		e.tag(3, wireVarint)
+		e.varint(v.Uint)
+	case KindDouble:
+		e.tag(4, wireFixed64)
+		e.fixed64(math.Float64bits(v.Double))
+	case KindBool:
+		e.tag(5, wireVarint)
+
+		if v.Bool {
+			e.varint(1)
+		} else {
+			e.varint(0)
+		}
+	}
+}
+
+// tag encodes field number and wire type.
+func (e *encoder) tag(field, wireType int) {
+	e.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+// varint encodes unsigned integer as varint.
+func (e *encoder) varint(value uint64) {
+	for value >= 0x80 {
+		e.buffer = append(e.buffer, byte(value)|0x80)
+		value >>= 7
+	}
+
+	e.buffer = append(e.buffer, byte(value))
+}
+
+// fixed64 encodes unsigned integer as little-endian 64-bit value.
+func (e *encoder) fixed64(value uint64) {
+	for index := 0; index < 8; index++ {
+		e.buffer = append(e.buffer, byte(value>>(8*index)))
+	}
+}
+
+// bytes encodes length-delimited data.
+func (e *encoder) bytes(data []byte) {
+	e.varint(uint64(len(data)))
+	e.buffer = append(e.buffer, data...)
+}
+
+// string encodes non-empty string field.
+func (e *encoder) string(field int, value string) {
+	if value != "" {
+		e.tag(field, wireBytes)
+		e.bytes([]byte(value))
+	}
+}
+
+// int64 encodes non-zero integer field.
+func (e *encoder) int64(field int, value int64) {
+	if value != 0 {
+		e.tag(field, wireVarint)
+		e.varint(uint64(value))
+	}
+}
+
+// sint64 encodes non-zero integer field with zigzag encoding.
+func (e *encoder) sint64(field int, value int64) {
+	if value != 0 {
+		e.tag(field, wireVarint)
+		e.varint(uint64(value<<1) ^ uint64(value>>63))
+	}
+}
+
+// message encodes embedded message field. Empty embedded message is skipped
+// unless it is required, like for example map entries.
+func (e *encoder) message(field int, required bool, encode func(e *encoder)) {
+	embedded := &encoder{}
+
+	encode(embedded)
+
+	if required || (len(embedded.buffer) != 0) {
+		e.tag(field, wireBytes)
+		e.bytes(embedded.buffer)
+	}
+}
+
+// sortedKeys returns sorted keys of provided map.
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
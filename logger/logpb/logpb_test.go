@@ -0,0 +1,164 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logpb_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger/logpb"
+)
+
+func TestMarshal(test *testing.T) {
+	record := &logpb.Record{
+		ID:      "a",
+		Level:   logpb.Level{Value: -1},
+		Message: "m",
+		Fields:  map[string]logpb.Value{"k": {Kind: logpb.KindInt}},
+	}
+
+	want := []byte{
+		0x0a, 0x01, 'a',
+		0x2a, 0x02, 0x08, 0x01,
+		0x42, 0x01, 'm',
+		0x5a, 0x07, 0x0a, 0x01, 'k', 0x12, 0x02, 0x10, 0x00,
+	}
+
+	got, err := record.Marshal()
+
+	if err != nil {
+		test.Fatal("Marshal() returns an unexpected error", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		test.Errorf("Marshal() = % x; want % x", got, want)
+	}
+}
+
+func TestUnmarshalRoundTrip(test *testing.T) {
+	want := logpb.Record{
+		ID:           "id",
+		Type:         "log",
+		Name:         "logger",
+		TimeUnixNano: 1600000000123456789,
+		Level:        logpb.Level{Value: 6, Name: "info"},
+		Address:      "127.0.0.1",
+		Hostname:     "localhost",
+		Message:      string(bytes.Repeat([]byte{'x'}, 300)),
+		File:         logpb.Source{Line: 42, Name: "main.go", Path: "/src/main.go", Function: "main.main"},
+		Labels:       map[string]string{"service": "api", "empty": ""},
+		Fields: map[string]logpb.Value{
+			"string": logpb.ValueOf("value"),
+			"int":    logpb.ValueOf(math.MinInt64),
+			"uint":   logpb.ValueOf(uint64(math.MaxUint64)),
+			"double": logpb.ValueOf(2.25),
+			"bool":   logpb.ValueOf(false),
+			"none":   {},
+		},
+	}
+
+	data, err := want.Marshal()
+
+	if err != nil {
+		test.Fatal("Marshal() returns an unexpected error", err)
+	}
+
+	var got logpb.Record
+
+	if err := got.Unmarshal(data); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		test.Errorf("Unmarshal() = %#v; want %#v", got, want)
+	}
+}
+
+func TestUnmarshalUnknownFields(test *testing.T) {
+	data := []byte{
+		0x78, 0x01,
+		0x81, 0x01, 0, 0, 0, 0, 0, 0, 0, 0,
+		0x8a, 0x01, 0x01, 'x',
+		0x95, 0x01, 0, 0, 0, 0,
+		0x0a, 0x01, 'a',
+	}
+
+	var record logpb.Record
+
+	if err := record.Unmarshal(data); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if record.ID != "a" {
+		test.Errorf("Unmarshal() ID = %q; want %q", record.ID, "a")
+	}
+}
+
+func TestUnmarshalInvalid(test *testing.T) {
+	tests := []struct {
+		data []byte
+		want error
+	}{
+		{[]byte{0x0a}, logpb.ErrUnexpectedEnd},
+		{[]byte{0x0a, 0x02, 'a'}, logpb.ErrUnexpectedEnd},
+		{[]byte{0x20, 0x80}, logpb.ErrUnexpectedEnd},
+		{[]byte{0x08, 0x01}, logpb.ErrInvalidWireType},
+		{[]byte{0x0b}, logpb.ErrInvalidWireType},
+		{[]byte{0x00}, logpb.ErrInvalidWireType},
+		{[]byte{0x2a, 0x02, 0x12, 0x05}, logpb.ErrUnexpectedEnd},
+		{[]byte{0x2a, 0x02, 0x0a, 0x01}, logpb.ErrInvalidWireType},
+		{append([]byte{0x20}, bytes.Repeat([]byte{0xff}, 10)...), logpb.ErrVarintOverflow},
+	}
+
+	for _, tt := range tests {
+		var record logpb.Record
+
+		if err := record.Unmarshal(tt.data); !errors.Is(err, tt.want) {
+			test.Errorf("Unmarshal(% x) error = %v; want %v", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestValueOf(test *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  logpb.Value
+	}{
+		{"text", logpb.Value{Kind: logpb.KindString, String: "text"}},
+		{int8(-3), logpb.Value{Kind: logpb.KindInt, Int: -3}},
+		{uint16(3), logpb.Value{Kind: logpb.KindUint, Uint: 3}},
+		{float32(0.5), logpb.Value{Kind: logpb.KindDouble, Double: 0.5}},
+		{true, logpb.Value{Kind: logpb.KindBool, Bool: true}},
+		{nil, logpb.Value{Kind: logpb.KindString, String: "<nil>"}},
+		{[]int{1, 2}, logpb.Value{Kind: logpb.KindString, String: "[1 2]"}},
+	}
+
+	for _, tt := range tests {
+		if got := logpb.ValueOf(tt.value); got != tt.want {
+			test.Errorf("ValueOf(%#v) = %+v; want %+v", tt.value, got, tt.want)
+		}
+	}
+
+	if got := (logpb.Value{}).Interface(); got != nil {
+		test.Errorf("Interface() = %#v; want nil", got)
+	}
+
+	if got := logpb.ValueOf(-3).Interface(); got != int64(-3) {
+		test.Errorf("Interface() = %#v; want %#v", got, int64(-3))
+	}
+}
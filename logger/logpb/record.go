@@ -0,0 +1,116 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logpb
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueKind defines which field of the Value oneof is set.
+type ValueKind int
+
+// These constants define kinds of the Value message.
+const (
+	KindNone ValueKind = iota
+	KindString
+	KindInt
+	KindUint
+	KindDouble
+	KindBool
+)
+
+// Record defines log record message.
+type Record struct {
+	ID           string
+	Type         string
+	Name         string
+	TimeUnixNano int64
+	Level        Level
+	Address      string
+	Hostname     string
+	Message      string
+	File         Source
+	Labels       map[string]string
+	Fields       map[string]Value
+}
+
+// Level defines log level message.
+type Level struct {
+	Value int64
+	Name  string
+}
+
+// Source defines source location message.
+type Source struct {
+	Line     int64
+	Name     string
+	Path     string
+	Function string
+}
+
+// Value defines named value message. Only field selected by Kind is set.
+type Value struct {
+	Kind   ValueKind
+	String string
+	Int    int64
+	Uint   uint64
+	Double float64
+	Bool   bool
+}
+
+// ValueOf returns Value message for provided value. Strings, booleans,
+// integers and floating-point numbers are stored in matching fields. Other
+// values and values implementing the fmt.Stringer interface are stored as
+// strings.
+func ValueOf(value interface{}) Value {
+	if _, ok := value.(fmt.Stringer); !ok {
+		valueOf := reflect.ValueOf(value)
+
+		switch valueOf.Kind() {
+		case reflect.String:
+			return Value{Kind: KindString, String: valueOf.String()}
+		case reflect.Bool:
+			return Value{Kind: KindBool, Bool: valueOf.Bool()}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return Value{Kind: KindInt, Int: valueOf.Int()}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return Value{Kind: KindUint, Uint: valueOf.Uint()}
+		case reflect.Float32, reflect.Float64:
+			return Value{Kind: KindDouble, Double: valueOf.Float()}
+		}
+	}
+
+	return Value{Kind: KindString, String: fmt.Sprint(value)}
+}
+
+// Interface returns value of field selected by Kind. It returns nil if no
+// field is set.
+func (v Value) Interface() interface{} {
+	switch v.Kind {
+	case KindString:
+		return v.String
+	case KindInt:
+		return v.Int
+	case KindUint:
+		return v.Uint
+	case KindDouble:
+		return v.Double
+	case KindBool:
+		return v.Bool
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxDepth defines maximum nesting depth of decoded arrays and maps.
+const MaxDepth = 1000
+
+// These errors are returned when data cannot be decoded from MessagePack.
+var (
+	ErrUnexpectedEnd = errors.New("unexpected end of data")      // nolint:gochecknoglobals
+	ErrInvalidType   = errors.New("invalid or unsupported type") // nolint:gochecknoglobals
+	ErrTrailingData  = errors.New("trailing data")               // nolint:gochecknoglobals
+	ErrMaxDepth      = errors.New("maximum depth exceeded")      // nolint:gochecknoglobals
+)
+
+// A decoder represents MessagePack decoder state.
+type decoder struct {
+	data   []byte
+	offset int
+	depth  int
+}
+
+// Unmarshal decodes MessagePack data and it stores the result in the value
+// pointed to by provided value. Decoded value is stored like by the
+// encoding/json package, using the same json tags and conversion rules. For
+// a pointer to an empty interface maps are decoded as map[string]interface{},
+// arrays as []interface{}, binary data as []byte, integers as int64 or uint64
+// and floats as float64.
+func Unmarshal(data []byte, value interface{}) error {
+	d := &decoder{data: data}
+
+	decoded, err := d.decode()
+
+	if err != nil {
+		return err
+	}
+
+	if d.offset != len(d.data) {
+		return fmt.Errorf("cannot decode at offset %d: %w", d.offset, ErrTrailingData)
+	}
+
+	if pointer, ok := value.(*interface{}); ok {
+		*pointer = decoded
+		return nil
+	}
+
+	bytes, err := json.Marshal(decoded)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, value)
+}
+
+// decode decodes next value.
+func (d *decoder) decode() (interface{}, error) { // nolint:gocyclo
+	marker, err := d.read(1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind := marker[0]; {
+	case kind <= 0x7f:
+		return int64(kind), nil
+	case kind >= typeNegative:
+		return int64(int8(kind)), nil
+	case (kind & 0xf0) == typeFixMap:
+		return d.decodeMap(int(kind & 0x0f))
+	case (kind & 0xf0) == typeFixArray:
+		return d.decodeArray(int(kind & 0x0f))
+	case (kind & 0xe0) == typeFixStr:
+		return d.decodeString(int(kind & 0x1f))
+	case kind == typeNil:
+		return nil, nil
+	case kind == typeFalse:
+		return false, nil
+	case kind == typeTrue:
+		return true, nil
+	case kind == typeBin8, kind == typeBin16, kind == typeBin32:
+		length, err := d.readLength(1 << (kind - typeBin8))
+
+		if err != nil {
+			return nil, err
+		}
+
+		bytes, err := d.read(length)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]byte(nil), bytes...), nil
+	case kind == typeFloat32:
+		bytes, err := d.read(4)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(bytes))), nil
+	case kind == typeFloat64:
+		bytes, err := d.read(8)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return math.Float64frombits(binary.BigEndian.Uint64(bytes)), nil
+	case kind >= typeUint8 && kind <= typeUint64:
+		value, err := d.readUint(1 << (kind - typeUint8))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if value > math.MaxInt64 {
+			return value, nil
+		}
+
+		return int64(value), nil
+	case kind >= typeInt8 && kind <= typeInt64:
+		size := 1 << (kind - typeInt8)
+
+		value, err := d.readUint(size)
+
+		if err != nil {
+			return nil, err
+		}
+
+		shift := 64 - 8*size
+
+		return int64(value<<shift) >> shift, nil
+	case kind >= typeStr8 && kind <= typeStr32:
+		length, err := d.readLength(1 << (kind - typeStr8))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeString(length)
+	case kind == typeArray16, kind == typeArray32:
+		length, err := d.readLength(2 << (kind - typeArray16))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeArray(length)
+	case kind == typeMap16, kind == typeMap32:
+		length, err := d.readLength(2 << (kind - typeMap16))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeMap(length)
+	default:
+		return nil, fmt.Errorf("cannot decode type 0x%02x at offset %d: %w", kind, d.offset-1, ErrInvalidType)
+	}
+}
+
+// decodeString decodes string with provided length.
+func (d *decoder) decodeString(length int) (interface{}, error) {
+	bytes, err := d.read(length)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(bytes), nil
+}
+
+// decodeArray decodes array with provided number of elements.
+func (d *decoder) decodeArray(length int) (interface{}, error) {
+	if err := d.enter(length); err != nil {
+		return nil, err
+	}
+
+	defer d.leave()
+
+	array := make([]interface{}, length)
+
+	for index := range array {
+		value, err := d.decode()
+
+		if err != nil {
+			return nil, err
+		}
+
+		array[index] = value
+	}
+
+	return array, nil
+}
+
+// decodeMap decodes map with provided number of key-value pairs. Keys that
+// are not strings are converted to strings.
+func (d *decoder) decodeMap(length int) (interface{}, error) {
+	if err := d.enter(2 * length); err != nil {
+		return nil, err
+	}
+
+	defer d.leave()
+
+	decoded := make(map[string]interface{}, length)
+
+	for index := 0; index < length; index++ {
+		key, err := d.decode()
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decode()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if name, ok := key.(string); ok {
+			decoded[name] = value
+		} else {
+			decoded[fmt.Sprint(key)] = value
+		}
+	}
+
+	return decoded, nil
+}
+
+// enter enters nested array or map with provided number of values. Every
+// value takes at least one byte and it allows to reject too long lengths
+// before allocation.
+func (d *decoder) enter(values int) error {
+	if values > len(d.data)-d.offset {
+		return fmt.Errorf("cannot decode %d values at offset %d: %w", values, d.offset, ErrUnexpectedEnd)
+	}
+
+	if d.depth++; d.depth > MaxDepth {
+		return fmt.Errorf("cannot decode at offset %d: %w", d.offset, ErrMaxDepth)
+	}
+
+	return nil
+}
+
+// leave leaves nested array or map.
+func (d *decoder) leave() {
+	d.depth--
+}
+
+// read returns next bytes with provided length.
+func (d *decoder) read(length int) ([]byte, error) {
+	if (length < 0) || (length > len(d.data)-d.offset) {
+		return nil, fmt.Errorf("cannot read %d bytes at offset %d: %w", length, d.offset, ErrUnexpectedEnd)
+	}
+
+	bytes := d.data[d.offset : d.offset+length]
+	d.offset += length
+
+	return bytes, nil
+}
+
+// readUint reads big-endian unsigned integer with provided size in bytes.
+func (d *decoder) readUint(size int) (uint64, error) {
+	bytes, err := d.read(size)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var value uint64
+
+	for _, b := range bytes {
+		value = value<<8 | uint64(b)
+	}
+
+	return value, nil
+}
+
+// readLength reads length of string, binary data, array or map.
+func (d *decoder) readLength(size int) (int, error) {
+	value, err := d.readUint(size)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if value > math.MaxInt32 {
+		return 0, fmt.Errorf("cannot decode length %d at offset %d: %w", value, d.offset, ErrUnexpectedEnd)
+	}
+
+	return int(value), nil
+}
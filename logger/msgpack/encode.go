@@ -0,0 +1,421 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgpack
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// These constants define MessagePack format type markers.
+const (
+	typeNil      = 0xc0
+	typeFalse    = 0xc2
+	typeTrue     = 0xc3
+	typeBin8     = 0xc4
+	typeBin16    = 0xc5
+	typeBin32    = 0xc6
+	typeFloat32  = 0xca
+	typeFloat64  = 0xcb
+	typeUint8    = 0xcc
+	typeUint16   = 0xcd
+	typeUint32   = 0xce
+	typeUint64   = 0xcf
+	typeInt8     = 0xd0
+	typeInt16    = 0xd1
+	typeInt32    = 0xd2
+	typeInt64    = 0xd3
+	typeStr8     = 0xd9
+	typeStr16    = 0xda
+	typeStr32    = 0xdb
+	typeArray16  = 0xdc
+	typeArray32  = 0xdd
+	typeMap16    = 0xde
+	typeMap32    = 0xdf
+	typeFixMap   = 0x80
+	typeFixArray = 0x90
+	typeFixStr   = 0xa0
+	typeNegative = 0xe0
+)
+
+// ErrUnsupportedType is returned when value cannot be encoded to MessagePack.
+var ErrUnsupportedType = errors.New("unsupported type") // nolint:gochecknoglobals
+
+var gJSONNumberType = reflect.TypeOf(json.Number("")) // nolint:gochecknoglobals
+
+// An encoder represents MessagePack encoder state.
+type encoder struct {
+	buffer []byte
+}
+
+// Marshal returns the MessagePack encoding of provided value. Booleans,
+// numbers, strings, byte slices, slices, arrays, maps and structures are
+// supported. Values implementing the json.Marshaler or
+// encoding.TextMarshaler interfaces are encoded like by the encoding/json
+// package.
+func Marshal(value interface{}) ([]byte, error) {
+	e := &encoder{}
+
+	if err := e.encode(reflect.ValueOf(value)); err != nil {
+		return nil, err
+	}
+
+	return e.buffer, nil
+}
+
+// encode encodes provided value.
+func (e *encoder) encode(value reflect.Value) error { // nolint:gocyclo
+	if !value.IsValid() {
+		e.buffer = append(e.buffer, typeNil)
+		return nil
+	}
+
+	switch value.Kind() { // nolint:exhaustive
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			e.buffer = append(e.buffer, typeNil)
+			return nil
+		}
+	}
+
+	if value.Type() == gJSONNumberType {
+		return e.encodeNumber(json.Number(value.String()))
+	}
+
+	if value.CanInterface() {
+		switch marshaler := value.Interface().(type) {
+		case json.Marshaler:
+			return e.encodeJSON(marshaler)
+		case encoding.TextMarshaler:
+			text, err := marshaler.MarshalText()
+
+			if err != nil {
+				return err
+			}
+
+			e.encodeString(string(text))
+
+			return nil
+		}
+	}
+
+	switch value.Kind() { // nolint:exhaustive
+	case reflect.Bool:
+		e.encodeBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.encodeInt(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.encodeUint(value.Uint())
+	case reflect.Float32:
+		e.buffer = append(e.buffer, typeFloat32)
+		e.buffer = appendUint32(e.buffer, math.Float32bits(float32(value.Float())))
+	case reflect.Float64:
+		e.buffer = append(e.buffer, typeFloat64)
+		e.buffer = appendUint64(e.buffer, math.Float64bits(value.Float()))
+	case reflect.String:
+		e.encodeString(value.String())
+	case reflect.Slice, reflect.Array:
+		return e.encodeArray(value)
+	case reflect.Map:
+		return e.encodeMap(value)
+	case reflect.Struct:
+		return e.encodeStruct(value)
+	case reflect.Ptr, reflect.Interface:
+		return e.encode(value.Elem())
+	default:
+		return fmt.Errorf("cannot encode %s: %w", value.Type(), ErrUnsupportedType)
+	}
+
+	return nil
+}
+
+// encodeJSON encodes value provided by the json.Marshaler interface.
+func (e *encoder) encodeJSON(marshaler json.Marshaler) error {
+	data, err := marshaler.MarshalJSON()
+
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+
+	var value interface{}
+
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	return e.encode(reflect.ValueOf(value))
+}
+
+// encodeNumber encodes JSON number as integer if possible.
+func (e *encoder) encodeNumber(number json.Number) error {
+	if value, err := number.Int64(); err == nil {
+		e.encodeInt(value)
+		return nil
+	}
+
+	if value, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+		e.encodeUint(value)
+		return nil
+	}
+
+	value, err := number.Float64()
+
+	if err != nil {
+		return err
+	}
+
+	e.buffer = append(e.buffer, typeFloat64)
+	e.buffer = appendUint64(e.buffer, math.Float64bits(value))
+
+	return nil
+}
+
+// encodeBool encodes boolean value.
+func (e *encoder) encodeBool(value bool) {
+	if value {
+		e.buffer = append(e.buffer, typeTrue)
+	} else {
+		e.buffer = append(e.buffer, typeFalse)
+	}
+}
+
+// encodeInt encodes signed integer using the smallest possible format.
+func (e *encoder) encodeInt(value int64) {
+	switch {
+	case value >= 0:
+		e.encodeUint(uint64(value))
+	case value >= -32:
+		e.buffer = append(e.buffer, byte(value))
+	case value >= math.MinInt8:
+		e.buffer = append(e.buffer, typeInt8, byte(value))
+	case value >= math.MinInt16:
+		e.buffer = append(e.buffer, typeInt16)
+		e.buffer = appendUint16(e.buffer, uint16(value))
+	case value >= math.MinInt32:
+		e.buffer = append(e.buffer, typeInt32)
+		e.buffer = appendUint32(e.buffer, uint32(value))
+	default:
+		e.buffer = append(e.buffer, typeInt64)
+		e.buffer = appendUint64(e.buffer, uint64(value))
+	}
+}
+
+// encodeUint encodes unsigned integer using the smallest possible format.
+func (e *encoder) encodeUint(value uint64) {
+	switch {
+	case value <= math.MaxInt8:
+		e.buffer = append(e.buffer, byte(value))
+	case value <= math.MaxUint8:
+		e.buffer = append(e.buffer, typeUint8, byte(value))
+	case value <= math.MaxUint16:
+		e.buffer = append(e.buffer, typeUint16)
+		e.buffer = appendUint16(e.buffer, uint16(value))
+	case value <= math.MaxUint32:
+		e.buffer = append(e.buffer, typeUint32)
+		e.buffer = appendUint32(e.buffer, uint32(value))
+	default:
+		e.buffer = append(e.buffer, typeUint64)
+		e.buffer = appendUint64(e.buffer, value)
+	}
+}
+
+// encodeString encodes string value.
+func (e *encoder) encodeString(value string) {
+	length := len(value)
+
+	switch {
+	case length < 32:
+		e.buffer = append(e.buffer, typeFixStr|byte(length))
+	case length <= math.MaxUint8:
+		e.buffer = append(e.buffer, typeStr8, byte(length))
+	case length <= math.MaxUint16:
+		e.buffer = append(e.buffer, typeStr16)
+		e.buffer = appendUint16(e.buffer, uint16(length))
+	default:
+		e.buffer = append(e.buffer, typeStr32)
+		e.buffer = appendUint32(e.buffer, uint32(length))
+	}
+
+	e.buffer = append(e.buffer, value...)
+}
+
+// encodeBytes encodes byte slice value.
+func (e *encoder) encodeBytes(value []byte) {
+	length := len(value)
+
+	switch {
+	case length <= math.MaxUint8:
+		e.buffer = append(e.buffer, typeBin8, byte(length))
+	case length <= math.MaxUint16:
+		e.buffer = append(e.buffer, typeBin16)
+		e.buffer = appendUint16(e.buffer, uint16(length))
+	default:
+		e.buffer = append(e.buffer, typeBin32)
+		e.buffer = appendUint32(e.buffer, uint32(length))
+	}
+
+	e.buffer = append(e.buffer, value...)
+}
+
+// encodeArrayHeader encodes array header with provided number of elements.
+func (e *encoder) encodeArrayHeader(length int) {
+	switch {
+	case length < 16:
+		e.buffer = append(e.buffer, typeFixArray|byte(length))
+	case length <= math.MaxUint16:
+		e.buffer = append(e.buffer, typeArray16)
+		e.buffer = appendUint16(e.buffer, uint16(length))
+	default:
+		e.buffer = append(e.buffer, typeArray32)
+		e.buffer = appendUint32(e.buffer, uint32(length))
+	}
+}
+
+// encodeMapHeader encodes map header with provided number of key-value pairs.
+func (e *encoder) encodeMapHeader(length int) {
+	switch {
+	case length < 16:
+		e.buffer = append(e.buffer, typeFixMap|byte(length))
+	case length <= math.MaxUint16:
+		e.buffer = append(e.buffer, typeMap16)
+		e.buffer = appendUint16(e.buffer, uint16(length))
+	default:
+		e.buffer = append(e.buffer, typeMap32)
+		e.buffer = appendUint32(e.buffer, uint32(length))
+	}
+}
+
+// encodeArray encodes slice or array. Byte slices are encoded as binary data.
+func (e *encoder) encodeArray(value reflect.Value) error {
+	if (value.Kind() == reflect.Slice) && (value.Type().Elem().Kind() == reflect.Uint8) {
+		e.encodeBytes(value.Bytes())
+		return nil
+	}
+
+	e.encodeArrayHeader(value.Len())
+
+	for index := 0; index < value.Len(); index++ {
+		if err := e.encode(value.Index(index)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeMap encodes map with keys sorted like by the encoding/json package.
+func (e *encoder) encodeMap(value reflect.Value) error {
+	keys := make([]string, 0, value.Len())
+	values := make(map[string]reflect.Value, value.Len())
+
+	iterator := value.MapRange()
+
+	for iterator.Next() {
+		key, err := mapKey(iterator.Key())
+
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+		values[key] = iterator.Value()
+	}
+
+	sort.Strings(keys)
+
+	e.encodeMapHeader(len(keys))
+
+	for _, key := range keys {
+		e.encodeString(key)
+
+		if err := e.encode(values[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeStruct encodes structure as map of exported fields.
+func (e *encoder) encodeStruct(value reflect.Value) error {
+	var fields []field
+
+	for _, f := range getFields(value.Type()) {
+		fieldValue, ok := fieldByIndex(value, f.index)
+
+		if ok && !(f.omitEmpty && isEmpty(fieldValue)) {
+			f.value = fieldValue
+			fields = append(fields, f)
+		}
+	}
+
+	e.encodeMapHeader(len(fields))
+
+	for _, f := range fields {
+		e.encodeString(f.name)
+
+		if err := e.encode(f.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapKey returns map key as string like the encoding/json package.
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch key.Kind() { // nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("cannot encode map key %s: %w", key.Type(), ErrUnsupportedType)
+	}
+}
+
+// appendUint16 appends big-endian 16-bit unsigned integer.
+func appendUint16(buffer []byte, value uint16) []byte {
+	return append(buffer, byte(value>>8), byte(value))
+}
+
+// appendUint32 appends big-endian 32-bit unsigned integer.
+func appendUint32(buffer []byte, value uint32) []byte {
+	return append(buffer, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+}
+
+// appendUint64 appends big-endian 64-bit unsigned integer.
+func appendUint64(buffer []byte, value uint64) []byte {
+	return appendUint32(appendUint32(buffer, uint32(value>>32)), uint32(value))
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgpack
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field defines encoded structure field.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	value     reflect.Value
+}
+
+// getFields returns encoded fields of provided structure type. Field names
+// and options are taken from json tags. Fields of embedded structures without
+// json tag name are promoted like by the encoding/json package.
+func getFields(structType reflect.Type) []field {
+	var fields []field
+
+	for index := 0; index < structType.NumField(); index++ {
+		structField := structType.Field(index)
+
+		tag := structField.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, options := tag, ""
+
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+
+		fieldType := structField.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if structField.Anonymous && (name == "") && (fieldType.Kind() == reflect.Struct) {
+			for _, promoted := range getFields(fieldType) {
+				promoted.index = append([]int{index}, promoted.index...)
+				fields = append(fields, promoted)
+			}
+
+			continue
+		}
+
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = structField.Name
+		}
+
+		fields = append(fields, field{
+			name:      name,
+			index:     []int{index},
+			omitEmpty: hasOption(options, "omitempty"),
+		})
+	}
+
+	return fields
+}
+
+// hasOption returns true if comma-separated json tag options contain provided
+// option.
+func hasOption(options, option string) bool {
+	for _, value := range strings.Split(options, ",") {
+		if value == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldByIndex returns nested structure field. It returns false if field is
+// inside of a nil embedded structure.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for position, fieldIndex := range index {
+		if position > 0 && (value.Kind() == reflect.Ptr) {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(fieldIndex)
+	}
+
+	return value, true
+}
+
+// isEmpty returns true if value is empty like for the json omitempty option.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() { // nolint:exhaustive
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgpack_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger/msgpack"
+)
+
+func TestMarshal(test *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{false, []byte{0xc2}},
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0xcc, 0x80}},
+		{-1, []byte{0xff}},
+		{-32, []byte{0xe0}},
+		{-33, []byte{0xd0, 0xdf}},
+		{65536, []byte{0xce, 0x00, 0x01, 0x00, 0x00}},
+		{int64(math.MinInt64), []byte{0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{map[string]int{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		{struct {
+			A int    `json:"a"`
+			B string `json:"-"`
+			C int    `json:"c,omitempty"`
+			d int
+		}{A: 1, B: "b", d: 4}, []byte{0x81, 0xa1, 'a', 0x01}},
+	}
+
+	for _, tt := range tests {
+		got, err := msgpack.Marshal(tt.value)
+
+		if err != nil {
+			test.Errorf("Marshal(%#v) returns an unexpected error %v", tt.value, err)
+		}
+
+		if !bytes.Equal(got, tt.want) {
+			test.Errorf("Marshal(%#v) = % x; want % x", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalUnsupportedType(test *testing.T) {
+	_, err := msgpack.Marshal(make(chan int))
+
+	if !errors.Is(err, msgpack.ErrUnsupportedType) {
+		test.Errorf("Marshal() error = %v; want %v", err, msgpack.ErrUnsupportedType)
+	}
+}
+
+func TestUnmarshalRoundTrip(test *testing.T) {
+	value := map[string]interface{}{
+		"nil":    nil,
+		"bool":   true,
+		"int":    int64(-1000),
+		"uint":   uint64(math.MaxUint64),
+		"float":  2.25,
+		"string": string(bytes.Repeat([]byte{'x'}, 300)),
+		"bytes":  []byte{0, 1, 2},
+		"array":  []interface{}{int64(1), "two", []interface{}{}},
+		"map":    map[string]interface{}{"key": "value"},
+	}
+
+	data, err := msgpack.Marshal(value)
+
+	if err != nil {
+		test.Fatal("Marshal() returns an unexpected error", err)
+	}
+
+	var got interface{}
+
+	if err := msgpack.Unmarshal(data, &got); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(got, value) {
+		test.Errorf("Unmarshal() = %#v; want %#v", got, value)
+	}
+}
+
+func TestUnmarshalStruct(test *testing.T) {
+	type object struct {
+		Name string    `json:"name"`
+		Time time.Time `json:"time"`
+		List []int     `json:"list"`
+	}
+
+	want := object{
+		Name: "object",
+		Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		List: []int{1, 2, 3},
+	}
+
+	data, err := msgpack.Marshal(want)
+
+	if err != nil {
+		test.Fatal("Marshal() returns an unexpected error", err)
+	}
+
+	var got object
+
+	if err := msgpack.Unmarshal(data, &got); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		test.Errorf("Unmarshal() = %#v; want %#v", got, want)
+	}
+}
+
+func TestUnmarshalInvalid(test *testing.T) {
+	tests := []struct {
+		data []byte
+		want error
+	}{
+		{[]byte{}, msgpack.ErrUnexpectedEnd},
+		{[]byte{0xa3, 'a'}, msgpack.ErrUnexpectedEnd},
+		{[]byte{0xdd, 0xff, 0xff, 0xff, 0xff}, msgpack.ErrUnexpectedEnd},
+		{[]byte{0xc1}, msgpack.ErrInvalidType},
+		{[]byte{0x01, 0x02}, msgpack.ErrTrailingData},
+		{append(bytes.Repeat([]byte{0x91}, msgpack.MaxDepth+1), 0xc0), msgpack.ErrMaxDepth},
+	}
+
+	for _, tt := range tests {
+		var value interface{}
+
+		if err := msgpack.Unmarshal(tt.data, &value); !errors.Is(err, tt.want) {
+			test.Errorf("Unmarshal(% x) error = %v; want %v", tt.data, err, tt.want)
+		}
+	}
+}
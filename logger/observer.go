@@ -0,0 +1,255 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandler("observer", func() Handler {
+		return NewObserver()
+	})
+}
+
+// ObservedEntry defines log entry recorded by the Observer log handler.
+type ObservedEntry struct {
+	Time      time.Time
+	Level     Level
+	Name      string
+	Template  string
+	Message   string
+	File      Source
+	Arguments Arguments
+	Fields    map[string]interface{}
+	Context   context.Context
+}
+
+// ObservedLogs defines list of log entries recorded by the Observer log
+// handler.
+type ObservedLogs []ObservedEntry
+
+// An Observer represents a log handler object for recording emitted log
+// records as structured log entries. It is intended for unit tests of code
+// that logs. Recorded log entries are safe to read after logger Flush call.
+type Observer struct {
+	logs   ObservedLogs
+	stream *Stream
+}
+
+// NewObserver creates a new Observer log handler object.
+func NewObserver() *Observer {
+	o := &Observer{
+		stream: NewStream(),
+	}
+
+	o.stream.writer = io.Discard
+	o.stream.handler = o.observe
+
+	return o
+}
+
+// All returns copy of all recorded log entries.
+func (o *Observer) All() ObservedLogs {
+	o.stream.RLock()
+	defer o.stream.RUnlock()
+
+	return append(ObservedLogs(nil), o.logs...)
+}
+
+// Len returns number of recorded log entries.
+func (o *Observer) Len() int {
+	o.stream.RLock()
+	defer o.stream.RUnlock()
+
+	return len(o.logs)
+}
+
+// FilterLevel returns recorded log entries with provided log level value.
+func (o *Observer) FilterLevel(level int) ObservedLogs {
+	return o.All().FilterLevel(level)
+}
+
+// FilterMessage returns recorded log entries with provided formatted message.
+func (o *Observer) FilterMessage(message string) ObservedLogs {
+	return o.All().FilterMessage(message)
+}
+
+// Reset removes all recorded log entries.
+func (o *Observer) Reset() {
+	o.stream.Lock()
+	defer o.stream.Unlock()
+
+	o.logs = nil
+}
+
+// SetHandlerName sets log handler name.
+func (o *Observer) SetHandlerName(name string) Handler {
+	o.stream.SetHandlerName(name)
+	return o
+}
+
+// GetHandlerName returns log handler name.
+func (o *Observer) GetHandlerName() string {
+	return o.stream.GetHandlerName()
+}
+
+// Enable enables log handler.
+func (o *Observer) Enable() Handler {
+	o.stream.Enable()
+	return o
+}
+
+// Disable disabled log handler.
+func (o *Observer) Disable() Handler {
+	o.stream.Disable()
+	return o
+}
+
+// IsEnabled returns if log handler is enabled.
+func (o *Observer) IsEnabled() bool {
+	return o.stream.IsEnabled()
+}
+
+// SetFormatter sets log formatter.
+func (o *Observer) SetFormatter(formatter *Formatter) Handler {
+	o.stream.SetFormatter(formatter)
+	return o
+}
+
+// GetFormatter returns log formatter.
+func (o *Observer) GetFormatter() *Formatter {
+	return o.stream.GetFormatter()
+}
+
+// SetLevel sets log level.
+func (o *Observer) SetLevel(level int) Handler {
+	o.stream.SetLevel(level)
+	return o
+}
+
+// SetMinimumLevel sets minimum log level.
+func (o *Observer) SetMinimumLevel(level int) Handler {
+	o.stream.SetMinimumLevel(level)
+	return o
+}
+
+// GetMinimumLevel returns minimum log level.
+func (o *Observer) GetMinimumLevel() int {
+	return o.stream.GetMinimumLevel()
+}
+
+// SetMaximumLevel sets maximum log level.
+func (o *Observer) SetMaximumLevel(level int) Handler {
+	o.stream.SetMaximumLevel(level)
+	return o
+}
+
+// GetMaximumLevel returns maximum log level.
+func (o *Observer) GetMaximumLevel() int {
+	return o.stream.GetMaximumLevel()
+}
+
+// SetLevelRange sets minimum and maximum log level values.
+func (o *Observer) SetLevelRange(min, max int) Handler {
+	o.stream.SetLevelRange(min, max)
+	return o
+}
+
+// GetLevelRange returns minimum and maximum log level values.
+func (o *Observer) GetLevelRange() (min, max int) {
+	return o.stream.GetLevelRange()
+}
+
+// Emit records log record as structured log entry.
+func (o *Observer) Emit(record *Record) error {
+	return o.stream.Emit(record)
+}
+
+// Close closes log handler. Recorded log entries are kept.
+func (o *Observer) Close() error {
+	return o.stream.Close()
+}
+
+// observe is a stream handler that records log record as structured log entry.
+func (o *Observer) observe(_ io.Writer, record *Record, formatter *Formatter) error {
+	message, err := formatter.FormatMessage(record)
+
+	if err != nil {
+		return NewRuntimeError("cannot format message", err)
+	}
+
+	entry := ObservedEntry{
+		Time:      record.Time,
+		Level:     record.Level,
+		Name:      record.Name,
+		Template:  record.Message,
+		Message:   message,
+		File:      record.File,
+		Arguments: append(Arguments(nil), record.Arguments...),
+		Fields:    make(map[string]interface{}),
+		Context:   record.Context,
+	}
+
+	for key, value := range record.Fields {
+		entry.Fields[key] = value
+	}
+
+	for _, argument := range record.Arguments {
+		if fields, ok := getNamedFields(argument); ok {
+			for _, field := range fields {
+				entry.Fields[field.Key] = field.Value
+			}
+		}
+	}
+
+	o.logs = append(o.logs, entry)
+
+	return nil
+}
+
+// Len returns number of log entries.
+func (l ObservedLogs) Len() int {
+	return len(l)
+}
+
+// FilterLevel returns log entries with provided log level value.
+func (l ObservedLogs) FilterLevel(level int) ObservedLogs {
+	return l.Filter(func(entry *ObservedEntry) bool {
+		return entry.Level.Value == level
+	})
+}
+
+// FilterMessage returns log entries with provided formatted message.
+func (l ObservedLogs) FilterMessage(message string) ObservedLogs {
+	return l.Filter(func(entry *ObservedEntry) bool {
+		return entry.Message == message
+	})
+}
+
+// Filter returns log entries for which provided function returns true.
+func (l ObservedLogs) Filter(keep func(entry *ObservedEntry) bool) ObservedLogs {
+	var filtered ObservedLogs
+
+	for index := range l {
+		if keep(&l[index]) {
+			filtered = append(filtered, l[index])
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestObserver(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer)
+
+	log.Info("Hello {p}", "world")
+	log.Warning("Named {user}", logger.Named{"user": "bob"})
+	log.Info("Bye")
+	log.Flush()
+
+	if observer.Len() != 3 {
+		test.Fatalf("Len() = %d; want 3", observer.Len())
+	}
+
+	infos := observer.FilterLevel(logger.InfoLevel)
+
+	if infos.Len() != 2 {
+		test.Errorf("FilterLevel().Len() = %d; want 2", infos.Len())
+	}
+
+	entries := observer.FilterMessage("Hello world")
+
+	if entries.Len() != 1 {
+		test.Fatalf("FilterMessage().Len() = %d; want 1", entries.Len())
+	}
+
+	if entries[0].Template != "Hello {p}" {
+		test.Errorf("Template = %q; want \"Hello {p}\"", entries[0].Template)
+	}
+
+	if entries[0].File.Name != "observer_test.go" {
+		test.Errorf("File.Name = %q; want \"observer_test.go\"", entries[0].File.Name)
+	}
+
+	warnings := observer.FilterLevel(logger.WarningLevel).FilterMessage("Named bob")
+
+	if (warnings.Len() != 1) || (warnings[0].Fields["user"] != "bob") {
+		test.Errorf("FilterLevel().FilterMessage() = %v; want entry with user field", warnings)
+	}
+
+	observer.Reset()
+
+	if observer.Len() != 0 {
+		test.Errorf("Len() = %d; want 0", observer.Len())
+	}
+}
+
+func TestObserverFieldsArguments(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer)
+
+	fields := logger.Fields{{Key: "id", Value: 2}}
+
+	log.Info("{user}", logger.Fields{{Key: "user", Value: "bob"}})
+	log.Info("{id}", &fields)
+	log.Flush()
+
+	entries := observer.All()
+
+	if len(entries) != 2 {
+		test.Fatalf("len(All()) = %d; want 2", len(entries))
+	}
+
+	if user := entries[0].Fields["user"]; user != "bob" {
+		test.Errorf("Fields[user] = %v; want bob", user)
+	}
+
+	if id := entries[1].Fields["id"]; id != 2 {
+		test.Errorf("Fields[id] = %v; want 2", id)
+	}
+
+	if entries[1].Message != "2" {
+		test.Errorf("Message = %q; want %q", entries[1].Message, "2")
+	}
+}
+
+func TestObserverDefaultFields(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetDefaultFields(logger.Named{"service": "api", "user": "alice"})
+
+	log.Info("{user}", logger.Named{"user": "bob"})
+	log.Flush()
+
+	entries := observer.All()
+
+	if len(entries) != 1 {
+		test.Fatalf("len(All()) = %d; want 1", len(entries))
+	}
+
+	if service := entries[0].Fields["service"]; service != "api" {
+		test.Errorf("Fields[service] = %v; want api", service)
+	}
+
+	if user := entries[0].Fields["user"]; user != "bob" {
+		test.Errorf("Fields[user] = %v; want bob", user)
+	}
+}
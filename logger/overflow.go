@@ -0,0 +1,51 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"time"
+)
+
+// OverflowPolicy defines behavior of logger when logger worker thread queue
+// is full.
+type OverflowPolicy int
+
+// These constants define overflow policies.
+const (
+	// Block blocks the calling goroutine until log record can be queued.
+	Block OverflowPolicy = iota
+
+	// DropNewest drops the log record that cannot be queued.
+	DropNewest
+
+	// DropOldest drops the oldest queued log record to make room for a new
+	// one.
+	DropOldest
+)
+
+// DroppedReportInterval defines minimum interval between warning log records
+// that report number of dropped log records.
+const DroppedReportInterval = time.Second
+
+// DroppedMessage defines message of warning log record that reports number of
+// dropped log records.
+const DroppedMessage = "dropped {p} log records"
+
+// dropCounter counts dropped log records of logger.
+type dropCounter struct {
+	total    uint64
+	pending  uint64
+	reported int64
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+const overflowDropped = 10
+
+func testOverflowPolicy(test *testing.T, policy logger.OverflowPolicy) *logger.Observer {
+	log, release := newBlockedLogger(logger.New().SetOverflowPolicy(policy))
+
+	for index := 0; index < logger.DefaultQueueLength+overflowDropped; index++ {
+		log.Info("Message {p}", index)
+	}
+
+	if log.GetDroppedCount() != overflowDropped {
+		test.Errorf("GetDroppedCount() = %d; want %d", log.GetDroppedCount(), overflowDropped)
+	}
+
+	release()
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+
+	handler, _ := log.GetHandler("observer")
+	observer := handler.(*logger.Observer)
+
+	if observer.FilterLevel(logger.WarningLevel).FilterMessage("dropped 10 log records").Len() != 1 {
+		test.Error("number of dropped log records is not reported")
+	}
+
+	if want := logger.DefaultQueueLength + 2; observer.Len() != want {
+		test.Errorf("observer.Len() = %d; want %d", observer.Len(), want)
+	}
+
+	return observer
+}
+
+func TestOverflowPolicyDropNewest(test *testing.T) {
+	observer := testOverflowPolicy(test, logger.DropNewest)
+
+	if observer.FilterMessage("Message 0").Len() != 1 {
+		test.Error("the oldest log record is dropped")
+	}
+
+	if observer.FilterMessage("Message 4105").Len() != 0 {
+		test.Error("the newest log record is not dropped")
+	}
+}
+
+func TestOverflowPolicyDropOldest(test *testing.T) {
+	observer := testOverflowPolicy(test, logger.DropOldest)
+
+	if observer.FilterMessage("Message 0").Len() != 0 {
+		test.Error("the oldest log record is not dropped")
+	}
+
+	if observer.FilterMessage("Message 4105").Len() != 1 {
+		test.Error("the newest log record is dropped")
+	}
+}
+
+func TestOverflowPolicyStats(test *testing.T) {
+	log, release := newBlockedLogger(logger.New().SetOverflowPolicy(logger.DropNewest))
+
+	for index := 0; index <= logger.DefaultQueueLength; index++ {
+		log.Info(testMessage)
+	}
+
+	if stats := log.GetWorker().Stats(); (stats.Dropped != 1) || (stats.QueueLength != logger.DefaultQueueLength) {
+		test.Errorf("Stats() = %+v; want 1 dropped and full queue", stats)
+	}
+
+	release()
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestSetEnqueueTimeout(test *testing.T) {
+	const timeout = 20 * time.Millisecond
+
+	log, release := newBlockedLogger(logger.New().SetEnqueueTimeout(timeout))
+
+	if log.GetEnqueueTimeout() != timeout {
+		test.Errorf("GetEnqueueTimeout() = %v; want %v", log.GetEnqueueTimeout(), timeout)
+	}
+
+	for index := 0; index < logger.DefaultQueueLength; index++ {
+		log.Info(testMessage)
+	}
+
+	start := time.Now()
+
+	log.Info("dropped")
+
+	if elapsed := time.Since(start); elapsed < timeout {
+		test.Errorf("Info() returns after %v; want at least %v", elapsed, timeout)
+	}
+
+	if log.GetDroppedCount() != 1 {
+		test.Errorf("GetDroppedCount() = %d; want 1", log.GetDroppedCount())
+	}
+
+	release()
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+
+	handler, _ := log.GetHandler("observer")
+
+	if handler.(*logger.Observer).FilterMessage("dropped").Len() != 0 {
+		test.Error("log record is not dropped after enqueue timeout")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// PanicError defines error used by the Panic method with formatted log
+// message, log level and source location of the Panic method caller.
+type PanicError struct {
+	Message string
+	Level   Level
+	File    Source
+}
+
+// NewPanicError creates new PanicError object from provided log record.
+func NewPanicError(record *Record) *PanicError {
+	message, err := record.GetMessage()
+
+	if err != nil {
+		message = record.Message
+	}
+
+	file := record.File
+	file.Name = filepath.Base(file.Path)
+	file.Function = filepath.Base(file.Function)
+
+	return &PanicError{
+		Message: message,
+		Level:   record.Level,
+		File:    file,
+	}
+}
+
+// Error returns formatted error string with message, file name, file line
+// number and function name.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("%s:%d:%s(): %s",
+		p.File.Name,
+		p.File.Line,
+		p.File.Function,
+		p.Message,
+	)
+}
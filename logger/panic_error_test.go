@@ -0,0 +1,63 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"errors"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestLoggerPanicError(test *testing.T) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer)
+
+	defer func() {
+		err, ok := recover().(error)
+
+		if !ok {
+			test.Fatal("recover() doesn't return an error")
+		}
+
+		var panicError *logger.PanicError
+
+		if !errors.As(err, &panicError) {
+			test.Fatal("errors.As() = false; want true")
+		}
+
+		if panicError.Message != "bad state 5" {
+			test.Error("Message =", panicError.Message, "; want bad state 5")
+		}
+
+		if panicError.Level.Value != logger.PanicLevel {
+			test.Errorf("Level.Value = %d; want %d", panicError.Level.Value, logger.PanicLevel)
+		}
+
+		want := "panic_error_test.go:62:logger_test.TestLoggerPanicError(): bad state 5"
+
+		if err.Error() != want {
+			test.Error("Error() =", err.Error(), "; want", want)
+		}
+
+		if buffer.String() != "bad state 5\n" {
+			test.Error("buffer.String() =", buffer.String(), "; want bad state 5")
+		}
+	}()
+
+	log.Panic("bad state {p}", 5)
+}
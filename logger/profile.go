@@ -0,0 +1,126 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var gProfiling int32 // nolint:gochecknoglobals
+
+// Profile defines time spent by log handler on formatting and writing of log
+// records. Durations are sums over all profiled log records and counts are
+// numbers of profiled format and write operations.
+type Profile struct {
+	FormatDuration time.Duration
+	FormatCount    uint64
+	WriteDuration  time.Duration
+	WriteCount     uint64
+}
+
+// Profiler defines log handler that profiles formatting and writing of log
+// records.
+type Profiler interface {
+	Profile() Profile
+}
+
+// profileCounters defines profile counters updated atomically.
+type profileCounters struct {
+	formatDuration int64
+	formatCount    uint64
+	writeDuration  int64
+	writeCount     uint64
+}
+
+// EnableProfiling enables or disables profiling of formatting and writing of
+// log records by log handlers. It is disabled on default and disabled
+// profiling has no overhead beside a single atomic load per emitted log
+// record. Collected profiles are returned by the Profile methods of log
+// handlers and by the Logger Profile method.
+func EnableProfiling(enabled bool) {
+	var value int32
+
+	if enabled {
+		value = 1
+	}
+
+	atomic.StoreInt32(&gProfiling, value)
+}
+
+// IsProfiling returns if profiling of log handlers is enabled.
+func IsProfiling() bool {
+	return atomic.LoadInt32(&gProfiling) != 0
+}
+
+// start returns current time if profiling is enabled or zero time otherwise.
+func (c *profileCounters) start() time.Time {
+	if !IsProfiling() {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// addFormat adds duration of format operation started at provided time. It
+// returns current time used as start of the next operation.
+func (c *profileCounters) addFormat(start time.Time) time.Time {
+	if start.IsZero() {
+		return start
+	}
+
+	now := time.Now()
+
+	atomic.AddInt64(&c.formatDuration, int64(now.Sub(start)))
+	atomic.AddUint64(&c.formatCount, 1)
+
+	return now
+}
+
+// addWrite adds duration of write operation started at provided time.
+func (c *profileCounters) addWrite(start time.Time) {
+	if !start.IsZero() {
+		atomic.AddInt64(&c.writeDuration, int64(time.Since(start)))
+		atomic.AddUint64(&c.writeCount, 1)
+	}
+}
+
+// get returns current profile.
+func (c *profileCounters) get() Profile {
+	return Profile{
+		FormatDuration: time.Duration(atomic.LoadInt64(&c.formatDuration)),
+		FormatCount:    atomic.LoadUint64(&c.formatCount),
+		WriteDuration:  time.Duration(atomic.LoadInt64(&c.writeDuration)),
+		WriteCount:     atomic.LoadUint64(&c.writeCount),
+	}
+}
+
+// Profile returns profiles of all added log handlers that implement
+// the Profiler interface, keyed by names used to add them. It allows to find
+// log handlers that are bottlenecks of logger worker thread.
+func (l *Logger) Profile() map[string]Profile {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	profiles := make(map[string]Profile)
+
+	for name, handler := range l.getHandlers() {
+		if profiler, ok := handler.(Profiler); ok {
+			profiles[name] = profiler.Profile()
+		}
+	}
+
+	return profiles
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestProfile(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	log := logger.New().SetSynchronous(true).SetHandlers(logger.Handlers{
+		"buffer":   buffer,
+		"observer": logger.NewObserver(),
+	})
+
+	log.Info(testMessage)
+
+	if profile := buffer.Profile(); profile != (logger.Profile{}) {
+		test.Errorf("Profile() = %+v; want zero profile when profiling is disabled", profile)
+	}
+
+	logger.EnableProfiling(true)
+	defer logger.EnableProfiling(false)
+
+	if !logger.IsProfiling() {
+		test.Error("IsProfiling() = false; want true")
+	}
+
+	log.Info(testMessage)
+	log.Info(testMessage)
+
+	profiles := log.Profile()
+
+	if _, ok := profiles["observer"]; ok {
+		test.Error("Profile() contains log handler that is not a profiler")
+	}
+
+	profile, ok := profiles["buffer"]
+
+	if !ok {
+		test.Fatalf("Profile() = %v; want buffer profile", profiles)
+	}
+
+	if (profile.FormatCount != 2) || (profile.WriteCount != 2) {
+		test.Errorf("Profile() = %+v; want 2 format and write operations", profile)
+	}
+}
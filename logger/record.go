@@ -15,25 +15,262 @@
 package logger
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"gitlab.com/tymonx/go-logger/logger/logpb"
+	"gitlab.com/tymonx/go-logger/logger/msgpack"
 )
 
+// JSONTimeFormat defines format of log record time in the JSON output.
+type JSONTimeFormat int32
+
+// These constants define formats of log record time in the JSON output.
+const (
+	// JSONTimeRFC3339Nano formats log record time as RFC3339 string with
+	// nanoseconds.
+	JSONTimeRFC3339Nano JSONTimeFormat = iota
+
+	// JSONTimeEpochMillis formats log record time as number of milliseconds
+	// elapsed since the Unix epoch.
+	JSONTimeEpochMillis
+)
+
+var gJSONTimeFormat int32 // nolint:gochecknoglobals
+
+var gRecordPool = sync.Pool{ // nolint:gochecknoglobals
+	New: func() interface{} {
+		return new(Record)
+	},
+}
+
 // Record defines log record fields created by Logger and it is used by
 // Formatter to format log message based on these fields.
+//
+// ID is generated once by logger worker thread before running hooks and
+// emitting log record to log handlers and it doesn't change after that. It is
+// stable across retries of log handlers and it can be used as an idempotency
+// key to deduplicate log records shipped more than once.
+//
+// Context is set by context-aware log methods like InfoContext and it is
+// passed untouched to log handlers. Log records are emitted asynchronously by
+// logger worker thread and context may be already canceled at emit time. Log
+// handlers should use it only for request-scoped values and they should not
+// rely on its deadline.
 type Record struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"`
-	Name      string    `json:"name"`
-	Time      time.Time `json:"-"`
-	Level     Level     `json:"level"`
-	Address   string    `json:"address"`
-	Hostname  string    `json:"hostname"`
-	Message   string    `json:"message"`
-	File      Source    `json:"file"`
-	Arguments Arguments `json:"arguments"`
-	Timestamp Timestamp `json:"timestamp"`
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Name      string            `json:"name"`
+	Time      time.Time         `json:"-"`
+	Level     Level             `json:"level"`
+	Address   string            `json:"address"`
+	Hostname  string            `json:"hostname"`
+	Message   string            `json:"message"`
+	File      Source            `json:"file"`
+	Arguments Arguments         `json:"arguments"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Fields    Named             `json:"fields,omitempty"`
+	Duration  time.Duration     `json:"duration,omitempty"`
+	Context   context.Context   `json:"-"`
+	Timestamp Timestamp         `json:"timestamp"`
 	logger    *Logger
+	lazyID    *lazyID
+	internal  bool
+	pooled    bool
+}
+
+// newPooledRecord returns reset log record from the log records pool.
+func newPooledRecord() *Record {
+	record := gRecordPool.Get().(*Record) // nolint:forcetypeassert
+	record.pooled = true
+
+	return record
+}
+
+// release resets log record and it returns it to the log records pool if it
+// was taken from the pool.
+func (r *Record) release() {
+	if r.pooled {
+		*r = Record{}
+		gRecordPool.Put(r)
+	}
+}
+
+// Retain prevents returning of log record to the log records pool after
+// emitting it to all log handlers. Hooks and log handlers that keep reference
+// to provided log record after returning must call it when record pooling is
+// enabled. It must be called from the hook or log handler Emit or EmitBatch
+// method.
+func (r *Record) Retain() *Record {
+	r.pooled = false
+	return r
+}
+
+// Clone returns a copy of log record that is never returned to the log records
+// pool. Level, source, timestamp and other fields are copied by value and log
+// arguments are copied to a new slice but they are not copied deeply.
+func (r *Record) Clone() *Record {
+	clone := *r
+	clone.pooled = false
+
+	if r.Arguments != nil {
+		clone.Arguments = append(Arguments(nil), r.Arguments...)
+	}
+
+	if r.Labels != nil {
+		clone.Labels = make(map[string]string, len(r.Labels))
+
+		for key, value := range r.Labels {
+			clone.Labels[key] = value
+		}
+	}
+
+	if r.Fields != nil {
+		clone.Fields = make(Named, len(r.Fields))
+
+		for key, value := range r.Fields {
+			clone.Fields[key] = value
+		}
+	}
+
+	return &clone
+}
+
+// getRecordFields returns default fields without keys overridden by named
+// values from provided log arguments.
+func getRecordFields(defaults Named, arguments Arguments) Named {
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	for _, argument := range arguments {
+		if fields, ok := getNamedFields(argument); ok {
+			for _, field := range fields {
+				delete(defaults, field.Key)
+			}
+		}
+	}
+
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	return defaults
+}
+
+// recordJSON defines log record without JSON methods.
+type recordJSON Record
+
+// SetJSONTimeFormat sets format of log record time in the JSON output. On
+// default it is JSONTimeRFC3339Nano.
+func SetJSONTimeFormat(format JSONTimeFormat) {
+	atomic.StoreInt32(&gJSONTimeFormat, int32(format))
+}
+
+// GetJSONTimeFormat returns format of log record time in the JSON output.
+func GetJSONTimeFormat() JSONTimeFormat {
+	return JSONTimeFormat(atomic.LoadInt32(&gJSONTimeFormat))
+}
+
+// MarshalJSON packs log record to JSON with log record time as the "time"
+// field formatted according to the SetJSONTimeFormat function. Zero time is
+// omitted.
+func (r *Record) MarshalJSON() ([]byte, error) {
+	return r.jsonFields().MarshalJSON()
+}
+
+// jsonFields returns log record fields packed to JSON by the MarshalJSON
+// method in their output order. Empty labels, default fields, duration, error
+// code and zero time are omitted.
+func (r *Record) jsonFields() Fields {
+	fields := Fields{
+		{Key: "id", Value: r.GetID()},
+		{Key: "type", Value: r.Type},
+		{Key: "name", Value: r.Name},
+		{Key: "level", Value: r.Level},
+		{Key: "address", Value: r.Address},
+		{Key: "hostname", Value: r.Hostname},
+		{Key: "message", Value: r.Message},
+		{Key: "file", Value: r.File},
+		{Key: "arguments", Value: r.Arguments},
+	}
+
+	if len(r.Labels) != 0 {
+		fields = append(fields, Field{Key: "labels", Value: r.Labels})
+	}
+
+	if len(r.Fields) != 0 {
+		fields = append(fields, Field{Key: "fields", Value: r.Fields})
+	}
+
+	if r.Duration != 0 {
+		fields = append(fields, Field{Key: "duration", Value: r.Duration})
+	}
+
+	if code, ok := r.GetErrorCode(); ok {
+		fields = append(fields, Field{Key: "error_code", Value: code})
+	}
+
+	fields = append(fields, Field{Key: "timestamp", Value: r.Timestamp})
+
+	if !r.Time.IsZero() {
+		if GetJSONTimeFormat() == JSONTimeEpochMillis {
+			fields = append(fields, Field{Key: "time", Value: r.Time.UnixMilli()})
+		} else {
+			fields = append(fields, Field{Key: "time", Value: r.Time.Format(time.RFC3339Nano)})
+		}
+	}
+
+	return fields
+}
+
+// UnmarshalJSON unpacks log record from JSON. Log record time is restored from
+// the "time" field in both formats supported by the SetJSONTimeFormat function
+// or from the created timestamp if the "time" field is missing.
+func (r *Record) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*recordJSON
+		Time json.RawMessage `json:"time"`
+	}{
+		recordJSON: (*recordJSON)(r),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	aux.Time = bytes.TrimSpace(aux.Time)
+
+	switch {
+	case (len(aux.Time) == 0) || bytes.Equal(aux.Time, []byte("null")):
+		r.Time = time.Time{}
+
+		if r.Timestamp.Created != "" {
+			created, err := time.Parse(time.RFC3339, r.Timestamp.Created)
+
+			if err != nil {
+				return err
+			}
+
+			r.Time = created
+		}
+	case aux.Time[0] == '"':
+		return json.Unmarshal(aux.Time, &r.Time)
+	default:
+		var millis int64
+
+		if err := json.Unmarshal(aux.Time, &millis); err != nil {
+			return err
+		}
+
+		r.Time = time.UnixMilli(millis)
+	}
+
+	return nil
 }
 
 // ToJSON packs data to JSON.
@@ -46,6 +283,103 @@ func (r *Record) FromJSON(data []byte) error {
 	return json.Unmarshal(data, r)
 }
 
+// ToMsgPack packs data to MessagePack.
+func (r *Record) ToMsgPack() ([]byte, error) {
+	if (r.ID == "") && (r.lazyID != nil) {
+		record := *r
+		record.ID = r.GetID()
+
+		return msgpack.Marshal(&record)
+	}
+
+	return msgpack.Marshal(r)
+}
+
+// FromMsgPack unpacks data from MessagePack.
+func (r *Record) FromMsgPack(data []byte) error {
+	return msgpack.Unmarshal(data, r)
+}
+
+// ToProto packs data to protocol buffers message defined by the logpb
+// package. Message is formatted with log record arguments. Default fields and
+// named values from log arguments are packed as values of protocol buffers
+// fields map.
+func (r *Record) ToProto() ([]byte, error) {
+	message, err := r.GetMessage()
+
+	if err != nil {
+		return nil, err
+	}
+
+	record := &logpb.Record{
+		ID:       r.GetID(),
+		Type:     r.Type,
+		Name:     r.Name,
+		Level:    logpb.Level{Value: int64(r.Level.Value), Name: r.Level.Name},
+		Address:  r.Address,
+		Hostname: r.Hostname,
+		Message:  message,
+		File: logpb.Source{
+			Line:     int64(r.File.Line),
+			Name:     r.File.Name,
+			Path:     r.File.Path,
+			Function: r.File.Function,
+		},
+		Labels: r.Labels,
+	}
+
+	if !r.Time.IsZero() {
+		record.TimeUnixNano = r.Time.UnixNano()
+	}
+
+	fields := make(map[string]logpb.Value, len(r.Fields))
+
+	for key, value := range r.Fields {
+		fields[key] = logpb.ValueOf(value)
+	}
+
+	for _, argument := range r.Arguments {
+		if named, ok := getNamedFields(argument); ok {
+			for _, field := range named {
+				fields[field.Key] = logpb.ValueOf(field.Value)
+			}
+		}
+	}
+
+	if len(fields) != 0 {
+		record.Fields = fields
+	}
+
+	return record.Marshal()
+}
+
+// GetID returns log record ID. If the ID field is empty and logger uses the
+// IDGenerationLazy ID generation, ID is generated on the first call and it is
+// memoized. It is safe to call it concurrently from multiple log handlers
+// formatting the same log record. Log handlers and hooks should use it instead
+// of reading the ID field directly.
+func (r *Record) GetID() string {
+	if (r.ID == "") && (r.lazyID != nil) {
+		return r.lazyID.get(r)
+	}
+
+	return r.ID
+}
+
+// GetErrorCode returns error code of the first error argument with error code
+// like the ErrorCodeOf function.
+func (r *Record) GetErrorCode() (int, bool) {
+	for _, argument := range r.Arguments {
+		if err, ok := argument.(error); ok {
+			if code, ok := ErrorCodeOf(err); ok {
+				return code, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
 // GetMessage returns formatted message.
 func (r *Record) GetMessage() (string, error) {
 	message, err := NewFormatter().FormatMessage(r)
@@ -0,0 +1,239 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestRecordJSONTime(test *testing.T) {
+	defer logger.SetJSONTimeFormat(logger.JSONTimeRFC3339Nano)
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	tests := []struct {
+		format logger.JSONTimeFormat
+		field  string
+		want   time.Time
+	}{
+		{logger.JSONTimeRFC3339Nano, `"time":"2020-01-02T03:04:05.123456789Z"`, created},
+		{logger.JSONTimeEpochMillis, `"time":1577934245123`, created.Truncate(time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		logger.SetJSONTimeFormat(tt.format)
+
+		if logger.GetJSONTimeFormat() != tt.format {
+			test.Errorf("GetJSONTimeFormat() = %d; want %d", logger.GetJSONTimeFormat(), tt.format)
+		}
+
+		record := &logger.Record{
+			Time:    created,
+			Message: testMessage,
+			Timestamp: logger.Timestamp{
+				Created: created.Format(time.RFC3339),
+			},
+		}
+
+		data, err := record.ToJSON()
+
+		if err != nil {
+			test.Fatal("ToJSON() returns an unexpected error", err)
+		}
+
+		if !strings.Contains(string(data), tt.field) || !strings.Contains(string(data), `"created":"2020-01-02T03:04:05Z"`) {
+			test.Errorf("ToJSON() = %s; want %s and created timestamp", data, tt.field)
+		}
+
+		var decoded logger.Record
+
+		if err := decoded.FromJSON(data); err != nil {
+			test.Fatal("FromJSON() returns an unexpected error", err)
+		}
+
+		if !decoded.Time.Equal(tt.want) || (decoded.Message != testMessage) {
+			test.Errorf("FromJSON() = %+v; want time %v", decoded, tt.want)
+		}
+	}
+}
+
+func TestRecordJSONZeroTime(test *testing.T) {
+	data, err := new(logger.Record).ToJSON()
+
+	if err != nil {
+		test.Fatal("ToJSON() returns an unexpected error", err)
+	}
+
+	if strings.Contains(string(data), `"time"`) {
+		test.Errorf("ToJSON() = %s; want no time field", data)
+	}
+
+	record := logger.Record{Time: time.Now()}
+
+	if err := record.FromJSON(data); err != nil {
+		test.Fatal("FromJSON() returns an unexpected error", err)
+	}
+
+	if !record.Time.IsZero() {
+		test.Errorf("FromJSON() time = %v; want zero", record.Time)
+	}
+}
+
+func TestRecordJSONRoundTrip(test *testing.T) {
+	record := &logger.Record{
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 678000000, time.Local),
+		Message: "Message {p} {p1} {user} {.Name} {p2}",
+		Level: logger.Level{
+			Value: logger.WarningLevel,
+			Name:  logger.WarningName,
+		},
+		File: logger.Source{
+			Name:     "record_test.go",
+			Function: "logger_test.TestRecordJSONRoundTrip",
+			Line:     10,
+		},
+		Arguments: logger.Arguments{
+			1,
+			"two",
+			3.5,
+			struct{ Name string }{"object"},
+			logger.Named{"user": "bob", "id": 7},
+			true,
+			nil,
+			testError,
+		},
+	}
+
+	record.Timestamp.Created = record.Time.Format(time.RFC3339)
+
+	data, err := record.ToJSON()
+
+	if err != nil {
+		test.Fatal("ToJSON() returns an unexpected error", err)
+	}
+
+	decoded := new(logger.Record)
+
+	if err := decoded.FromJSON(data); err != nil {
+		test.Fatal("FromJSON() returns an unexpected error", err)
+	}
+
+	formatter := logger.NewFormatter()
+
+	want, err := formatter.Format(record)
+
+	if err != nil {
+		test.Fatal("Format() returns an unexpected error", err)
+	}
+
+	got, err := formatter.Format(decoded)
+
+	if err != nil {
+		test.Fatal("Format() returns an unexpected error", err)
+	}
+
+	if got != want {
+		test.Errorf("Format() = %q; want %q", got, want)
+	}
+
+	message, err := formatter.FormatMessage(decoded)
+
+	if err != nil {
+		test.Fatal("FormatMessage() returns an unexpected error", err)
+	}
+
+	if want := "Message 1 two bob object 3.5 id=7 true <nil> " + testError.Error(); message != want {
+		test.Errorf("FormatMessage() = %q; want %q", message, want)
+	}
+}
+
+func TestRecordJSONLabels(test *testing.T) {
+	record := &logger.Record{
+		Message: "Message",
+		Labels:  map[string]string{"env": "prod"},
+	}
+
+	data, err := record.ToJSON()
+
+	if err != nil {
+		test.Fatal("ToJSON() returns an unexpected error", err)
+	}
+
+	if !strings.Contains(string(data), `"labels":{"env":"prod"}`) {
+		test.Errorf("ToJSON() = %s; want labels", data)
+	}
+
+	decoded := new(logger.Record)
+
+	if err := decoded.FromJSON(data); err != nil {
+		test.Fatal("FromJSON() returns an unexpected error", err)
+	}
+
+	if got := decoded.Labels["env"]; got != "prod" {
+		test.Errorf("Labels[\"env\"] = %q; want %q", got, "prod")
+	}
+}
+
+func TestRecordErrorCode(test *testing.T) {
+	coded := fmt.Errorf("wrapped: %w", logger.NewRuntimeErrorCode(42, "first"))
+
+	record := &logger.Record{
+		Message:   "{p}",
+		Arguments: logger.Arguments{testError, logger.NewRuntimeError("plain", coded), logger.NewRuntimeErrorCode(7, "second")},
+	}
+
+	if code, ok := record.GetErrorCode(); !ok || (code != 42) {
+		test.Errorf("GetErrorCode() = %d, %t; want 42, true", code, ok)
+	}
+
+	data, err := record.ToJSON()
+
+	if err != nil {
+		test.Fatal("ToJSON() returns an unexpected error", err)
+	}
+
+	if !strings.Contains(string(data), `"error_code":42`) {
+		test.Errorf("ToJSON() = %s; want error_code 42", data)
+	}
+
+	if message, err := logger.NewFormatter().SetFormat("{errorCode}").Format(record); err != nil {
+		test.Error("Format() returns an unexpected error", err)
+	} else if message != "42" {
+		test.Errorf("Format() = %q; want %q", message, "42")
+	}
+
+	record.Arguments = logger.Arguments{logger.NewRuntimeError("plain", testError)}
+
+	if code, ok := record.GetErrorCode(); ok {
+		test.Errorf("GetErrorCode() = %d, %t; want 0, false", code, ok)
+	}
+
+	if data, err = record.ToJSON(); err != nil {
+		test.Fatal("ToJSON() returns an unexpected error", err)
+	} else if strings.Contains(string(data), "error_code") {
+		test.Errorf("ToJSON() = %s; want no error_code", data)
+	}
+
+	if message, err := logger.NewFormatter().SetFormat("{errorCode}").Format(record); err != nil {
+		test.Error("Format() returns an unexpected error", err)
+	} else if message != "" {
+		test.Errorf("Format() = %q; want empty", message)
+	}
+}
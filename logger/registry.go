@@ -0,0 +1,80 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// LoggerNameSeparator defines separator used between names of loggers in
+// the hierarchy of named loggers.
+const LoggerNameSeparator = "."
+
+var gLoggersMutex sync.Mutex            // nolint:gochecknoglobals
+var gLoggers = make(map[string]*Logger) // nolint:gochecknoglobals
+
+// GetLogger returns named logger from the hierarchy of named loggers. Logger
+// name is a dotted path like for example "app.db.pool" and the "app.db" logger
+// is a parent of it. Named loggers are created on the first call and the same
+// logger instance is returned for the same name. The global logger returned by
+// the Get function is the root of the hierarchy and it is returned for an
+// empty name.
+//
+// Named logger without own log handlers shares log handlers with the nearest
+// parent logger. Configuring log handlers of the "app.db" logger affects also
+// the "app.db.pool" logger unless it sets own log handlers using for example
+// the SetHandlers or AddHandler methods.
+func GetLogger(name string) *Logger {
+	name = strings.Trim(name, LoggerNameSeparator)
+
+	if name == "" {
+		return Get()
+	}
+
+	gLoggersMutex.Lock()
+	defer gLoggersMutex.Unlock()
+
+	return getLogger(name)
+}
+
+// getLogger returns named logger and it creates all missing parent loggers.
+// Caller must hold the registry lock.
+func getLogger(name string) *Logger {
+	if logger, ok := gLoggers[name]; ok {
+		return logger
+	}
+
+	var parent *Logger
+
+	if index := strings.LastIndex(name, LoggerNameSeparator); index >= 0 {
+		parent = getLogger(name[:index])
+	}
+
+	logger := &Logger{
+		name:        name,
+		parent:      parent,
+		inherit:     true,
+		errorCode:   DefaultErrorCode,
+		flushLevel:  DisabledFlushLevel,
+		exitFunc:    os.Exit,
+		idGenerator: NewUUID4(),
+	}
+
+	gLoggers[name] = logger
+
+	return logger
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestGetLoggerCached(test *testing.T) {
+	const name = "registry.cached.child"
+
+	loggers := make([]*logger.Logger, 16)
+
+	var wg sync.WaitGroup
+
+	for index := range loggers {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+			loggers[index] = logger.GetLogger(name)
+		}(index)
+	}
+
+	wg.Wait()
+
+	for _, log := range loggers {
+		if log != loggers[0] {
+			test.Fatal("GetLogger() returns different loggers for the same name")
+		}
+	}
+
+	if got := loggers[0].GetName(); got != name {
+		test.Errorf("GetName() = %q; want %q", got, name)
+	}
+}
+
+func TestGetLoggerHierarchy(test *testing.T) {
+	parentBuffer := logger.NewBuffer()
+	childBuffer := logger.NewBuffer()
+
+	parentBuffer.GetFormatter().SetFormat("{name}:{message}")
+	childBuffer.GetFormatter().SetFormat("{name}:{message}")
+
+	parent := logger.GetLogger("registry.hierarchy")
+	child := logger.GetLogger("registry.hierarchy.child")
+
+	parent.SetHandler("buffer", parentBuffer)
+
+	child.Info("inherited")
+	child.Flush()
+
+	if want := "registry.hierarchy.child:inherited\n"; parentBuffer.String() != want {
+		test.Errorf("parent buffer = %q; want %q", parentBuffer.String(), want)
+	}
+
+	parent.SetLevel(logger.ErrorLevel)
+	child.Info("filtered")
+	child.Flush()
+
+	if strings.Contains(parentBuffer.String(), "filtered") {
+		test.Error("parent level is not applied to child logger")
+	}
+
+	child.SetHandler("buffer", childBuffer)
+	child.Info("overridden")
+	child.Flush()
+
+	if want := "registry.hierarchy.child:overridden\n"; childBuffer.String() != want {
+		test.Errorf("child buffer = %q; want %q", childBuffer.String(), want)
+	}
+
+	if strings.Contains(parentBuffer.String(), "overridden") {
+		test.Error("child logger with own handlers emits via parent handlers")
+	}
+
+	child.ResetHandlers()
+
+	if _, err := child.GetHandler("buffer"); err != nil {
+		test.Error("GetHandler() returns an unexpected error", err)
+	}
+
+	if handler, _ := child.GetHandler("buffer"); handler != parentBuffer {
+		test.Error("child logger doesn't inherit parent handlers after reset")
+	}
+}
+
+func TestGetLoggerLabels(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{labels}:{message}")
+
+	parent := logger.GetLogger("registry.labels")
+	child := logger.GetLogger("registry.labels.child")
+
+	parent.SetHandler("buffer", buffer)
+	parent.SetLabels(map[string]string{"env": "prod", "region": "eu1"})
+	child.SetLabels(map[string]string{"region": "us1", "zone": "a"})
+
+	child.Info("labeled")
+	child.Flush()
+
+	if want := "env=prod,region=us1,zone=a:labeled\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	labels := child.GetLabels()
+	labels["env"] = "dev"
+
+	if got := parent.GetLabels()["env"]; got != "prod" {
+		test.Errorf("GetLabels()[\"env\"] = %q; want %q", got, "prod")
+	}
+
+	child.SetLabels(nil)
+
+	if got := child.GetLabels()["region"]; got != "eu1" {
+		test.Errorf("GetLabels()[\"region\"] = %q; want %q", got, "eu1")
+	}
+}
+
+func TestGetLoggerInheritedHandlersConcurrent(test *testing.T) {
+	parent := logger.GetLogger("registry.concurrent").SetHandler("observer", logger.NewObserver())
+	child := logger.GetLogger("registry.concurrent.child").SetSynchronous(true)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for index := 0; index < 100; index++ {
+			parent.AddHandler("added", logger.NewObserver())
+			parent.RemoveHandler("added")
+		}
+	}()
+
+	for index := 0; index < 100; index++ {
+		child.Info(testMessage)
+	}
+
+	wg.Wait()
+
+	if observer, _ := parent.GetHandler("observer"); observer.(*logger.Observer).Len() != 100 { // nolint:forcetypeassert
+		test.Error("child logger doesn't emit log records to inherited log handlers")
+	}
+}
+
+func TestGetLoggerInheritedHandlersSetLevel(test *testing.T) {
+	var errs []error
+
+	child := logger.GetLogger("registry.level.child").SetInternalErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	child.SetLevel(logger.ErrorLevel).SetLevelRange(logger.InfoLevel, logger.ErrorLevel)
+
+	if len(errs) != 2 {
+		test.Fatalf("len(errs) = %d; want 2", len(errs))
+	}
+
+	if !strings.Contains(errs[0].Error(), "cannot call SetLevel on inherited log handlers") {
+		test.Error("Error() =", errs[0].Error(), "; want inherited log handlers error")
+	}
+}
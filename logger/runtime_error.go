@@ -15,24 +15,63 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"sync/atomic"
 )
 
 // These constants are used for the RuntimeError.
 const (
-	RuntimeErrorSkipCall = 1
+	RuntimeErrorSkipCall          = 1
+	DefaultRuntimeErrorStackDepth = 32
 )
 
+var gRuntimeErrorStackDepth int32 = DefaultRuntimeErrorStackDepth // nolint:gochecknoglobals
+
+// ErrorCoder defines interface for errors with error code. Errors that may be
+// created without error code, like RuntimeError, implement also the HasCode
+// method and they have error code only when it returns true. Every
+// RuntimeError matches ErrorCoder as the errors.As target, use the ErrorCodeOf
+// function to find the first error with error code.
+type ErrorCoder interface {
+	error
+
+	Code() int
+}
+
 // RuntimeError defines runtime error with returned error message, file name,
-// file line number and function name.
+// file line number, function name and error code.
 type RuntimeError struct {
+	code      int
+	hasCode   bool
 	line      int
 	file      string
 	message   string
 	function  string
 	arguments []interface{}
+	stack     []uintptr
+}
+
+// SetRuntimeErrorStackDepth sets maximum number of stack frames captured by
+// created RuntimeError objects. Captured stack is printed with the "%+v"
+// format verb. Zero or negative depth disables capturing of stack. On default
+// it is DefaultRuntimeErrorStackDepth.
+func SetRuntimeErrorStackDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+
+	atomic.StoreInt32(&gRuntimeErrorStackDepth, int32(depth))
+}
+
+// GetRuntimeErrorStackDepth returns maximum number of stack frames captured by
+// created RuntimeError objects.
+func GetRuntimeErrorStackDepth() int {
+	return int(atomic.LoadInt32(&gRuntimeErrorStackDepth))
 }
 
 // NewRuntimeError creates new RuntimeError object.
@@ -40,45 +79,233 @@ func NewRuntimeError(message string, arguments ...interface{}) *RuntimeError {
 	return NewRuntimeErrorBase(RuntimeErrorSkipCall, message, arguments...)
 }
 
-// NewRuntimeErrorBase creates new RuntimeError object using custom skip call value.
+// NewRuntimeErrorCode creates new RuntimeError object with error code. Error
+// code is surfaced in log records when it is passed as a log argument.
+func NewRuntimeErrorCode(code int, message string, arguments ...interface{}) *RuntimeError {
+	err := newRuntimeError(RuntimeErrorSkipCall, code, message, arguments)
+	err.hasCode = true
+
+	return err
+}
+
+// NewRuntimeErrorBase creates new RuntimeError object using custom skip call
+// value. Error code is set to DefaultErrorCode.
 func NewRuntimeErrorBase(skipCall int, message string, arguments ...interface{}) *RuntimeError {
+	return newRuntimeError(skipCall+1, DefaultErrorCode, message, arguments)
+}
+
+// newRuntimeError creates new RuntimeError object using custom skip call
+// value and error code.
+func newRuntimeError(skipCall, code int, message string, arguments []interface{}) *RuntimeError {
 	pc, path, line, _ := runtime.Caller(skipCall + 1)
 
+	var stack []uintptr
+
+	if depth := GetRuntimeErrorStackDepth(); depth > 0 {
+		stack = make([]uintptr, depth)
+		stack = stack[:runtime.Callers(skipCall+2, stack)]
+	}
+
 	return &RuntimeError{
+		code:      code,
 		line:      line,
 		file:      filepath.Base(path),
 		message:   message,
 		function:  filepath.Base(runtime.FuncForPC(pc).Name()),
 		arguments: arguments,
+		stack:     stack,
 	}
 }
 
 // Error returns formatted error string with message, file name, file line
 // number and function name.
 func (r *RuntimeError) Error() string {
-	var formatted string
-
-	var err error
+	return fmt.Sprintf("%s:%d:%s(): %s",
+		r.file,
+		r.line,
+		r.function,
+		r.Message(),
+	)
+}
 
+// Message returns error message formatted with arguments.
+func (r *RuntimeError) Message() string {
 	record := &Record{
 		Message:   r.message,
 		Arguments: r.arguments,
 	}
 
-	if formatted, err = NewFormatter().FormatMessage(record); err != nil {
-		formatted = r.message
+	formatted, err := NewFormatter().FormatMessage(record)
+
+	if err != nil {
+		return r.message
 	}
 
-	return fmt.Sprintf("%s:%d:%s(): %s",
-		r.file,
-		r.line,
-		r.function,
-		formatted,
-	)
+	return formatted
+}
+
+// File returns file name of source location where error was created.
+func (r *RuntimeError) File() string {
+	return r.file
+}
+
+// Line returns line number of source location where error was created.
+func (r *RuntimeError) Line() int {
+	return r.line
+}
+
+// Function returns function name of source location where error was created.
+func (r *RuntimeError) Function() string {
+	return r.function
+}
+
+// MarshalJSON packs error to JSON object with formatted message, source
+// location, error code and wrapped errors from arguments. Wrapped errors
+// implementing the json.Marshaler interface, like other RuntimeError objects,
+// are packed by it, other wrapped errors are packed as error strings.
+func (r *RuntimeError) MarshalJSON() ([]byte, error) {
+	fields := Fields{
+		{Key: "message", Value: r.Message()},
+		{Key: "file", Value: r.file},
+		{Key: "line", Value: r.line},
+		{Key: "function", Value: r.function},
+		{Key: "code", Value: r.code},
+	}
+
+	if errs := r.Unwrap(); len(errs) != 0 {
+		wrapped := make([]interface{}, 0, len(errs))
+
+		for _, err := range errs {
+			if marshaler, ok := err.(json.Marshaler); ok {
+				wrapped = append(wrapped, marshaler)
+			} else {
+				wrapped = append(wrapped, err.Error())
+			}
+		}
+
+		fields = append(fields, Field{Key: "errors", Value: wrapped})
+	}
+
+	return fields.MarshalJSON()
+}
+
+// Format implements the fmt.Formatter interface. The "%v" and "%s" format
+// verbs print the same single line as the Error method. The "%+v" format verb
+// prints it followed by captured stack with function name and source location
+// of every stack frame in separate lines, starting from the error creator.
+func (r *RuntimeError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(state, r.Error())
+
+		if state.Flag('+') {
+			for _, frame := range r.Stack() {
+				_, _ = io.WriteString(state, "\n"+frame.Function+"\n\t"+frame.Path+":"+strconv.Itoa(frame.Line))
+			}
+		}
+	case 's':
+		_, _ = io.WriteString(state, r.Error())
+	case 'q':
+		_, _ = io.WriteString(state, strconv.Quote(r.Error()))
+	default:
+		_, _ = fmt.Fprintf(state, "%%!%c(*logger.RuntimeError=%s)", verb, r.Error())
+	}
+}
+
+// Stack returns source locations of captured stack frames starting from
+// the error creator.
+func (r *RuntimeError) Stack() []Source {
+	if len(r.stack) == 0 {
+		return nil
+	}
+
+	sources := make([]Source, 0, len(r.stack))
+	frames := runtime.CallersFrames(r.stack)
+
+	for {
+		frame, more := frames.Next()
+
+		sources = append(sources, Source{
+			Function: frame.Function,
+			Name:     filepath.Base(frame.File),
+			Path:     frame.File,
+			Line:     frame.Line,
+		})
+
+		if !more {
+			return sources
+		}
+	}
+}
+
+// Code returns error code. RuntimeError created without error code returns
+// DefaultErrorCode.
+func (r *RuntimeError) Code() int {
+	return r.code
+}
+
+// HasCode returns true if RuntimeError was created with error code by
+// the NewRuntimeErrorCode function.
+func (r *RuntimeError) HasCode() bool {
+	return r.hasCode
+}
+
+// ErrorCodeOf returns error code of the first error with error code found in
+// provided error tree in depth-first order like the errors.As function.
+// Errors implementing the ErrorCoder interface with the HasCode method that
+// returns false, like RuntimeError created without error code, are skipped
+// but errors wrapped by them are searched.
+func ErrorCodeOf(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	if coder, ok := err.(ErrorCoder); ok && hasErrorCode(coder) { // nolint:errorlint
+		return coder.Code(), true
+	}
+
+	switch e := err.(type) { // nolint:errorlint
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if code, ok := ErrorCodeOf(wrapped); ok {
+				return code, true
+			}
+		}
+	case interface{ Unwrap() error }:
+		return ErrorCodeOf(e.Unwrap())
+	}
+
+	return 0, false
+}
+
+// hasErrorCode returns true if provided error has error code. Errors without
+// the HasCode method always have error code.
+func hasErrorCode(coder ErrorCoder) bool {
+	if checker, ok := coder.(interface{ HasCode() bool }); ok {
+		return checker.HasCode()
+	}
+
+	return true
+}
+
+// Unwrap returns all wrapped errors from arguments in order. It allows the
+// errors.Is and errors.As functions to traverse all of them.
+func (r *RuntimeError) Unwrap() []error {
+	var errs []error
+
+	for _, argument := range r.arguments {
+		if err, ok := argument.(error); ok {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
 }
 
-// Unwrap wrapped error.
-func (r *RuntimeError) Unwrap() error {
+// Cause returns the first wrapped error from arguments or nil. It is
+// the single error counterpart of the Unwrap method for callers that expect
+// only one wrapped error.
+func (r *RuntimeError) Cause() error {
 	for _, argument := range r.arguments {
 		if err, ok := argument.(error); ok {
 			return err
@@ -15,6 +15,10 @@
 package logger_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"gitlab.com/tymonx/go-logger/logger"
@@ -35,7 +39,7 @@ func TestRuntimeErrorNew(test *testing.T) {
 func TestRuntimeErrorNoArguments(test *testing.T) {
 	err := logger.NewRuntimeError("test")
 
-	want := "runtime_error_test.go:36:logger_test.TestRuntimeErrorNoArguments(): test"
+	want := "runtime_error_test.go:40:logger_test.TestRuntimeErrorNoArguments(): test"
 
 	if err == nil {
 		test.Error("NewRuntimeError() returns nil")
@@ -53,7 +57,7 @@ func TestRuntimeErrorNoArguments(test *testing.T) {
 func TestRuntimeErrorAutoPlacedArguments(test *testing.T) {
 	err := logger.NewRuntimeError("test", 3, "hello", "world", nil, 0)
 
-	want := "runtime_error_test.go:54:logger_test.TestRuntimeErrorAutoPlacedArguments(): test 3 hello world <nil> 0"
+	want := "runtime_error_test.go:58:logger_test.TestRuntimeErrorAutoPlacedArguments(): test 3 hello world <nil> 0"
 
 	if err == nil {
 		test.Error("NewRuntimeError() returns nil")
@@ -71,7 +75,7 @@ func TestRuntimeErrorAutoPlacedArguments(test *testing.T) {
 func TestRuntimeErrorError(test *testing.T) {
 	err := logger.NewRuntimeError("test", testError)
 
-	want := "runtime_error_test.go:72:logger_test.TestRuntimeErrorError(): test My test error"
+	want := "runtime_error_test.go:76:logger_test.TestRuntimeErrorError(): test My test error"
 
 	if err == nil {
 		test.Error("NewRuntimeError() returns nil")
@@ -94,7 +98,7 @@ func TestRuntimeErrorErrors(test *testing.T) {
 
 	err := logger.NewRuntimeError("test", errs...)
 
-	want := "runtime_error_test.go:95:logger_test.TestRuntimeErrorErrors(): test My test error My test error"
+	want := "runtime_error_test.go:99:logger_test.TestRuntimeErrorErrors(): test My test error My test error"
 
 	if err == nil {
 		test.Error("NewRuntimeError() returns nil")
@@ -108,3 +112,200 @@ func TestRuntimeErrorErrors(test *testing.T) {
 		test.Error("Unwrap() returns nil")
 	}
 }
+
+func TestRuntimeErrorUnwrapAll(test *testing.T) {
+	sentinel := Error("sentinel")
+
+	err := logger.NewRuntimeError("test", testError, 5, sentinel)
+
+	if len(err.Unwrap()) != 2 {
+		test.Errorf("len(Unwrap()) = %d; want 2", len(err.Unwrap()))
+	}
+
+	if !errors.Is(err, sentinel) {
+		test.Error("errors.Is() = false; want true")
+	}
+
+	var target Error
+
+	if !errors.As(err, &target) || target != testError {
+		test.Error("errors.As() =", target, "; want", testError)
+	}
+}
+
+func TestRuntimeErrorCode(test *testing.T) {
+	if code := logger.NewRuntimeError("test").Code(); code != logger.DefaultErrorCode {
+		test.Errorf("Code() = %d; want %d", code, logger.DefaultErrorCode)
+	}
+
+	err := logger.NewRuntimeErrorCode(42, "test")
+
+	want := "runtime_error_test.go:141:logger_test.TestRuntimeErrorCode(): test"
+
+	if err.Error() != want {
+		test.Error("Error() =", err.Error(), "; want", want)
+	}
+
+	var coder logger.ErrorCoder
+
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &coder) || coder.Code() != 42 {
+		test.Error("errors.As() =", coder, "; want error code 42")
+	}
+}
+
+type wrappedError struct {
+	error
+}
+
+func (w wrappedError) Unwrap() error {
+	return w.error
+}
+
+func TestRuntimeErrorUnwrapSentinels(test *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+	wrapped := wrappedError{second}
+
+	err := logger.NewRuntimeError("test {p} {p}", first, 1, wrapped)
+
+	for _, want := range []error{first, second} {
+		if !errors.Is(err, want) {
+			test.Errorf("errors.Is(%v) = false; want true", want)
+		}
+	}
+
+	var target wrappedError
+
+	if !errors.As(err, &target) || (target != wrapped) {
+		test.Error("errors.As() =", target, "; want", wrapped)
+	}
+
+	if cause := err.Cause(); cause != first { // nolint:errorlint
+		test.Error("Cause() =", cause, "; want", first)
+	}
+
+	if cause := logger.NewRuntimeError("test", 1).Cause(); cause != nil {
+		test.Error("Cause() returns an unexpected error", cause)
+	}
+}
+
+func newHelperError() *logger.RuntimeError {
+	return logger.NewRuntimeErrorBase(1, "test")
+}
+
+func TestRuntimeErrorStack(test *testing.T) {
+	err := newHelperError()
+	stack := err.Stack()
+
+	if (len(stack) == 0) || !strings.HasSuffix(stack[0].Function, ".TestRuntimeErrorStack") {
+		test.Fatalf("Stack() = %+v; want TestRuntimeErrorStack first", stack)
+	}
+
+	if stack[0].Name != "runtime_error_test.go" {
+		test.Errorf("Stack()[0].Name = %q; want %q", stack[0].Name, "runtime_error_test.go")
+	}
+
+	for verb, want := range map[string]string{"%v": err.Error(), "%s": err.Error(), "%q": fmt.Sprintf("%q", err.Error())} {
+		if got := fmt.Sprintf(verb, err); got != want {
+			test.Errorf("Sprintf(%q) = %q; want %q", verb, got, want)
+		}
+	}
+
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+
+	if (len(lines) != 1+2*len(stack)) || (lines[0] != err.Error()) {
+		test.Fatalf("Sprintf(%%+v) = %q; want error and stack", lines)
+	}
+
+	if want := fmt.Sprintf("\t%s:%d", stack[0].Path, stack[0].Line); (lines[1] != stack[0].Function) || (lines[2] != want) {
+		test.Errorf("Sprintf(%%+v) = %q, %q; want %q, %q", lines[1], lines[2], stack[0].Function, want)
+	}
+}
+
+func TestRuntimeErrorStackDepth(test *testing.T) {
+	defer logger.SetRuntimeErrorStackDepth(logger.DefaultRuntimeErrorStackDepth)
+
+	logger.SetRuntimeErrorStackDepth(1)
+
+	if depth := logger.GetRuntimeErrorStackDepth(); depth != 1 {
+		test.Errorf("GetRuntimeErrorStackDepth() = %d; want 1", depth)
+	}
+
+	if stack := logger.NewRuntimeError("test").Stack(); len(stack) != 1 {
+		test.Errorf("len(Stack()) = %d; want 1", len(stack))
+	}
+
+	logger.SetRuntimeErrorStackDepth(-1)
+
+	err := logger.NewRuntimeError("test")
+
+	if stack := err.Stack(); stack != nil {
+		test.Errorf("Stack() = %+v; want nil", stack)
+	}
+
+	if formatted := fmt.Sprintf("%+v", err); formatted != err.Error() {
+		test.Errorf("Sprintf(%%+v) = %q; want %q", formatted, err.Error())
+	}
+}
+
+func TestRuntimeErrorMarshalJSON(test *testing.T) {
+	inner := logger.NewRuntimeErrorCode(7, "inner {p}", testError)
+	err := logger.NewRuntimeError("outer {p}", inner, 3)
+
+	if (err.File() != "runtime_error_test.go") || (err.Line() == 0) ||
+		!strings.HasSuffix(err.Function(), ".TestRuntimeErrorMarshalJSON") {
+		test.Errorf("File(), Line(), Function() = %q, %d, %q; want source location",
+			err.File(), err.Line(), err.Function())
+	}
+
+	if want := "outer " + inner.Error() + " 3"; err.Message() != want {
+		test.Errorf("Message() = %q; want %q", err.Message(), want)
+	}
+
+	data, jsonErr := json.Marshal(err)
+
+	if jsonErr != nil {
+		test.Fatal("json.Marshal() returns an unexpected error", jsonErr)
+	}
+
+	want := fmt.Sprintf(`{"message":%q,"file":"runtime_error_test.go","line":%d,"function":%q,"code":%d,`+
+		`"errors":[{"message":"inner My test error","file":"runtime_error_test.go","line":%d,"function":%q,"code":7,`+
+		`"errors":["My test error"]}]}`,
+		err.Message(), err.Line(), err.Function(), logger.DefaultErrorCode, inner.Line(), inner.Function())
+
+	if string(data) != want {
+		test.Errorf("json.Marshal() = %s; want %s", data, want)
+	}
+
+	if data, jsonErr = json.Marshal(logger.NewRuntimeError("test")); jsonErr != nil {
+		test.Fatal("json.Marshal() returns an unexpected error", jsonErr)
+	} else if strings.Contains(string(data), `"errors"`) {
+		test.Errorf("json.Marshal() = %s; want no errors", data)
+	}
+}
+
+func TestRuntimeErrorCodeWrapped(test *testing.T) {
+	err := logger.NewRuntimeError("outer", logger.NewRuntimeErrorCode(42, "inner"))
+
+	if code, ok := logger.ErrorCodeOf(err); !ok || (code != 42) {
+		test.Errorf("ErrorCodeOf() = %d, %t; want 42, true", code, ok)
+	}
+
+	var coder logger.ErrorCoder
+
+	if !errors.As(err, &coder) || (coder.Code() != logger.DefaultErrorCode) {
+		test.Fatal("errors.As() =", coder, "; want outer error")
+	}
+
+	if checker, ok := coder.(interface{ HasCode() bool }); !ok || checker.HasCode() {
+		test.Error("HasCode() = true for error created without error code; want false")
+	}
+
+	if code, ok := logger.ErrorCodeOf(fmt.Errorf("wrapped: %w", err)); !ok || (code != 42) {
+		test.Errorf("ErrorCodeOf() = %d, %t; want 42, true", code, ok)
+	}
+
+	if code, ok := logger.ErrorCodeOf(logger.NewRuntimeError("uncoded")); ok {
+		test.Errorf("ErrorCodeOf() = %d, %t; want 0, false", code, ok)
+	}
+}
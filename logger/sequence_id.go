@@ -0,0 +1,77 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("sequence", func() IDGenerator { return NewSequenceID("") })
+}
+
+// SequenceIDWidth defines minimum number of digits of sequence numbers
+// generated by the SequenceID generator. Shorter sequence numbers are padded
+// with leading zeros.
+const SequenceIDWidth = 6
+
+// A SequenceID represents ID generator that generates prefixed sequence
+// numbers, like for example "req-000042", that are easier to correlate than
+// UUID4. Sequence numbers are unique only within the current process. They
+// start again from the start value after restarting the application and they
+// may repeat between different processes or SequenceID generators.
+type SequenceID struct {
+	prefix  string
+	counter uint64
+}
+
+// NewSequenceID creates a new SequenceID object that generates sequence
+// numbers with provided prefix starting from 1.
+func NewSequenceID(prefix string) *SequenceID {
+	return &SequenceID{
+		prefix: prefix,
+	}
+}
+
+// SetStart sets sequence number returned by the next call of the Generate
+// method.
+func (s *SequenceID) SetStart(start uint64) *SequenceID {
+	atomic.StoreUint64(&s.counter, start-1)
+
+	return s
+}
+
+// GetPrefix returns prefix of generated sequence numbers.
+func (s *SequenceID) GetPrefix() string {
+	return s.prefix
+}
+
+// Generate generates next prefixed sequence number. It is lock-free and it
+// can be called concurrently.
+func (s *SequenceID) Generate() (id string, err error) {
+	var digits [20]byte
+
+	number := strconv.AppendUint(digits[:0], atomic.AddUint64(&s.counter, 1), 10)
+
+	buffer := make([]byte, 0, len(s.prefix)+SequenceIDWidth+len(number))
+	buffer = append(buffer, s.prefix...)
+
+	for padding := SequenceIDWidth - len(number); padding > 0; padding-- {
+		buffer = append(buffer, '0')
+	}
+
+	return string(append(buffer, number...)), nil
+}
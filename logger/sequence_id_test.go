@@ -0,0 +1,120 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestSequenceID(test *testing.T) {
+	generator := logger.NewSequenceID("req-")
+
+	for _, want := range []string{"req-000001", "req-000002"} {
+		if id, err := generator.Generate(); (err != nil) || (id != want) {
+			test.Errorf("Generate() = %q, %v; want %q, nil", id, err, want)
+		}
+	}
+
+	generator.SetStart(42)
+
+	if id, _ := generator.Generate(); id != "req-000042" {
+		test.Errorf("Generate() = %q; want %q", id, "req-000042")
+	}
+
+	generator.SetStart(1234567)
+
+	if id, _ := generator.Generate(); id != "req-1234567" {
+		test.Errorf("Generate() = %q; want %q", id, "req-1234567")
+	}
+
+	if id, _ := logger.NewSequenceID("").SetStart(0).Generate(); id != "000000" {
+		test.Errorf("Generate() = %q; want %q", id, "000000")
+	}
+}
+
+func TestSequenceIDConcurrent(test *testing.T) {
+	const (
+		goroutines = 8
+		ids        = 1000
+	)
+
+	generator := logger.NewSequenceID("")
+	generated := make([][]string, goroutines)
+
+	var wg sync.WaitGroup
+
+	for index := range generated {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+
+			for count := 0; count < ids; count++ {
+				id, _ := generator.Generate()
+				generated[index] = append(generated[index], id)
+			}
+		}(index)
+	}
+
+	wg.Wait()
+
+	unique := make(map[string]struct{}, goroutines*ids)
+
+	for _, list := range generated {
+		for _, id := range list {
+			unique[id] = struct{}{}
+		}
+	}
+
+	if len(unique) != goroutines*ids {
+		test.Errorf("len(unique) = %d; want %d", len(unique), goroutines*ids)
+	}
+}
+
+func TestCreateIDGenerator(test *testing.T) {
+	for _, name := range []string{"sequence", "snowflake", "static", "ulid", "uuid4", "uuid7"} {
+		generator, err := logger.CreateIDGenerator(name)
+
+		if err != nil {
+			test.Fatal("CreateIDGenerator() returns an unexpected error", err)
+		}
+
+		if id, err := generator.Generate(); (err != nil) || (id == "") {
+			test.Errorf("Generate() = %q, %v; want ID", id, err)
+		}
+	}
+
+	if _, err := logger.CreateIDGenerator("unknown"); err == nil {
+		test.Error("CreateIDGenerator() returns nil error for unknown ID generator")
+	}
+
+	if err := logger.RegisterIDGenerator(" ", func() logger.IDGenerator { return nil }); err == nil {
+		test.Error("RegisterIDGenerator() returns nil error for empty name")
+	}
+
+	if err := logger.RegisterIDGenerator("nil", nil); err == nil {
+		test.Error("RegisterIDGenerator() returns nil error for nil constructor")
+	}
+
+	names := logger.RegisteredIDGenerators()
+
+	if want := []string{"context", "none", "sequence", "snowflake", "static", "ulid", "uuid4", "uuid7"}; !reflect.DeepEqual(names, want) {
+		test.Errorf("RegisteredIDGenerators() = %v; want %v", names, want)
+	}
+}
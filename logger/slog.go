@@ -0,0 +1,239 @@
+//go:build go1.21
+
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SlogGroupSeparator defines separator used between slog group names and
+// attribute keys in names of named values.
+const SlogGroupSeparator = "."
+
+// A SlogHandler represents a slog.Handler that logs slog records via logger.
+// Slog attributes are logged as the Fields log argument with keys qualified
+// by names of slog groups like for example "request.method".
+type SlogHandler struct {
+	logger *Logger
+	fields Fields
+	prefix string
+}
+
+// NewSlogHandler creates a new SlogHandler object that logs slog records via
+// provided logger using its logger worker thread and log handlers.
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{
+		logger: l,
+	}
+}
+
+// Enabled returns true if log record with provided slog level would be
+// logged.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	value, _ := fromSlogLevel(level)
+
+	return h.logger.isLevelEnabled(value)
+}
+
+// Handle logs provided slog record. Slog levels Debug, Info, Warn and Error
+// are mapped to the DebugLevel, InfoLevel, WarningLevel and ErrorLevel log
+// levels. Other slog levels are logged like with the Log method with log
+// level value offset from the nearest lower mapped log level and with the
+// slog level name, like for example "info+2". Source location is taken from
+// the slog record program counter.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level, levelName := fromSlogLevel(r.Level)
+
+	fields := append(Fields(nil), h.fields...)
+
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	message := r.Message
+
+	var arguments []interface{}
+
+	if len(fields) != 0 {
+		message = escapeTemplate(message)
+		arguments = []interface{}{fields}
+	}
+
+	record := h.logger.createRecord(level, levelName, message, arguments)
+	record.Context = ctx
+
+	if !r.Time.IsZero() {
+		record.Time = r.Time
+	}
+
+	if (r.PC != 0) && h.logger.isCallerCaptured(level) {
+		record.File = getSlogSource(r.PC)
+	}
+
+	h.logger.logRecord(record)
+
+	return nil
+}
+
+// WithAttrs returns a new SlogHandler object with provided slog attributes
+// bound to all logged slog records.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = append(Fields(nil), h.fields...)
+
+	for _, attr := range attrs {
+		clone.fields = appendSlogAttr(clone.fields, h.prefix, attr)
+	}
+
+	return &clone
+}
+
+// WithGroup returns a new SlogHandler object that qualifies keys of all
+// following slog attributes by provided slog group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.prefix = h.prefix + name + SlogGroupSeparator
+
+	return &clone
+}
+
+// RecordFromSlog returns a new log record converted from provided slog record
+// that can be emitted using the Emit method. Slog levels are mapped like with
+// the SlogHandler.Handle method. Slog attributes are stored in the Fields log
+// argument with keys qualified by names of slog groups like for example
+// "request.method" and then template delimiters in log message are escaped.
+// Source location is taken from the slog record program counter.
+func RecordFromSlog(r slog.Record) *Record {
+	level, levelName := fromSlogLevel(r.Level)
+
+	if levelName == "" {
+		levelName = GetLevelName(level)
+	}
+
+	record := &Record{
+		Time:    r.Time,
+		Message: r.Message,
+		Level: Level{
+			Name:  levelName,
+			Value: level,
+		},
+	}
+
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	var fields Fields
+
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, "", attr)
+		return true
+	})
+
+	if len(fields) != 0 {
+		record.Message = escapeTemplate(record.Message)
+		record.Arguments = Arguments{fields}
+	}
+
+	if r.PC != 0 {
+		record.File = getSlogSource(r.PC)
+	}
+
+	return record
+}
+
+// getSlogSource returns source location from provided program counter.
+func getSlogSource(pc uintptr) Source {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return Source{
+		Line:     frame.Line,
+		Path:     frame.File,
+		Function: frame.Function,
+	}
+}
+
+// fromSlogLevel returns log level value and name mapped from provided slog
+// level. Empty log level name is used for mapped slog levels.
+func fromSlogLevel(level slog.Level) (int, string) {
+	var base slog.Level
+
+	var value int
+
+	switch {
+	case level >= slog.LevelError:
+		base, value = slog.LevelError, ErrorLevel
+	case level >= slog.LevelWarn:
+		base, value = slog.LevelWarn, WarningLevel
+	case level >= slog.LevelInfo:
+		base, value = slog.LevelInfo, InfoLevel
+	default:
+		base, value = slog.LevelDebug, DebugLevel
+	}
+
+	if level == base {
+		return value, ""
+	}
+
+	return value + int(level-base), strings.ToLower(level.String())
+}
+
+// appendSlogAttr appends provided slog attribute to named values. Slog groups
+// are flattened with keys qualified by names of slog groups.
+func appendSlogAttr(fields Fields, prefix string, attr slog.Attr) Fields {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(fields, Field{
+			Key:   prefix + attr.Key,
+			Value: attr.Value.Any(),
+		})
+	}
+
+	if attr.Key != "" {
+		prefix += attr.Key + SlogGroupSeparator
+	}
+
+	for _, member := range attr.Value.Group() {
+		fields = appendSlogAttr(fields, prefix, member)
+	}
+
+	return fields
+}
+
+// escapeTemplate returns provided message with escaped template delimiters
+// that is formatted as the same message.
+func escapeTemplate(message string) string {
+	if !strings.ContainsAny(message, "{}") {
+		return message
+	}
+
+	return strings.NewReplacer("{", `{"{"}`, "}", `{"}"}`).Replace(message)
+}
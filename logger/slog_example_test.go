@@ -0,0 +1,39 @@
+//go:build go1.21
+
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"fmt"
+	"log/slog"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func ExampleNewSlogHandler() {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{level}: {message}")
+
+	log := logger.New().SetHandler("buffer", buffer).SetMinimumLevel(logger.InfoLevel).SetSynchronous(true)
+
+	slogger := slog.New(logger.NewSlogHandler(log)).With("service", "api").WithGroup("request")
+
+	slogger.Info("Request handled", "method", "GET", "status", 200)
+	slogger.Debug("Dropped")
+
+	fmt.Print(buffer.String())
+	// Output: info: Request handled service=api request.method=GET request.status=200
+}
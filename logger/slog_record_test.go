@@ -0,0 +1,128 @@
+//go:build go1.21
+
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestRecordFromSlog(test *testing.T) {
+	observer := logger.NewObserver()
+
+	observer.SetMinimumLevel(logger.MinimumLevel)
+
+	log := logger.New().SetHandlers(logger.Handlers{"observer": observer})
+
+	defer log.Close()
+
+	now := time.Date(2020, time.March, 4, 5, 6, 7, 8, time.UTC)
+
+	levels := []struct {
+		slog  slog.Level
+		level logger.Level
+	}{
+		{slog.LevelDebug, logger.Level{Value: logger.DebugLevel, Name: logger.DebugName}},
+		{slog.LevelInfo, logger.Level{Value: logger.InfoLevel, Name: logger.InfoName}},
+		{slog.LevelWarn + 1, logger.Level{Value: logger.WarningLevel + 1, Name: "warn+1"}},
+		{slog.LevelError, logger.Level{Value: logger.ErrorLevel, Name: logger.ErrorName}},
+	}
+
+	for index, entry := range levels {
+		r := slog.NewRecord(now.Add(time.Duration(index)*time.Second), entry.slog, "Message", 0)
+
+		log.Emit(logger.RecordFromSlog(r))
+	}
+
+	log.Flush()
+
+	logs := observer.All()
+
+	if logs.Len() != len(levels) {
+		test.Fatalf("Len() = %d; want %d", logs.Len(), len(levels))
+	}
+
+	for index, entry := range logs {
+		if want := now.Add(time.Duration(index) * time.Second); !entry.Time.Equal(want) {
+			test.Errorf("Time = %v; want %v", entry.Time, want)
+		}
+
+		if entry.Level != levels[index].level {
+			test.Errorf("Level = %+v; want %+v", entry.Level, levels[index].level)
+		}
+
+		if entry.Message != "Message" {
+			test.Errorf("Message = %q; want %q", entry.Message, "Message")
+		}
+	}
+}
+
+func TestRecordFromSlogAttrs(test *testing.T) {
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "Value {}", 0)
+
+	r.AddAttrs(
+		slog.String("service", "api"),
+		slog.Group("request", slog.String("method", "GET"), slog.Int("status", 200)),
+		slog.Group("", slog.Bool("inline", true)),
+	)
+
+	record := logger.RecordFromSlog(r)
+
+	if record.Time.IsZero() {
+		test.Error("Time is zero; want current time")
+	}
+
+	if len(record.Arguments) != 1 {
+		test.Fatalf("len(Arguments) = %d; want 1", len(record.Arguments))
+	}
+
+	fields, ok := record.Arguments[0].(logger.Fields)
+
+	if !ok {
+		test.Fatalf("Arguments[0] = %T; want logger.Fields", record.Arguments[0])
+	}
+
+	want := logger.Fields{
+		{Key: "service", Value: "api"},
+		{Key: "request.method", Value: "GET"},
+		{Key: "request.status", Value: int64(200)},
+		{Key: "inline", Value: true},
+	}
+
+	if len(fields) != len(want) {
+		test.Fatalf("len(Fields) = %d; want %d", len(fields), len(want))
+	}
+
+	for index, field := range fields {
+		if field != want[index] {
+			test.Errorf("Fields[%d] = %+v; want %+v", index, field, want[index])
+		}
+	}
+
+	message, err := record.GetMessage()
+
+	if err != nil {
+		test.Fatal("GetMessage() returns an unexpected error", err)
+	}
+
+	if want := "Value {} service=api request.method=GET request.status=200 inline=true"; message != want {
+		test.Errorf("GetMessage() = %q; want %q", message, want)
+	}
+}
@@ -0,0 +1,93 @@
+//go:build go1.21
+
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestSlogHandler(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	buffer.SetMinimumLevel(logger.InfoLevel)
+	buffer.GetFormatter().SetFormat("{level}:{file}:{line}:{message}")
+
+	log := logger.New().SetHandlers(logger.Handlers{"buffer": buffer}).SetSynchronous(true)
+
+	defer log.Close()
+
+	slogger := slog.New(logger.NewSlogHandler(log))
+
+	slogger.Info("Message {p}", "user", "bob", slog.Group("request", "method", "GET"))
+	slogger.WithGroup("server").With("port", 80).Warn("Started", "tls", false)
+	slogger.Log(context.Background(), slog.LevelInfo+2, "Custom")
+	slogger.Debug("Filtered")
+
+	want := "info:slog_test.go:39:Message {p} user=bob request.method=GET\n" +
+		"warning:slog_test.go:40:Started server.port=80 server.tls=false\n" +
+		"info+2:slog_test.go:41:Custom\n"
+
+	if buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestSlogHandlerLevels(test *testing.T) {
+	observer := logger.NewObserver()
+
+	observer.SetMinimumLevel(logger.MinimumLevel)
+
+	log := logger.New().SetHandlers(logger.Handlers{"observer": observer})
+
+	defer log.Close()
+
+	handler := logger.NewSlogHandler(log)
+
+	for _, level := range []slog.Level{slog.LevelDebug - 2, slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError + 4} {
+		if !handler.Enabled(context.Background(), level) {
+			test.Errorf("Enabled(%v) = false; want true", level)
+		}
+
+		slog.New(handler).Log(context.Background(), level, "Message")
+	}
+
+	log.Flush()
+
+	want := []logger.Level{
+		{Value: logger.DebugLevel - 2, Name: "debug-2"},
+		{Value: logger.DebugLevel, Name: logger.DebugName},
+		{Value: logger.InfoLevel, Name: logger.InfoName},
+		{Value: logger.WarningLevel, Name: logger.WarningName},
+		{Value: logger.ErrorLevel + 4, Name: "error+4"},
+	}
+
+	logs := observer.All()
+
+	if logs.Len() != len(want) {
+		test.Fatalf("Len() = %d; want %d", logs.Len(), len(want))
+	}
+
+	for index, entry := range logs {
+		if entry.Level != want[index] {
+			test.Errorf("Level = %+v; want %+v", entry.Level, want[index])
+		}
+	}
+}
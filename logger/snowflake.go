@@ -0,0 +1,129 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("snowflake", func() IDGenerator { return NewSnowflake(0) })
+}
+
+// SnowflakeEpoch defines epoch of timestamps in Snowflake IDs, it is
+// 2020-01-01T00:00:00Z in milliseconds since the Unix epoch.
+const SnowflakeEpoch = 1577836800000
+
+// These constants define sizes of Snowflake ID parts in bits.
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+)
+
+// These constants define maximum values of Snowflake ID parts.
+const (
+	MaximumSnowflakeNodeID   = 1<<snowflakeNodeBits - 1
+	maximumSnowflakeSequence = 1<<snowflakeSequenceBits - 1
+)
+
+// A Snowflake represents Snowflake ID generator. Snowflake ID is a 63-bit
+// unsigned integer in decimal format with 41-bit timestamp in milliseconds
+// since the SnowflakeEpoch, 10-bit node ID and 12-bit sequence number. Node
+// ID identifies application instance that generated ID and it can be obtained
+// from ID using the ParseSnowflake function.
+//
+// Up to 4096 IDs are generated within the same millisecond. Further IDs borrow
+// timestamp of the next millisecond instead of waiting for it. When wall time
+// goes back, IDs keep using the last timestamp and an internal error is
+// reported once, generating of IDs is never blocked.
+type Snowflake struct {
+	mutex     sync.Mutex
+	nodeID    uint16
+	timestamp int64
+	wallTime  int64
+	sequence  int64
+	backwards bool
+}
+
+// NewSnowflake creates a new Snowflake object with provided node ID. Node ID
+// must not be greater than MaximumSnowflakeNodeID.
+func NewSnowflake(nodeID uint16) *Snowflake {
+	return &Snowflake{
+		nodeID: nodeID,
+	}
+}
+
+// GetNodeID returns node ID.
+func (s *Snowflake) GetNodeID() uint16 {
+	return s.nodeID
+}
+
+// Generate generates new Snowflake ID.
+func (s *Snowflake) Generate() (id string, err error) {
+	if s.nodeID > MaximumSnowflakeNodeID {
+		return "", NewRuntimeError("cannot generate Snowflake ID, invalid node ID", s.nodeID)
+	}
+
+	now := time.Now().UnixMilli() - SnowflakeEpoch
+
+	s.mutex.Lock()
+
+	report := false
+
+	if now < s.wallTime {
+		report = !s.backwards
+		s.backwards = true
+	} else {
+		s.wallTime = now
+		s.backwards = false
+	}
+
+	if now > s.timestamp {
+		s.timestamp = now
+		s.sequence = 0
+	} else if s.sequence++; s.sequence > maximumSnowflakeSequence {
+		s.timestamp++
+		s.sequence = 0
+	}
+
+	value := s.timestamp<<(snowflakeNodeBits+snowflakeSequenceBits) |
+		int64(s.nodeID)<<snowflakeSequenceBits | s.sequence
+
+	s.mutex.Unlock()
+
+	if report {
+		printError(NewRuntimeError("clock moved backwards, Snowflake ID uses the last timestamp"))
+	}
+
+	return strconv.FormatInt(value, 10), nil
+}
+
+// ParseSnowflake returns time, node ID and sequence number from provided
+// Snowflake ID.
+func ParseSnowflake(id string) (created time.Time, nodeID, sequence uint16, err error) {
+	value, err := strconv.ParseInt(id, 10, 64)
+
+	if (err != nil) || (value < 0) {
+		return time.Time{}, 0, 0, NewRuntimeError("cannot parse Snowflake ID {p | printf \"%q\"}", id, err)
+	}
+
+	created = time.UnixMilli(value>>(snowflakeNodeBits+snowflakeSequenceBits) + SnowflakeEpoch)
+	nodeID = uint16(value >> snowflakeSequenceBits & MaximumSnowflakeNodeID)
+	sequence = uint16(value & maximumSnowflakeSequence)
+
+	return created, nodeID, sequence, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestSnowflake(test *testing.T) {
+	const ids = 10000
+
+	generator := logger.NewSnowflake(42)
+
+	if nodeID := generator.GetNodeID(); nodeID != 42 {
+		test.Errorf("GetNodeID() = %d; want 42", nodeID)
+	}
+
+	before := time.Now().Truncate(time.Millisecond)
+
+	var previous int64
+
+	for index := 0; index < ids; index++ {
+		id, err := generator.Generate()
+
+		if err != nil {
+			test.Fatal("Generate() returns an unexpected error", err)
+		}
+
+		value, err := strconv.ParseInt(id, 10, 64)
+
+		if err != nil {
+			test.Fatal("ParseInt() returns an unexpected error", err)
+		}
+
+		if value <= previous {
+			test.Fatalf("Generate() = %d; want greater than %d", value, previous)
+		}
+
+		previous = value
+	}
+
+	after := time.Now().Add((ids/4096 + 1) * time.Millisecond)
+
+	created, nodeID, _, err := logger.ParseSnowflake(strconv.FormatInt(previous, 10))
+
+	if err != nil {
+		test.Fatal("ParseSnowflake() returns an unexpected error", err)
+	}
+
+	if nodeID != 42 {
+		test.Errorf("ParseSnowflake() node ID = %d; want 42", nodeID)
+	}
+
+	if created.Before(before) || created.After(after) {
+		test.Errorf("ParseSnowflake() time = %v; want between %v and %v", created, before, after)
+	}
+}
+
+func TestSnowflakeErrors(test *testing.T) {
+	if _, err := logger.NewSnowflake(logger.MaximumSnowflakeNodeID + 1).Generate(); err == nil {
+		test.Error("Generate() doesn't return an error for invalid node ID")
+	}
+
+	for _, id := range []string{"", "snowflake", "-1"} {
+		if _, _, _, err := logger.ParseSnowflake(id); err == nil {
+			test.Errorf("ParseSnowflake(%q) doesn't return an error", id)
+		}
+	}
+}
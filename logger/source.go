@@ -14,10 +14,69 @@
 
 package logger
 
-// Source defines log file information fields.
+import (
+	"strings"
+)
+
+// Source defines log file information fields. Package is the full import path
+// of the function package and it is set together with the Name field.
 type Source struct {
 	Function string `json:"function"`
+	Package  string `json:"-"`
 	Name     string `json:"name"`
 	Path     string `json:"-"`
 	Line     int    `json:"line"`
 }
+
+// splitFunction splits fully qualified function name returned by
+// the runtime.FuncForPC function, like "example.com/pkg.(*T).Method.func1",
+// to package path "example.com/pkg" and function name "(*T).Method.func1".
+// Function name without package path, like "pkg.Func", is split to package
+// name "pkg" and function name "Func".
+func splitFunction(function string) (pkg, name string) {
+	start := function
+
+	if index := strings.IndexByte(start, '['); index >= 0 {
+		start = start[:index]
+	}
+
+	slash := strings.LastIndexByte(start, '/') + 1
+
+	dot := strings.IndexByte(start[slash:], '.')
+
+	if dot < 0 {
+		return "", function
+	}
+
+	return function[:slash+dot], function[slash+dot+1:]
+}
+
+// getPackageName returns package name that is the last element of provided
+// package path.
+func getPackageName(pkg string) string {
+	return pkg[strings.LastIndexByte(pkg, '/')+1:]
+}
+
+// getShortFunction returns function name without closure suffixes like
+// ".func1" or ".1".
+func getShortFunction(name string) string {
+	for {
+		index := strings.LastIndexByte(name, '.')
+
+		if index < 0 {
+			return name
+		}
+
+		suffix := name[index+1:]
+
+		if strings.HasPrefix(suffix, "func") {
+			suffix = suffix[len("func"):]
+		}
+
+		if (suffix == "") || (strings.Trim(suffix, "0123456789") != "") {
+			return name
+		}
+
+		name = name[:index]
+	}
+}
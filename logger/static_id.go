@@ -0,0 +1,50 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("static", func() IDGenerator { return NewStaticID(DefaultStaticID) })
+	_ = RegisterIDGenerator("none", func() IDGenerator { return NewNoID() })
+}
+
+// DefaultStaticID defines ID returned by the StaticID generator registered
+// under the "static" name. It is the nil UUID.
+const DefaultStaticID = "00000000-0000-0000-0000-000000000000"
+
+// A StaticID represents ID generator that returns the same ID every time. Use
+// it with the NewStaticID or NewNoID functions to make formatted log messages
+// with the {id} placeholder deterministic, like for example in Example tests
+// or in golden-file tests.
+type StaticID struct {
+	value string
+}
+
+// NewStaticID creates a new StaticID object that returns provided ID.
+func NewStaticID(value string) *StaticID {
+	return &StaticID{
+		value: value,
+	}
+}
+
+// NewNoID creates a new StaticID object that returns empty ID, the {id}
+// placeholder is formatted as empty string.
+func NewNoID() *StaticID {
+	return NewStaticID("")
+}
+
+// Generate returns the same ID every time.
+func (s *StaticID) Generate() (id string, err error) {
+	return s.value, nil
+}
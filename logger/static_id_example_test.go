@@ -0,0 +1,37 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"fmt"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func ExampleNewStaticID() {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("[{id}] {level}: {message}")
+
+	log := logger.New().SetHandler("buffer", buffer).SetIDGenerator(logger.NewStaticID("request")).SetSynchronous(true)
+
+	log.Info("Deterministic output")
+
+	log.SetIDGenerator(logger.NewNoID()).Info("Without ID")
+
+	fmt.Print(buffer.String())
+	// Output:
+	// [request] info: Deterministic output
+	// [] info: Without ID
+}
@@ -18,12 +18,29 @@ import (
 	"os"
 )
 
-// NewStderr created a new Stderr log handler object.
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandler("stderr", func() Handler {
+		return NewStderr()
+	})
+}
+
+// NewStderr created a new Stderr log handler object. ANSI colors of its
+// formatter are enabled when standard error output is a terminal.
 func NewStderr() *Stream {
 	stream := NewStream()
 
-	stream.writer = os.Stderr
-	stream.minimumLevel = ErrorLevel
+	stream.writer = stderrWriter{}
+	stream.SetMinimumLevel(ErrorLevel)
+	stream.formatter.SetColors(IsColorSupported(os.Stderr))
 
 	return stream
 }
+
+// stderrWriter writes to the current standard error output. It follows reassignment
+// of os.Stderr after log handler creation.
+type stderrWriter struct{}
+
+// Write writes data to os.Stderr.
+func (stderrWriter) Write(data []byte) (int, error) {
+	return os.Stderr.Write(data)
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"log"
+)
+
+// RedirectStdlog redirects output of the standard log package to provided
+// logger. Every line written by the standard log package is logged as a log
+// record with provided log level. Flags of the standard log package are
+// cleared to not duplicate date and time already added by logger. Returned
+// function restores previous output and flags of the standard log package.
+func RedirectStdlog(l *Logger, level int) func() {
+	output := log.Writer()
+	flags := log.Flags()
+
+	log.SetFlags(0)
+	log.SetOutput(l.Writer(level, ""))
+
+	return func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestRedirectStdlog(test *testing.T) {
+	buffer := logger.NewBuffer()
+
+	buffer.GetFormatter().SetFormat("{level}:{message}")
+
+	target := logger.New().SetHandlers(logger.Handlers{"buffer": buffer}).SetSynchronous(true)
+
+	defer target.Close()
+
+	restore := logger.RedirectStdlog(target, logger.WarningLevel)
+
+	log.Printf("Redirected %d", 1)
+	log.Print("Multiple\nlines")
+
+	restore()
+
+	if want := "warning:Redirected 1\nwarning:Multiple\nwarning:lines\n"; buffer.String() != want {
+		test.Errorf("buffer = %q; want %q", buffer.String(), want)
+	}
+
+	if log.Writer() != os.Stderr {
+		test.Error("RedirectStdlog() doesn't restore output")
+	}
+
+	if log.Flags() != log.LstdFlags {
+		test.Errorf("log.Flags() = %d; want %d", log.Flags(), log.LstdFlags)
+	}
+}
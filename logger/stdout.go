@@ -18,12 +18,29 @@ import (
 	"os"
 )
 
-// NewStdout created a new Stdout log handler object.
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandler("stdout", func() Handler {
+		return NewStdout()
+	})
+}
+
+// NewStdout created a new Stdout log handler object. ANSI colors of its
+// formatter are enabled when standard output is a terminal.
 func NewStdout() *Stream {
 	stream := NewStream()
 
-	stream.writer = os.Stdout
-	stream.maximumLevel = ErrorLevel - 1
+	stream.writer = stdoutWriter{}
+	stream.SetMaximumLevel(ErrorLevel - 1)
+	stream.formatter.SetColors(IsColorSupported(os.Stdout))
 
 	return stream
 }
+
+// stdoutWriter writes to the current standard output. It follows reassignment
+// of os.Stdout after log handler creation.
+type stdoutWriter struct{}
+
+// Write writes data to os.Stdout.
+func (stdoutWriter) Write(data []byte) (int, error) {
+	return os.Stdout.Write(data)
+}
@@ -15,42 +15,164 @@
 package logger
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"io"
+	"math"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 )
 
-// StreamHandler defines a custom stream handler for writing log records with writer.
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandlerFactory("stream", newStreamFromOptions)
+}
+
+// DefaultLineEnding defines default line ending written after every log
+// record by stream handlers.
+const DefaultLineEnding = "\n"
+
+// DefaultRetryTimeout defines default maximum total duration of retrying
+// failed writes set by the Stream SetRetry method.
+const DefaultRetryTimeout = 10 * time.Second
+
+// DefaultHeartbeatMessage defines message of heartbeat log records written by
+// stream with set heartbeat interval.
+const DefaultHeartbeatMessage = "heartbeat"
+
+// lengthPrefixSize defines size of length prefix written by
+// the StreamHandlerLengthPrefixed and StreamHandlerProto stream handlers.
+const lengthPrefixSize = 4
+
+// StreamHandler defines a custom stream handler for writing log records with
+// writer. Line ending set for stream can be obtained from provided writer using
+// the GetLineEnding function.
 type StreamHandler func(writer io.Writer, record *Record, formatter *Formatter) error
 
+// lineEndingWriter represents a writer provided to stream handler with line
+// ending set for stream.
+type lineEndingWriter struct {
+	io.Writer
+	lineEnding string
+}
+
 // Opener implements Open method.
 type Opener interface {
 	Open() (io.WriteCloser, error)
 }
 
+// keepAliveConn represents a network connection with TCP keep-alive, like for
+// example net.TCPConn.
+type keepAliveConn interface {
+	SetKeepAlive(keepAlive bool) error
+	SetKeepAlivePeriod(period time.Duration) error
+}
+
+// deadlineWriter represents a writer with write deadline, like for example
+// net.Conn or os.File.
+type deadlineWriter interface {
+	SetWriteDeadline(t time.Time) error
+}
+
 // A Stream represents a log handler object for logging messages using stream
 // object.
 type Stream struct {
+	HandlerBase
 	writer       io.Writer
 	closer       io.Closer
-	formatter    *Formatter
 	mutex        sync.RWMutex
 	opener       Opener
-	minimumLevel int
-	maximumLevel int
 	reopen       bool
-	isDisabled   bool
 	handler      StreamHandler
+	encoder      Encoder
+	writerLevel  int
+	lineEnding   string
+	writeTimeout time.Duration
+	keepAlive    time.Duration
+	heartbeat    chan struct{}
+	closing      chan struct{}
+	interval     time.Duration
+	lastWrite    time.Time
+	buffer       bytes.Buffer
+	profile      profileCounters
+	attempts     int
+	backoff      time.Duration
+	retryTimeout time.Duration
 }
 
-// NewStream creates a new Stream log handler object.
+// NewStream creates a new Stream log handler object. Created stream has no
+// writer and emitting of log records returns an error until writer is set by
+// the SetWriter, SetWriteCloser or SetOpener methods. Use the NewWriter or
+// NewWriteCloser functions to create a ready to use stream.
 func NewStream() *Stream {
-	return &Stream{
-		formatter:    NewFormatter(),
-		minimumLevel: MinimumLevel,
-		maximumLevel: MaximumLevel,
+	s := &Stream{
+		HandlerBase: HandlerBase{
+			formatter: NewFormatter(),
+		},
 		handler:      StreamHandlerDefault,
+		writerLevel:  DefaultWriterLevel,
+		lineEnding:   DefaultLineEnding,
+		retryTimeout: DefaultRetryTimeout,
+	}
+
+	s.owner = s
+
+	return s
+}
+
+// NewWriter creates a new Stream log handler object that writes log records
+// to provided writer. Provided writer is not closed by stream.
+func NewWriter(writer io.Writer) *Stream {
+	s := NewStream()
+
+	s.writer = writer
+
+	return s
+}
+
+// NewWriteCloser creates a new Stream log handler object that writes log
+// records to provided writer. Provided writer is closed when stream is closed.
+func NewWriteCloser(writeCloser io.WriteCloser) *Stream {
+	s := NewStream()
+
+	s.writer = writeCloser
+	s.closer = writeCloser
+
+	return s
+}
+
+// newStreamFromOptions creates a new Stream log handler object configured with
+// provided options: "writer" with io.Writer or io.WriteCloser that is closed
+// when stream is closed, "writeTimeout", "keepAlive", "heartbeat",
+// "retryAttempts", "retryBackoff" and "retryTimeout".
+func newStreamFromOptions(options HandlerOptions) (Handler, error) {
+	s := NewStream()
+	o := newHandlerOptions(options)
+
+	if value, ok := o.lookup("writer"); ok {
+		switch writer := value.(type) {
+		case io.WriteCloser:
+			s.writer = writer
+			s.closer = writer
+		case io.Writer:
+			s.writer = writer
+		default:
+			o.invalid("writer", value)
+		}
+	}
+
+	o.setDuration("writeTimeout", func(timeout time.Duration) { s.SetWriteTimeout(timeout) })
+	o.setDuration("keepAlive", func(period time.Duration) { s.SetKeepAlive(period) })
+	o.setDuration("heartbeat", func(interval time.Duration) { s.SetHeartbeat(interval) })
+	o.setRetry(s)
+
+	if err := o.err(); err != nil {
+		return nil, err
 	}
+
+	return s, nil
 }
 
 // Lock locks stream.
@@ -85,6 +207,330 @@ func (s *Stream) SetStreamHandler(handler StreamHandler) *Stream {
 	return s
 }
 
+// SetEncoder sets encoder used to encode log records instead of stream handler
+// and formatter. Encoded log record is written with line ending set for
+// stream. Setting it to nil restores using of stream handler, on default it
+// is StreamHandlerDefault that writes log records like the TextEncoder with
+// stream formatter.
+func (s *Stream) SetEncoder(encoder Encoder) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.encoder = encoder
+
+	return s
+}
+
+// GetEncoder returns encoder used to encode log records. It returns nil if
+// stream uses stream handler.
+func (s *Stream) GetEncoder() Encoder {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.encoder
+}
+
+// SetLineEnding sets line ending written after every log record by built-in
+// stream handlers. On default it is DefaultLineEnding. Empty line ending
+// disables writing line ending.
+func (s *Stream) SetLineEnding(lineEnding string) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lineEnding = lineEnding
+
+	return s
+}
+
+// GetLineEnding returns line ending written after every log record.
+func (s *Stream) GetLineEnding() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lineEnding
+}
+
+// SetWriteTimeout sets write timeout of I/O stream. Write deadline is set
+// before every write if writer implements the SetWriteDeadline method, like
+// for example net.Conn, so that a hung network destination cannot block
+// logger worker thread indefinitely. On timeout an error is returned and
+// stream is closed and opened again by the opener before emitting the next
+// log record. Zero or negative write timeout disables it and it is the
+// default.
+func (s *Stream) SetWriteTimeout(timeout time.Duration) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if timeout < 0 {
+		timeout = 0
+	}
+
+	if (timeout == 0) && (s.writeTimeout != 0) {
+		if writer, ok := s.writer.(deadlineWriter); ok {
+			_ = writer.SetWriteDeadline(time.Time{})
+		}
+	}
+
+	s.writeTimeout = timeout
+
+	return s
+}
+
+// GetWriteTimeout returns write timeout of I/O stream.
+func (s *Stream) GetWriteTimeout() time.Duration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.writeTimeout
+}
+
+// SetRetry sets number of retry attempts of failed writes and initial backoff
+// duration between them. Backoff duration is doubled after every attempt. Stream
+// opened by the opener, like for example network connection, is closed and
+// opened again before every attempt. After the last failed attempt an error
+// is returned. Retrying stops earlier when the next backoff would exceed retry
+// timeout set by the SetRetryTimeout method, so that persistently unavailable
+// destination doesn't stall logger worker thread indefinitely. Stream is not
+// locked during backoff and closing of stream stops retrying. After a short
+// write only not written data is written again. Zero or negative number of
+// attempts disables it and it is the default.
+func (s *Stream) SetRetry(attempts int, backoff time.Duration) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if attempts < 0 {
+		attempts = 0
+	}
+
+	if backoff < 0 {
+		backoff = 0
+	}
+
+	s.attempts = attempts
+	s.backoff = backoff
+
+	return s
+}
+
+// GetRetry returns number of retry attempts of failed writes and initial
+// backoff duration between them.
+func (s *Stream) GetRetry() (attempts int, backoff time.Duration) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.attempts, s.backoff
+}
+
+// SetRetryTimeout sets maximum total duration of retrying failed write
+// measured from the first failed attempt. Zero or negative value sets it to
+// DefaultRetryTimeout.
+func (s *Stream) SetRetryTimeout(timeout time.Duration) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if timeout <= 0 {
+		timeout = DefaultRetryTimeout
+	}
+
+	s.retryTimeout = timeout
+
+	return s
+}
+
+// GetRetryTimeout returns maximum total duration of retrying failed write.
+func (s *Stream) GetRetryTimeout() time.Duration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.retryTimeout
+}
+
+// Profile returns time spent on formatting and writing of log records since
+// profiling was enabled by the EnableProfiling function. Batch of log records
+// is profiled as a single format and write operation.
+func (s *Stream) Profile() Profile {
+	return s.profile.get()
+}
+
+// SetKeepAlive sets TCP keep-alive period of network connection, so that idle
+// connection is not dropped by firewalls between bursts of log records. It is
+// applied to opened writer and to writers opened later by the opener if they
+// support TCP keep-alive, like for example net.TCPConn. Zero keep-alive
+// period keeps system default and it is the default. Negative keep-alive
+// period disables TCP keep-alive.
+func (s *Stream) SetKeepAlive(period time.Duration) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keepAlive = period
+
+	if err := s.applyKeepAlive(); err != nil {
+		printError(err)
+	}
+
+	return s
+}
+
+// GetKeepAlive returns TCP keep-alive period of network connection.
+func (s *Stream) GetKeepAlive() time.Duration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.keepAlive
+}
+
+// applyKeepAlive sets TCP keep-alive to writer. Caller must hold the stream
+// lock.
+func (s *Stream) applyKeepAlive() error {
+	conn, ok := s.writer.(keepAliveConn)
+
+	if !ok || (s.keepAlive == 0) {
+		return nil
+	}
+
+	if s.keepAlive < 0 {
+		if err := conn.SetKeepAlive(false); err != nil {
+			return NewRuntimeError("cannot set stream keep-alive", err)
+		}
+
+		return nil
+	}
+
+	if err := conn.SetKeepAlive(true); err != nil {
+		return NewRuntimeError("cannot set stream keep-alive", err)
+	}
+
+	if err := conn.SetKeepAlivePeriod(s.keepAlive); err != nil {
+		return NewRuntimeError("cannot set stream keep-alive", err)
+	}
+
+	return nil
+}
+
+// SetHeartbeat sets heartbeat interval. Heartbeat log record with the
+// DefaultHeartbeatMessage message and the InfoLevel log level is written to
+// I/O stream when nothing was written to it during heartbeat interval. It
+// keeps connection with network destination active and broken connection is
+// detected before emitting the next log record. Heartbeat log records are
+// formatted like other log records. Zero or negative heartbeat interval or
+// closing of stream stops writing of heartbeat log records and it is the
+// default.
+func (s *Stream) SetHeartbeat(interval time.Duration) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stopHeartbeat()
+
+	if s.closing != nil {
+		close(s.closing)
+		s.closing = nil
+	}
+
+	if interval > 0 {
+		s.interval = interval
+		s.heartbeat = make(chan struct{})
+
+		go s.runHeartbeat(s.heartbeat, interval)
+	}
+
+	return s
+}
+
+// GetHeartbeat returns heartbeat interval. It returns zero if writing of
+// heartbeat log records is stopped.
+func (s *Stream) GetHeartbeat() time.Duration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.interval
+}
+
+// runHeartbeat writes heartbeat log records periodically until provided done
+// channel is closed.
+func (s *Stream) runHeartbeat(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := s.emitHeartbeat(done, interval); err != nil {
+				printError(err)
+			}
+		}
+	}
+}
+
+// emitHeartbeat writes heartbeat log record if nothing was written to I/O
+// stream during provided heartbeat interval.
+func (s *Stream) emitHeartbeat(done <-chan struct{}, interval time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	if !s.IsEnabled() || (time.Since(s.lastWrite) < interval) {
+		return nil
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	if s.writer == nil {
+		return nil
+	}
+
+	s.buffer.Reset()
+
+	if err := s.write(&s.buffer, newHeartbeatRecord()); err != nil {
+		return NewRuntimeError("cannot write heartbeat to stream", err)
+	}
+
+	if _, err := s.writeStream(s.buffer.Bytes()); err != nil {
+		return NewRuntimeError("cannot write heartbeat to stream", err)
+	}
+
+	return nil
+}
+
+// stopHeartbeat stops writing of heartbeat log records. Caller must hold the
+// stream lock.
+func (s *Stream) stopHeartbeat() {
+	if s.heartbeat != nil {
+		close(s.heartbeat)
+		s.heartbeat = nil
+		s.interval = 0
+	}
+}
+
+// newHeartbeatRecord creates a new heartbeat log record.
+func newHeartbeatRecord() *Record {
+	now := time.Now()
+
+	record := &Record{
+		Type:    DefaultTypeName,
+		Name:    filepath.Base(os.Args[0]),
+		Time:    now,
+		Message: DefaultHeartbeatMessage,
+		Level: Level{
+			Value: InfoLevel,
+			Name:  InfoName,
+		},
+	}
+
+	record.Timestamp.Created = now.Format(time.RFC3339)
+	record.Hostname, _ = getHostname()
+
+	return record
+}
+
 // SetWriter sets new writer to stream.
 func (s *Stream) SetWriter(writer io.Writer) error {
 	s.mutex.Lock()
@@ -141,130 +587,207 @@ func (s *Stream) SetOpener(opener Opener) *Stream {
 	return s
 }
 
-// Reopen reopens stream.
-func (s *Stream) Reopen() *Stream {
+// Reopen reopens stream. Stream is closed and opened again by the opener
+// before emitting the next log record.
+func (s *Stream) Reopen() Handler {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.reopen = true
 
 	return s
 }
 
-// Enable enables log handler.
-func (s *Stream) Enable() Handler {
+// Emit logs messages from logger using I/O stream. Log record is written by
+// stream handler or encoder to an internal buffer and it is written to I/O
+// stream with a single write call. Log records from different log handlers
+// sharing the same I/O stream, like for example os.Stdout, are not interleaved
+// and log record that cannot be written by stream handler is discarded.
+func (s *Stream) Emit(record *Record) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.isDisabled = false
+	s.buffer.Reset()
 
-	return s
+	start := s.profile.start()
+
+	if err := s.write(&s.buffer, record); err != nil {
+		return NewRuntimeError("cannot write to stream", err)
+	}
+
+	start = s.profile.addFormat(start)
+	err := s.writeRetry(s.buffer.Bytes())
+	s.profile.addWrite(start)
+
+	return err
 }
 
-// Disable disabled log handler.
-func (s *Stream) Disable() Handler {
+// emitBatch logs multiple messages from logger using I/O stream. Formatted log
+// records are concatenated and written to I/O stream with a single write call.
+// Log record that cannot be formatted doesn't prevent writing of others and
+// its partially written data is discarded.
+func (s *Stream) emitBatch(records []*Record) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.isDisabled = true
+	var buffer bytes.Buffer
 
-	return s
-}
+	var failed error
 
-// IsEnabled returns if log handler is enabled.
-func (s *Stream) IsEnabled() bool {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	start := s.profile.start()
 
-	return !s.isDisabled
-}
+	for _, record := range records {
+		length := buffer.Len()
 
-// SetFormatter sets Formatter.
-func (s *Stream) SetFormatter(formatter *Formatter) Handler {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+		if err := s.write(&buffer, record); err != nil {
+			buffer.Truncate(length)
 
-	s.formatter = formatter
+			if failed == nil {
+				failed = NewRuntimeError("cannot write to stream", err)
+			}
+		}
+	}
 
-	return s
-}
+	start = s.profile.addFormat(start)
+	err := s.writeRetry(buffer.Bytes())
+	s.profile.addWrite(start)
 
-// GetFormatter returns Formatter.
-func (s *Stream) GetFormatter() *Formatter {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
 
-	return s.formatter
+	return failed
 }
 
-// SetLevel sets log level.
-func (s *Stream) SetLevel(level int) Handler {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// writeRetry opens I/O stream if needed and it writes provided data to it.
+// Failed open or write is retried with exponential backoff according to
+// the SetRetry and SetRetryTimeout methods. Only not written data is written
+// again after a short write. The stream lock is released between attempts, so
+// other log records and the Close method are not blocked by backoff, and
+// the Close method stops retrying. Caller must hold the stream lock.
+func (s *Stream) writeRetry(data []byte) error {
+	written, err := s.openWrite(data)
+
+	if (err == nil) || (s.attempts <= 0) {
+		return err
+	}
 
-	s.minimumLevel = level
-	s.maximumLevel = level
+	data = append([]byte(nil), data[written:]...)
+	deadline := time.Now().Add(s.retryTimeout)
+	backoff := s.backoff
 
-	return s
-}
+	for attempt := 0; attempt < s.attempts; attempt++ {
+		if time.Now().Add(backoff).After(deadline) || !s.waitRetry(backoff) {
+			break
+		}
 
-// SetMinimumLevel sets minimum log level.
-func (s *Stream) SetMinimumLevel(level int) Handler {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+		backoff *= 2
 
-	s.minimumLevel = level
+		if s.opener != nil {
+			s.reopen = true
+		}
 
-	return s
-}
+		if written, err = s.openWrite(data); err == nil {
+			return nil
+		}
 
-// GetMinimumLevel returns minimum log level.
-func (s *Stream) GetMinimumLevel() int {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+		data = data[written:]
+	}
 
-	return s.minimumLevel
+	return err
 }
 
-// SetMaximumLevel sets maximum log level.
-func (s *Stream) SetMaximumLevel(level int) Handler {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// waitRetry waits provided backoff duration without holding the stream lock.
+// It returns false if the stream was closed in the meantime. Caller must hold
+// the stream lock.
+func (s *Stream) waitRetry(backoff time.Duration) bool {
+	if s.closing == nil {
+		s.closing = make(chan struct{})
+	}
 
-	s.maximumLevel = level
+	closing := s.closing
+	timer := time.NewTimer(backoff)
 
-	return s
-}
+	s.mutex.Unlock()
+	defer s.mutex.Lock()
 
-// GetMaximumLevel returns maximum log level.
-func (s *Stream) GetMaximumLevel() int {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	defer timer.Stop()
 
-	return s.maximumLevel
+	select {
+	case <-timer.C:
+		return true
+	case <-closing:
+		return false
+	}
 }
 
-// SetLevelRange sets minimum and maximum log level values.
-func (s *Stream) SetLevelRange(min, max int) Handler {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// openWrite opens I/O stream if needed and it writes provided data to it.
+// It returns number of written bytes. Caller must hold the stream lock.
+func (s *Stream) openWrite(data []byte) (int, error) {
+	if err := s.open(); err != nil {
+		return 0, err
+	}
 
-	s.minimumLevel = min
-	s.maximumLevel = max
+	if s.writer == nil {
+		return 0, NewRuntimeError("cannot write to stream without writer")
+	}
 
-	return s
+	return s.writeStream(data)
 }
 
-// GetLevelRange returns minimum and maximum log level values.
-func (s *Stream) GetLevelRange() (min, max int) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+// writeStream writes provided data to I/O stream with write timeout. Stream
+// is reopened before the next write after write timeout. It returns number of
+// written bytes. Caller must hold the stream lock.
+func (s *Stream) writeStream(data []byte) (int, error) {
+	if s.writeTimeout > 0 {
+		if writer, ok := s.writer.(deadlineWriter); ok {
+			if err := writer.SetWriteDeadline(time.Now().Add(s.writeTimeout)); err != nil {
+				return 0, NewRuntimeError("cannot set stream write deadline", err)
+			}
+		}
+	}
+
+	written, err := s.writer.Write(data)
+
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			s.reopen = true
+		}
+
+		return written, NewRuntimeError("cannot write to stream", err)
+	}
 
-	return s.minimumLevel, s.maximumLevel
+	s.lastWrite = time.Now()
+
+	return written, nil
 }
 
-// Emit logs messages from logger using I/O stream.
-func (s *Stream) Emit(record *Record) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// write writes provided log record to provided writer using encoder or stream
+// handler. Caller must hold the stream lock.
+func (s *Stream) write(writer io.Writer, record *Record) error {
+	if s.encoder == nil {
+		return s.handler(&lineEndingWriter{
+			Writer:     writer,
+			lineEnding: s.lineEnding,
+		}, record, s.GetFormatter())
+	}
+
+	data, err := s.encoder.Encode(record)
+
+	if err != nil {
+		return NewRuntimeError("cannot encode record", err)
+	}
+
+	if _, err := writer.Write(append(data, s.lineEnding...)); err != nil {
+		return NewRuntimeError("cannot write to stream", err)
+	}
+
+	return nil
+}
 
+// open reopens or opens I/O stream if needed. Caller must hold the stream
+// lock.
+func (s *Stream) open() error {
 	if s.reopen {
 		if s.closer != nil {
 			err := s.closer.Close()
@@ -289,22 +812,27 @@ func (s *Stream) Emit(record *Record) error {
 
 		s.writer = writer
 		s.closer = writer
-	}
 
-	if s.writer != nil {
-		if err := s.handler(s.writer, record, s.formatter); err != nil {
-			return NewRuntimeError("cannot write to stream", err)
+		if err = s.applyKeepAlive(); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// Close closes I/O stream.
+// Close closes I/O stream. It stops writing of heartbeat log records.
 func (s *Stream) Close() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	s.stopHeartbeat()
+
+	if s.closing != nil {
+		close(s.closing)
+		s.closing = nil
+	}
+
 	if s.closer != nil {
 		err := s.closer.Close()
 
@@ -319,6 +847,16 @@ func (s *Stream) Close() error {
 	return nil
 }
 
+// GetLineEnding returns line ending set for stream from writer provided to
+// stream handler. It returns DefaultLineEnding for other writers.
+func GetLineEnding(writer io.Writer) string {
+	if lineWriter, ok := writer.(*lineEndingWriter); ok {
+		return lineWriter.lineEnding
+	}
+
+	return DefaultLineEnding
+}
+
 // StreamHandlerDefault is a default stream handler for writing log records to stream.
 func StreamHandlerDefault(writer io.Writer, record *Record, formatter *Formatter) error {
 	message, err := formatter.Format(record)
@@ -327,7 +865,7 @@ func StreamHandlerDefault(writer io.Writer, record *Record, formatter *Formatter
 		return NewRuntimeError("cannot format record", err)
 	}
 
-	if _, err := fmt.Fprintln(writer, message); err != nil {
+	if _, err := io.WriteString(writer, message+GetLineEnding(writer)); err != nil {
 		return NewRuntimeError("cannot write to stream", err)
 	}
 
@@ -342,7 +880,73 @@ func StreamHandlerNDJSON(writer io.Writer, record *Record, _ *Formatter) error {
 		return NewRuntimeError("cannot format record", err)
 	}
 
-	if _, err := fmt.Fprintln(writer, string(bytes)); err != nil {
+	if _, err := writer.Write(append(bytes, GetLineEnding(writer)...)); err != nil {
+		return NewRuntimeError("cannot write to stream", err)
+	}
+
+	return nil
+}
+
+// StreamHandlerMsgPack handles writing log records in the MessagePack format.
+// It is a compact binary alternative to the StreamHandlerNDJSON stream
+// handler. MessagePack values are self-delimiting and log records are written
+// one after another without the line ending.
+func StreamHandlerMsgPack(writer io.Writer, record *Record, _ *Formatter) error {
+	bytes, err := record.ToMsgPack()
+
+	if err != nil {
+		return NewRuntimeError("cannot format record", err)
+	}
+
+	if _, err := writer.Write(bytes); err != nil {
+		return NewRuntimeError("cannot write to stream", err)
+	}
+
+	return nil
+}
+
+// StreamHandlerLengthPrefixed handles writing formatted log records prefixed
+// with their length encoded as a 4-byte big-endian unsigned integer instead of
+// the line ending. It is useful for framed protocols where formatted log
+// records may contain line endings.
+func StreamHandlerLengthPrefixed(writer io.Writer, record *Record, formatter *Formatter) error {
+	message, err := formatter.Format(record)
+
+	if err != nil {
+		return NewRuntimeError("cannot format record", err)
+	}
+
+	return writeLengthPrefixed(writer, []byte(message))
+}
+
+// StreamHandlerProto handles writing log records in the protocol buffers
+// format defined by the logpb package. Protocol buffers messages are not
+// self-delimiting and each log record is prefixed with its length encoded as
+// a 4-byte big-endian unsigned integer like in the StreamHandlerLengthPrefixed
+// stream handler.
+func StreamHandlerProto(writer io.Writer, record *Record, _ *Formatter) error {
+	bytes, err := record.ToProto()
+
+	if err != nil {
+		return NewRuntimeError("cannot format record", err)
+	}
+
+	return writeLengthPrefixed(writer, bytes)
+}
+
+// writeLengthPrefixed writes data prefixed with its length encoded as a 4-byte
+// big-endian unsigned integer.
+func writeLengthPrefixed(writer io.Writer, data []byte) error {
+	if uint64(len(data)) > math.MaxUint32 {
+		return NewRuntimeError("cannot write record, message is too long", len(data))
+	}
+
+	frame := make([]byte, lengthPrefixSize+len(data))
+
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[lengthPrefixSize:], data)
+
+	if _, err := writer.Write(frame); err != nil {
 		return NewRuntimeError("cannot write to stream", err)
 	}
 
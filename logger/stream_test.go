@@ -0,0 +1,620 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+	"gitlab.com/tymonx/go-logger/logger/logpb"
+)
+
+func TestStreamSetLineEnding(test *testing.T) {
+	for _, lineEnding := range []string{"\n", "\r\n", ""} {
+		buffer := logger.NewBuffer().SetLineEnding(lineEnding)
+		buffer.GetFormatter().SetFormat("{message}")
+
+		log := logger.New().SetHandler("buffer", buffer).SetSynchronous(true)
+
+		log.Info("first")
+		log.Info("second")
+
+		if want := "first" + lineEnding + "second" + lineEnding; buffer.String() != want {
+			test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+		}
+	}
+}
+
+func TestStreamHandlerLengthPrefixed(test *testing.T) {
+	buffer := logger.NewBuffer().SetStreamHandler(logger.StreamHandlerLengthPrefixed)
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer).SetSynchronous(true)
+
+	log.Info("multi\nline")
+
+	if want := "\x00\x00\x00\x0amulti\nline"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func decodeLengthPrefixed(data []byte) ([]string, error) {
+	var messages []string
+
+	for len(data) != 0 {
+		if len(data) < 4 {
+			return nil, testError
+		}
+
+		length := int(binary.BigEndian.Uint32(data))
+		data = data[4:]
+
+		if len(data) < length {
+			return nil, testError
+		}
+
+		messages = append(messages, string(data[:length]))
+		data = data[length:]
+	}
+
+	return messages, nil
+}
+
+func TestStreamHandlerLengthPrefixedRoundTrip(test *testing.T) {
+	want := []string{"first", "multi\nline\n", "", "{p} unicode \u2713"}
+
+	buffer := logger.NewBuffer().SetStreamHandler(logger.StreamHandlerLengthPrefixed)
+	buffer.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("buffer", buffer).SetSynchronous(true)
+
+	for _, message := range want {
+		log.Info(message)
+	}
+
+	got, err := decodeLengthPrefixed(buffer.Bytes())
+
+	if err != nil {
+		test.Fatal("decodeLengthPrefixed() returns an unexpected error", err)
+	}
+
+	if len(got) != len(want) {
+		test.Fatalf("len(messages) = %d; want %d", len(got), len(want))
+	}
+
+	for index := range want {
+		if got[index] != want[index] {
+			test.Errorf("messages[%d] = %q; want %q", index, got[index], want[index])
+		}
+	}
+}
+
+func TestStreamHandlerMsgPack(test *testing.T) {
+	msgpack := logger.NewBuffer().SetStreamHandler(logger.StreamHandlerMsgPack)
+	ndjson := logger.NewBuffer().SetStreamHandler(logger.StreamHandlerNDJSON)
+
+	log := logger.New().SetSynchronous(true).SetHandlers(logger.Handlers{
+		"msgpack": msgpack,
+		"ndjson":  ndjson,
+	})
+
+	log.Info("Message {p} {p}", 1, "two", logger.Named{"key": []int{3}})
+
+	if data := msgpack.Bytes(); len(data) >= len(ndjson.Bytes()) {
+		test.Errorf("len(msgpack) = %d; want less than len(ndjson) = %d", len(data), len(ndjson.Bytes()))
+	}
+
+	var got, want logger.Record
+
+	if err := got.FromMsgPack(msgpack.Bytes()); err != nil {
+		test.Fatal("FromMsgPack() returns an unexpected error", err)
+	}
+
+	if err := want.FromJSON(ndjson.Bytes()); err != nil {
+		test.Fatal("FromJSON() returns an unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		test.Errorf("FromMsgPack() = %#v; want %#v", got, want)
+	}
+
+	if len(got.Arguments) != 3 {
+		test.Errorf("len(Arguments) = %d; want 3", len(got.Arguments))
+	}
+}
+
+func TestStreamHandlerProto(test *testing.T) {
+	buffer := logger.NewBuffer().SetStreamHandler(logger.StreamHandlerProto)
+
+	log := logger.New().SetName("proto").SetSynchronous(true).SetHandler("buffer", buffer)
+
+	log.Warning("Message {p}", 1, logger.Named{"key": "value", "count": 2})
+	log.Info("second")
+
+	frames, err := decodeLengthPrefixed(buffer.Bytes())
+
+	if err != nil {
+		test.Fatal("decodeLengthPrefixed() returns an unexpected error", err)
+	}
+
+	if len(frames) != 2 {
+		test.Fatalf("len(frames) = %d; want 2", len(frames))
+	}
+
+	var record logpb.Record
+
+	if err := record.Unmarshal([]byte(frames[0])); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if (record.ID == "") || (record.Name != "proto") || (record.TimeUnixNano == 0) {
+		test.Errorf("Unmarshal() = %+v; want ID, name and time", record)
+	}
+
+	if want := (logpb.Level{Value: logger.WarningLevel, Name: "warning"}); record.Level != want {
+		test.Errorf("Level = %+v; want %+v", record.Level, want)
+	}
+
+	if want := "Message 1"; !strings.HasPrefix(record.Message, want) {
+		test.Errorf("Message = %q; want prefix %q", record.Message, want)
+	}
+
+	if (record.File.Line == 0) || !strings.HasSuffix(record.File.Name, "stream_test.go") {
+		test.Errorf("File = %+v; want caller source", record.File)
+	}
+
+	want := map[string]logpb.Value{
+		"key":   {Kind: logpb.KindString, String: "value"},
+		"count": {Kind: logpb.KindInt, Int: 2},
+	}
+
+	if !reflect.DeepEqual(record.Fields, want) {
+		test.Errorf("Fields = %+v; want %+v", record.Fields, want)
+	}
+
+	if err := record.Unmarshal([]byte(frames[1])); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if (record.Message != "second") || (record.Fields != nil) {
+		test.Errorf("Unmarshal() = %+v; want second message without fields", record)
+	}
+}
+
+func TestNewWriter(test *testing.T) {
+	var buffer bytes.Buffer
+
+	stream := logger.NewWriter(&buffer)
+	stream.GetFormatter().SetFormat("{message}")
+
+	log := logger.New().SetHandler("writer", stream).SetSynchronous(true)
+
+	log.Info("first")
+
+	if err := log.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	if want := "first\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestNewWriteCloser(test *testing.T) {
+	closer := failingCloser{err: testError}
+
+	if err := logger.NewWriteCloser(closer).Close(); !errors.Is(err, testError) {
+		test.Errorf("Close() = %v; want %v", err, testError)
+	}
+}
+
+func TestStreamWithoutWriter(test *testing.T) {
+	var errs []error
+
+	log := logger.New().SetHandler("stream", logger.NewStream()).SetSynchronous(true)
+
+	log.SetInternalErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	log.Info(testMessage)
+
+	if len(errs) != 1 {
+		test.Fatalf("len(errs) = %d; want 1", len(errs))
+	}
+
+	if !strings.Contains(errs[0].Error(), "cannot write to stream without writer") {
+		test.Error("Error() =", errs[0].Error(), "; want missing writer")
+	}
+}
+
+type chunkWriter struct {
+	mutex  sync.Mutex
+	chunks []string
+}
+
+func (c *chunkWriter) Write(data []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.chunks = append(c.chunks, string(data))
+
+	return len(data), nil
+}
+
+func TestStreamEmitSingleWrite(test *testing.T) {
+	const records = 100
+
+	writer := &chunkWriter{}
+
+	handler := func(writer io.Writer, record *logger.Record, _ *logger.Formatter) error {
+		for _, part := range []string{"begin ", record.Message, " end", "\n"} {
+			if _, err := fmt.Fprint(writer, part); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var wg sync.WaitGroup
+
+	for _, name := range []string{"first", "second"} {
+		log := logger.New().SetSynchronous(true).SetHandler(name, logger.NewWriter(writer).SetStreamHandler(handler))
+
+		wg.Add(1)
+
+		go func(log *logger.Logger, name string) {
+			defer wg.Done()
+
+			for index := 0; index < records; index++ {
+				log.Info(name)
+			}
+		}(log, name)
+	}
+
+	wg.Wait()
+
+	if len(writer.chunks) != 2*records {
+		test.Fatalf("len(chunks) = %d; want %d", len(writer.chunks), 2*records)
+	}
+
+	for _, chunk := range writer.chunks {
+		if (chunk != "begin first end\n") && (chunk != "begin second end\n") {
+			test.Fatalf("chunk = %q; want whole line", chunk)
+		}
+	}
+}
+
+type pipeOpener struct {
+	opened int
+}
+
+func (o *pipeOpener) Open() (io.WriteCloser, error) {
+	o.opened++
+
+	client, server := net.Pipe()
+
+	if o.opened > 1 {
+		go func() {
+			_, _ = io.Copy(io.Discard, server)
+		}()
+	}
+
+	return client, nil
+}
+
+type flakyOpener struct {
+	failures int
+	opened   int
+	buffer   bytes.Buffer
+}
+
+func (o *flakyOpener) Open() (io.WriteCloser, error) {
+	o.opened++
+
+	if o.opened <= o.failures {
+		return nil, testError
+	}
+
+	return &nopCloser{Writer: &o.buffer}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (*nopCloser) Close() error {
+	return nil
+}
+
+func TestStreamSetRetry(test *testing.T) {
+	opener := &flakyOpener{failures: 2}
+
+	stream := logger.NewStream().SetOpener(opener).SetRetry(3, time.Millisecond)
+	stream.GetFormatter().SetFormat("{message}")
+
+	if attempts, backoff := stream.GetRetry(); (attempts != 3) || (backoff != time.Millisecond) {
+		test.Errorf("GetRetry() = %d, %v; want 3, %v", attempts, backoff, time.Millisecond)
+	}
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	if opener.opened != 3 {
+		test.Errorf("opened = %d; want 3", opener.opened)
+	}
+
+	if want := testMessage + "\n"; opener.buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", opener.buffer.String(), want)
+	}
+}
+
+func TestStreamSetRetryExhausted(test *testing.T) {
+	opener := &flakyOpener{failures: 100}
+
+	stream := logger.NewStream().SetOpener(opener).SetRetry(2, time.Millisecond)
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); !errors.Is(err, testError) {
+		test.Error("Emit() =", err, "; want", testError)
+	}
+
+	if opener.opened != 3 {
+		test.Errorf("opened = %d; want 3", opener.opened)
+	}
+
+	opener.opened = 0
+	stream.SetRetry(100, 20*time.Millisecond).SetRetryTimeout(50 * time.Millisecond)
+
+	if timeout := stream.GetRetryTimeout(); timeout != 50*time.Millisecond {
+		test.Errorf("GetRetryTimeout() = %v; want %v", timeout, 50*time.Millisecond)
+	}
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); err == nil {
+		test.Error("Emit() doesn't return an error for unavailable destination")
+	}
+
+	if opener.opened != 2 {
+		test.Errorf("opened = %d; want 2 within retry timeout", opener.opened)
+	}
+
+	if timeout := stream.SetRetryTimeout(0).GetRetryTimeout(); timeout != logger.DefaultRetryTimeout {
+		test.Errorf("GetRetryTimeout() = %v; want %v", timeout, logger.DefaultRetryTimeout)
+	}
+}
+
+func TestStreamSetWriteTimeout(test *testing.T) {
+	opener := &pipeOpener{}
+
+	stream := logger.NewStream().SetOpener(opener).SetWriteTimeout(10 * time.Millisecond)
+
+	defer stream.Close()
+
+	if timeout := stream.GetWriteTimeout(); timeout != 10*time.Millisecond {
+		test.Errorf("GetWriteTimeout() = %v; want %v", timeout, 10*time.Millisecond)
+	}
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); err == nil {
+		test.Fatal("Emit() doesn't return an error for hung writer")
+	}
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	if opener.opened != 2 {
+		test.Errorf("opened = %d; want 2", opener.opened)
+	}
+
+	if timeout := stream.SetWriteTimeout(-time.Second).GetWriteTimeout(); timeout != 0 {
+		test.Errorf("GetWriteTimeout() = %v; want 0", timeout)
+	}
+}
+
+type keepAliveWriter struct {
+	chunkWriter
+	keepAlive bool
+	period    time.Duration
+}
+
+func (w *keepAliveWriter) SetKeepAlive(keepAlive bool) error {
+	w.keepAlive = keepAlive
+	return nil
+}
+
+func (w *keepAliveWriter) SetKeepAlivePeriod(period time.Duration) error {
+	w.period = period
+	return nil
+}
+
+func (w *keepAliveWriter) Close() error {
+	return nil
+}
+
+type keepAliveOpener struct {
+	writer *keepAliveWriter
+}
+
+func (o *keepAliveOpener) Open() (io.WriteCloser, error) {
+	return o.writer, nil
+}
+
+func TestStreamSetKeepAlive(test *testing.T) {
+	writer := &keepAliveWriter{}
+
+	stream := logger.NewStream().SetOpener(&keepAliveOpener{writer: writer}).SetKeepAlive(time.Minute)
+
+	if period := stream.GetKeepAlive(); period != time.Minute {
+		test.Errorf("GetKeepAlive() = %v; want %v", period, time.Minute)
+	}
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	if !writer.keepAlive || (writer.period != time.Minute) {
+		test.Errorf("keep-alive = %t, %v; want true, %v", writer.keepAlive, writer.period, time.Minute)
+	}
+
+	stream.SetKeepAlive(-1)
+
+	if writer.keepAlive {
+		test.Error("keep-alive = true; want false")
+	}
+}
+
+func TestStreamSetHeartbeat(test *testing.T) {
+	writer := &chunkWriter{}
+
+	stream := logger.NewWriter(writer).SetHeartbeat(time.Millisecond)
+
+	stream.GetFormatter().SetFormat("{level}:{message}")
+
+	if interval := stream.GetHeartbeat(); interval != time.Millisecond {
+		test.Errorf("GetHeartbeat() = %v; want %v", interval, time.Millisecond)
+	}
+
+	chunks := func() []string {
+		writer.mutex.Lock()
+		defer writer.mutex.Unlock()
+
+		return append([]string(nil), writer.chunks...)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); len(chunks()) == 0; {
+		if time.Now().After(deadline) {
+			test.Fatal("heartbeat log record is not written")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := stream.Close(); err != nil {
+		test.Fatal("Close() returns an unexpected error", err)
+	}
+
+	written := chunks()
+
+	if want := "info:" + logger.DefaultHeartbeatMessage + "\n"; written[0] != want {
+		test.Errorf("chunk = %q; want %q", written[0], want)
+	}
+
+	if interval := stream.GetHeartbeat(); interval != 0 {
+		test.Errorf("GetHeartbeat() = %v; want 0", interval)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if len(chunks()) != len(written) {
+		test.Error("heartbeat log records are written after closing of stream")
+	}
+}
+
+type shortWriter struct {
+	bytes.Buffer
+	short bool
+}
+
+func (s *shortWriter) Write(data []byte) (int, error) {
+	if !s.short {
+		s.short = true
+		written, _ := s.Buffer.Write(data[:len(data)/2])
+
+		return written, testError
+	}
+
+	return s.Buffer.Write(data)
+}
+
+func TestStreamSetRetryShortWrite(test *testing.T) {
+	writer := &shortWriter{}
+
+	stream := logger.NewWriter(writer).SetRetry(1, time.Millisecond)
+	stream.GetFormatter().SetFormat("{message}")
+
+	if err := stream.Emit(&logger.Record{Message: testMessage}); err != nil {
+		test.Fatal("Emit() returns an unexpected error", err)
+	}
+
+	if want := testMessage + "\n"; writer.String() != want {
+		test.Errorf("String() = %q; want %q", writer.String(), want)
+	}
+}
+
+type signalingWriter struct {
+	written chan struct{}
+}
+
+func (s signalingWriter) Write([]byte) (int, error) {
+	select {
+	case s.written <- struct{}{}:
+	default:
+	}
+
+	return 0, testError
+}
+
+func TestStreamSetRetryClose(test *testing.T) {
+	writer := signalingWriter{written: make(chan struct{}, 1)}
+
+	stream := logger.NewWriter(writer).SetRetry(1, time.Hour).SetRetryTimeout(2 * time.Hour)
+
+	emitted := make(chan error, 1)
+
+	go func() {
+		emitted <- stream.Emit(&logger.Record{Message: testMessage})
+	}()
+
+	<-writer.written
+
+	closed := make(chan struct{})
+
+	go func() {
+		defer close(closed)
+
+		stream.SetFormatter(logger.NewFormatter())
+		stream.GetRetry()
+
+		if err := stream.Close(); err != nil {
+			test.Error("Close() returns an unexpected error", err)
+		}
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		test.Fatal("stream lock is held during retry backoff")
+	}
+
+	select {
+	case err := <-emitted:
+		if !errors.Is(err, testError) {
+			test.Error("Emit() =", err, "; want", testError)
+		}
+	case <-time.After(10 * time.Second):
+		test.Fatal("Close() doesn't stop retrying")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultWriterLevel defines default log level of log records created from
+// data written to writer returned by the AsWriteCloser method.
+const DefaultWriterLevel = InfoLevel
+
+// streamWriter represents a writer that emits every written line as a log
+// record via log handler.
+type streamWriter struct {
+	handler Handler
+	level   int
+	buffer  bytes.Buffer
+	mutex   sync.Mutex
+}
+
+// AsWriteCloser returns writer that emits every written line as a separate
+// log record via stream log handler. Log records are created with log level
+// set by the SetWriterLevel method and written line without the line ending is
+// used as log message. Partial lines are buffered until the line ending is
+// written or the writer is closed. Closing returned writer doesn't close
+// stream.
+func (s *Stream) AsWriteCloser() io.WriteCloser {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return &streamWriter{
+		handler: s,
+		level:   s.writerLevel,
+	}
+}
+
+// SetWriterLevel sets log level of log records created from data written to
+// writer returned by the AsWriteCloser method. On default it is
+// DefaultWriterLevel.
+func (s *Stream) SetWriterLevel(level int) *Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.writerLevel = level
+
+	return s
+}
+
+// Write buffers provided data and it emits all complete lines as log records.
+func (w *streamWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer.Write(data)
+
+	for {
+		index := bytes.IndexByte(w.buffer.Bytes(), '\n')
+
+		if index < 0 {
+			break
+		}
+
+		line := w.buffer.Next(index + 1)
+
+		if err := w.emit(line[:index]); err != nil {
+			return len(data), err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Close emits buffered partial line as log record.
+func (w *streamWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	line := w.buffer.Bytes()
+	w.buffer.Reset()
+
+	return w.emit(line)
+}
+
+// emit emits provided line as log record via log handler.
+func (w *streamWriter) emit(line []byte) error {
+	min, max := w.handler.GetLevelRange()
+
+	if !w.handler.IsEnabled() || (w.level < min) || (w.level > max) {
+		return nil
+	}
+
+	now := time.Now()
+
+	record := &Record{
+		Time:    now,
+		Type:    DefaultTypeName,
+		Message: string(bytes.TrimSuffix(line, []byte("\r"))),
+		Level: Level{
+			Value: w.level,
+			Name:  GetLevelName(w.level),
+		},
+		Timestamp: Timestamp{
+			Created: now.Format(time.RFC3339),
+		},
+	}
+
+	if err := w.handler.Emit(record); err != nil {
+		return NewRuntimeError("cannot emit written line", err)
+	}
+
+	return nil
+}
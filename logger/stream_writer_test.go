@@ -0,0 +1,66 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestStreamAsWriteCloser(test *testing.T) {
+	var buffer bytes.Buffer
+
+	stream := logger.NewStream().SetWriterLevel(logger.WarningLevel)
+	stream.GetFormatter().SetFormat("{level}: {message}")
+
+	if err := stream.SetWriter(&buffer); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	writer := stream.AsWriteCloser()
+
+	for _, data := range []string{"first\nsec", "ond {p}\r\nthi", "rd"} {
+		if _, err := io.WriteString(writer, data); err != nil {
+			test.Error("WriteString() returns an unexpected error", err)
+		}
+	}
+
+	if want := "warning: first\nwarning: second {p}\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+
+	if err := writer.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+
+	if want := "warning: first\nwarning: second {p}\nwarning: third\n"; buffer.String() != want {
+		test.Errorf("buffer.String() = %q; want %q", buffer.String(), want)
+	}
+}
+
+func TestStreamAsWriteCloserError(test *testing.T) {
+	stream := logger.NewStream()
+
+	if err := stream.SetWriter(failingWriter{}); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	if _, err := stream.AsWriteCloser().Write([]byte("line\n")); err == nil {
+		test.Error("Write() doesn't return an error")
+	}
+}
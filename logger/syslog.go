@@ -18,8 +18,13 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 )
 
+func init() { // nolint:gochecknoinits
+	_ = RegisterHandlerFactory("syslog", newSyslogFromOptions)
+}
+
 // These constants define default values for syslog.
 const (
 	DefaultSyslogPort    = 514
@@ -59,11 +64,44 @@ func NewSyslog() *Syslog {
 	return s
 }
 
+// newSyslogFromOptions creates a new Syslog log handler object configured with
+// provided options: "address", "network", "port", "writeTimeout", "keepAlive",
+// "heartbeat", "retryAttempts", "retryBackoff" and "retryTimeout".
+func newSyslogFromOptions(options HandlerOptions) (Handler, error) {
+	s := NewSyslog()
+	o := newHandlerOptions(options)
+
+	o.setString("address", func(address string) { s.SetAddress(address) })
+	o.setString("network", func(network string) { s.SetNetwork(network) })
+	o.setInt("port", func(port int) { s.SetPort(port) })
+	o.setDuration("writeTimeout", func(timeout time.Duration) { s.SetWriteTimeout(timeout) })
+	o.setDuration("keepAlive", func(period time.Duration) { s.SetKeepAlive(period) })
+	o.setDuration("heartbeat", func(interval time.Duration) { s.SetHeartbeat(interval) })
+	o.setRetry(s.stream)
+
+	if err := o.err(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 // Open opens new connection.
 func (s *Syslog) Open() (io.WriteCloser, error) {
 	return net.Dial(s.network, s.address+":"+strconv.Itoa(s.port))
 }
 
+// SetHandlerName sets log handler name.
+func (s *Syslog) SetHandlerName(name string) Handler {
+	s.stream.SetHandlerName(name)
+	return s
+}
+
+// GetHandlerName returns log handler name.
+func (s *Syslog) GetHandlerName() string {
+	return s.stream.GetHandlerName()
+}
+
 // Enable enables log handler.
 func (s *Syslog) Enable() Handler {
 	return s.stream.Enable()
@@ -135,7 +173,7 @@ func (s *Syslog) SetPort(port int) *Syslog {
 
 	if s.port != port {
 		s.port = port
-		s.stream.Reopen()
+		s.stream.reopen = true
 	}
 
 	return s
@@ -161,7 +199,7 @@ func (s *Syslog) SetNetwork(network string) *Syslog {
 
 	if s.network != network {
 		s.network = network
-		s.stream.Reopen()
+		s.stream.reopen = true
 	}
 
 	return s
@@ -188,7 +226,7 @@ func (s *Syslog) SetAddress(address string) *Syslog {
 
 	if s.address != address {
 		s.address = address
-		s.stream.Reopen()
+		s.stream.reopen = true
 	}
 
 	return s
@@ -200,7 +238,81 @@ func (s *Syslog) GetAddress() string {
 	s.stream.RLock()
 	defer s.stream.RUnlock()
 
-	return s.network
+	return s.address
+}
+
+// SetWriteTimeout sets write timeout of connection like the Stream
+// SetWriteTimeout method. On timeout connection is opened again before
+// emitting the next log record.
+func (s *Syslog) SetWriteTimeout(timeout time.Duration) *Syslog {
+	s.stream.SetWriteTimeout(timeout)
+
+	return s
+}
+
+// GetWriteTimeout returns write timeout of connection.
+func (s *Syslog) GetWriteTimeout() time.Duration {
+	return s.stream.GetWriteTimeout()
+}
+
+// SetKeepAlive sets TCP keep-alive period of connection like the Stream
+// SetKeepAlive method.
+func (s *Syslog) SetKeepAlive(period time.Duration) *Syslog {
+	s.stream.SetKeepAlive(period)
+
+	return s
+}
+
+// GetKeepAlive returns TCP keep-alive period of connection.
+func (s *Syslog) GetKeepAlive() time.Duration {
+	return s.stream.GetKeepAlive()
+}
+
+// SetRetry sets number of retry attempts of failed writes and initial backoff
+// duration between them like the Stream SetRetry method. Connection to Syslog server
+// is opened again before every attempt.
+func (s *Syslog) SetRetry(attempts int, backoff time.Duration) *Syslog {
+	s.stream.SetRetry(attempts, backoff)
+
+	return s
+}
+
+// GetRetry returns number of retry attempts of failed writes and initial
+// backoff duration between them.
+func (s *Syslog) GetRetry() (attempts int, backoff time.Duration) {
+	return s.stream.GetRetry()
+}
+
+// SetRetryTimeout sets maximum total duration of retrying failed write like
+// the Stream SetRetryTimeout method.
+func (s *Syslog) SetRetryTimeout(timeout time.Duration) *Syslog {
+	s.stream.SetRetryTimeout(timeout)
+
+	return s
+}
+
+// GetRetryTimeout returns maximum total duration of retrying failed write.
+func (s *Syslog) GetRetryTimeout() time.Duration {
+	return s.stream.GetRetryTimeout()
+}
+
+// SetHeartbeat sets heartbeat interval like the Stream SetHeartbeat method.
+// Heartbeat log records are formatted with the syslog format.
+func (s *Syslog) SetHeartbeat(interval time.Duration) *Syslog {
+	s.stream.SetHeartbeat(interval)
+
+	return s
+}
+
+// GetHeartbeat returns heartbeat interval.
+func (s *Syslog) GetHeartbeat() time.Duration {
+	return s.stream.GetHeartbeat()
+}
+
+// Reopen reconnects to Syslog server before emitting the next log record.
+func (s *Syslog) Reopen() Handler {
+	s.stream.Reopen()
+	return s
 }
 
 // Emit logs messages from Logger to Syslog server.
@@ -210,6 +322,12 @@ func (s *Syslog) Emit(record *Record) error {
 	return s.stream.Emit(record)
 }
 
+// Profile returns time spent on formatting and writing of log records like
+// the Stream Profile method.
+func (s *Syslog) Profile() Profile {
+	return s.stream.Profile()
+}
+
 // Close closes communication to Syslog server.
 func (s *Syslog) Close() error {
 	return s.stream.Close()
@@ -223,25 +341,7 @@ func (s *Syslog) getRecordFuncs(record *Record) FormatterFuncs {
 			return s.version
 		},
 		"syslogPriority": func() int {
-			severities := [8]int{
-				FatalLevel,
-				AlertLevel,
-				CriticalLevel,
-				ErrorLevel,
-				WarningLevel,
-				NoticeLevel,
-				InfoLevel,
-				DebugLevel,
-			}
-
-			severity := 0
-
-			for i, level := range severities {
-				if level <= record.Level.Value {
-					severity = i
-					break
-				}
-			}
+			severity := GetLevelSeverity(record.Level.Value)
 
 			return ((0x1F & s.facility) << 3) | (0x07 & severity)
 		},
@@ -0,0 +1,185 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// TB defines subset of the testing.TB interface used by the Testing log
+// handler. The testing.T, testing.B and testing.F objects implement it.
+type TB interface {
+	Helper()
+	Log(arguments ...interface{})
+	Cleanup(function func())
+}
+
+// A Testing represents a log handler object for logging messages using the
+// Log method of test. It attaches log messages to the test that logs them.
+// Log handler disables itself when test has finished to not log from logger
+// worker thread after test completion.
+type Testing struct {
+	tb       TB
+	finished int32
+	stream   *Stream
+}
+
+// NewTesting creates a new Testing log handler object that logs messages
+// using the Log method of provided test.
+func NewTesting(tb TB) *Testing {
+	t := &Testing{
+		tb:     tb,
+		stream: NewStream(),
+	}
+
+	t.stream.writer = io.Discard
+	t.stream.handler = t.log
+
+	tb.Cleanup(t.finish)
+
+	return t
+}
+
+// ForTesting creates a new synchronous logger with a single Testing log
+// handler that logs messages using the Log method of provided test. Logger
+// is closed when test has finished.
+func ForTesting(tb TB) *Logger {
+	l := New().SetHandlers(Handlers{"testing": NewTesting(tb)}).SetSynchronous(true)
+
+	tb.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	return l
+}
+
+// SetHandlerName sets log handler name.
+func (t *Testing) SetHandlerName(name string) Handler {
+	t.stream.SetHandlerName(name)
+	return t
+}
+
+// GetHandlerName returns log handler name.
+func (t *Testing) GetHandlerName() string {
+	return t.stream.GetHandlerName()
+}
+
+// Enable enables log handler.
+func (t *Testing) Enable() Handler {
+	t.stream.Enable()
+	return t
+}
+
+// Disable disabled log handler.
+func (t *Testing) Disable() Handler {
+	t.stream.Disable()
+	return t
+}
+
+// IsEnabled returns if log handler is enabled. It returns false when test
+// has finished.
+func (t *Testing) IsEnabled() bool {
+	return (atomic.LoadInt32(&t.finished) == 0) && t.stream.IsEnabled()
+}
+
+// SetFormatter sets log formatter.
+func (t *Testing) SetFormatter(formatter *Formatter) Handler {
+	t.stream.SetFormatter(formatter)
+	return t
+}
+
+// GetFormatter returns log formatter.
+func (t *Testing) GetFormatter() *Formatter {
+	return t.stream.GetFormatter()
+}
+
+// SetLevel sets log level.
+func (t *Testing) SetLevel(level int) Handler {
+	t.stream.SetLevel(level)
+	return t
+}
+
+// SetMinimumLevel sets minimum log level.
+func (t *Testing) SetMinimumLevel(level int) Handler {
+	t.stream.SetMinimumLevel(level)
+	return t
+}
+
+// GetMinimumLevel returns minimum log level.
+func (t *Testing) GetMinimumLevel() int {
+	return t.stream.GetMinimumLevel()
+}
+
+// SetMaximumLevel sets maximum log level.
+func (t *Testing) SetMaximumLevel(level int) Handler {
+	t.stream.SetMaximumLevel(level)
+	return t
+}
+
+// GetMaximumLevel returns maximum log level.
+func (t *Testing) GetMaximumLevel() int {
+	return t.stream.GetMaximumLevel()
+}
+
+// SetLevelRange sets minimum and maximum log level values.
+func (t *Testing) SetLevelRange(min, max int) Handler {
+	t.stream.SetLevelRange(min, max)
+	return t
+}
+
+// GetLevelRange returns minimum and maximum log level values.
+func (t *Testing) GetLevelRange() (min, max int) {
+	return t.stream.GetLevelRange()
+}
+
+// Emit logs messages from logger using the Log method of test. It does
+// nothing when test has finished.
+func (t *Testing) Emit(record *Record) error {
+	return t.stream.Emit(record)
+}
+
+// Close closes log handler.
+func (t *Testing) Close() error {
+	return t.stream.Close()
+}
+
+// log formats provided log record and it logs it using the Log method of test.
+// It is called with the stream lock held.
+func (t *Testing) log(_ io.Writer, record *Record, formatter *Formatter) error {
+	if atomic.LoadInt32(&t.finished) != 0 {
+		return nil
+	}
+
+	message, err := formatter.Format(record)
+
+	if err != nil {
+		return NewRuntimeError("cannot format record", err)
+	}
+
+	t.tb.Helper()
+	t.tb.Log(message)
+
+	return nil
+}
+
+// finish disables log handler when test has finished. It waits for log record
+// that is being logged.
+func (t *Testing) finish() {
+	t.stream.Lock()
+	defer t.stream.Unlock()
+
+	atomic.StoreInt32(&t.finished, 1)
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+var _ logger.TB = (testing.TB)(nil)
+
+type fakeTB struct {
+	logs     []string
+	cleanups []func()
+	helpers  int
+	mutex    sync.Mutex
+}
+
+func (f *fakeTB) Helper() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.helpers++
+}
+
+func (f *fakeTB) Log(arguments ...interface{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.logs = append(f.logs, fmt.Sprint(arguments...))
+}
+
+func (f *fakeTB) Cleanup(function func()) {
+	f.cleanups = append(f.cleanups, function)
+}
+
+func (f *fakeTB) finish() {
+	for index := len(f.cleanups) - 1; index >= 0; index-- {
+		f.cleanups[index]()
+	}
+}
+
+func TestTesting(test *testing.T) {
+	tb := new(fakeTB)
+
+	handler := logger.NewTesting(tb)
+	handler.GetFormatter().SetFormat("{level}:{message}")
+
+	log := logger.New().SetHandlers(logger.Handlers{"testing": handler})
+
+	defer log.Close()
+
+	log.Info(testMessage)
+	log.Flush()
+
+	tb.finish()
+
+	log.Info(testMessage)
+	log.Flush()
+
+	if handler.IsEnabled() {
+		test.Error("IsEnabled() = true; want false after test has finished")
+	}
+
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+
+	if (len(tb.logs) != 1) || (tb.logs[0] != "info:"+testMessage) {
+		test.Errorf("logs = %q; want %q", tb.logs, []string{"info:" + testMessage})
+	}
+
+	if tb.helpers != 1 {
+		test.Errorf("Helper() calls = %d; want 1", tb.helpers)
+	}
+}
+
+func TestForTesting(test *testing.T) {
+	tb := new(fakeTB)
+
+	log := logger.ForTesting(tb)
+
+	if !log.IsSynchronous() {
+		test.Error("IsSynchronous() = false; want true")
+	}
+
+	log.Warning(testMessage)
+
+	if len(tb.logs) != 1 {
+		test.Errorf("len(logs) = %d; want 1", len(tb.logs))
+	}
+
+	tb.finish()
+
+	log.Warning(testMessage)
+
+	if len(tb.logs) != 1 {
+		test.Errorf("len(logs) = %d; want 1 after test has finished", len(tb.logs))
+	}
+
+	logger.ForTesting(test).Info("Attached to test")
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("ulid", func() IDGenerator { return NewULID() })
+}
+
+// ulidAlphabet defines Crockford's Base32 alphabet used to encode ULID.
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// These constants define sizes of ULID parts.
+const (
+	ulidEntropySize = 10
+	ulidEncodedSize = 26
+)
+
+// An ULID represents ULID generator. ULID is a 26 characters long identifier
+// with 48-bit timestamp in milliseconds and 80-bit random entropy from
+// crypto/rand. Generated ULIDs sort lexicographically by time. ULIDs generated
+// within the same millisecond by the same generator are monotonically ordered
+// by incrementing entropy of the previous ULID. Time of generated ULID is the
+// current wall time, not time of log record, and ULIDs keep ordering also when
+// wall time goes back.
+type ULID struct {
+	mutex     sync.Mutex
+	timestamp uint64
+	entropy   [ulidEntropySize]byte
+}
+
+// NewULID creates a new ULID object.
+func NewULID() *ULID {
+	return &ULID{}
+}
+
+// Generate generates new ULID.
+func (u *ULID) Generate() (id string, err error) {
+	timestamp := uint64(time.Now().UnixMilli())
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if timestamp > u.timestamp {
+		if _, err := io.ReadFull(rand.Reader, u.entropy[:]); err != nil {
+			return "", NewRuntimeError("cannot generate ULID", err)
+		}
+
+		u.timestamp = timestamp
+	} else if !u.increment() {
+		return "", NewRuntimeError("cannot generate ULID, entropy overflow in the same millisecond")
+	}
+
+	return u.encode(), nil
+}
+
+// increment increments entropy of the previous ULID. It returns false on
+// overflow. Caller must hold the ULID lock.
+func (u *ULID) increment() bool {
+	for index := len(u.entropy) - 1; index >= 0; index-- {
+		u.entropy[index]++
+
+		if u.entropy[index] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encode encodes timestamp and entropy to ULID format. Caller must hold the
+// ULID lock.
+func (u *ULID) encode() string {
+	var data [16]byte
+
+	var buffer [ulidEncodedSize]byte
+
+	binary.BigEndian.PutUint64(data[:8], u.timestamp<<16)
+	copy(data[6:], u.entropy[:])
+
+	high := binary.BigEndian.Uint64(data[:8])
+	low := binary.BigEndian.Uint64(data[8:])
+
+	for index := range buffer {
+		shift := uint(5 * (ulidEncodedSize - 1 - index))
+
+		var value uint64
+
+		switch {
+		case shift >= 64:
+			value = high >> (shift - 64)
+		case shift > 59:
+			value = (low >> shift) | (high << (64 - shift))
+		default:
+			value = low >> shift
+		}
+
+		buffer[index] = ulidAlphabet[value&0x1F]
+	}
+
+	return string(buffer[:])
+}
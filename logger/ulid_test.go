@@ -0,0 +1,127 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func decodeULIDTime(test *testing.T, id string) time.Time {
+	test.Helper()
+
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	var milliseconds int64
+
+	for _, character := range id[:10] {
+		index := strings.IndexRune(alphabet, character)
+
+		if index < 0 {
+			test.Fatalf("ULID %q contains invalid character %q", id, character)
+		}
+
+		milliseconds = milliseconds<<5 | int64(index)
+	}
+
+	return time.UnixMilli(milliseconds)
+}
+
+func TestULID(test *testing.T) {
+	const ids = 10000
+
+	generator := logger.NewULID()
+
+	before := time.Now().Truncate(time.Millisecond)
+
+	previous := ""
+	unique := make(map[string]struct{}, ids)
+
+	for index := 0; index < ids; index++ {
+		id, err := generator.Generate()
+
+		if err != nil {
+			test.Fatal("Generate() returns an unexpected error", err)
+		}
+
+		if len(id) != 26 {
+			test.Fatalf("len(Generate()) = %d; want 26", len(id))
+		}
+
+		if id <= previous {
+			test.Fatalf("Generate() = %q; want greater than %q", id, previous)
+		}
+
+		unique[id] = struct{}{}
+		previous = id
+	}
+
+	after := time.Now()
+
+	if len(unique) != ids {
+		test.Errorf("len(unique) = %d; want %d", len(unique), ids)
+	}
+
+	if created := decodeULIDTime(test, previous); created.Before(before) || created.After(after) {
+		test.Errorf("ULID time = %v; want between %v and %v", created, before, after)
+	}
+}
+
+func TestULIDConcurrent(test *testing.T) {
+	const (
+		goroutines = 8
+		ids        = 1000
+	)
+
+	generator := logger.NewULID()
+	generated := make([][]string, goroutines)
+
+	var wg sync.WaitGroup
+
+	for index := range generated {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+
+			for count := 0; count < ids; count++ {
+				id, _ := generator.Generate()
+				generated[index] = append(generated[index], id)
+			}
+		}(index)
+	}
+
+	wg.Wait()
+
+	unique := make(map[string]struct{}, goroutines*ids)
+
+	for _, list := range generated {
+		for index, id := range list {
+			if (index > 0) && (id <= list[index-1]) {
+				test.Fatalf("Generate() = %q; want greater than %q", id, list[index-1])
+			}
+
+			unique[id] = struct{}{}
+		}
+	}
+
+	if len(unique) != goroutines*ids {
+		test.Errorf("len(unique) = %d; want %d", len(unique), goroutines*ids)
+	}
+}
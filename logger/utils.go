@@ -15,14 +15,85 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"net"
 	"os"
+	"runtime"
 )
 
-// Named is used as named string placeholders for logger functions.
+// Named is used as named string placeholders for logger functions. Named
+// values that are not used in log message are appended to it as key=value
+// pairs sorted by key.
 type Named map[string]interface{}
 
+// Field defines a single named value of the Fields type.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Fields is used as named string placeholders for logger functions like
+// Named but it preserves insertion order of named values. Named values that
+// are not used in log message are appended to it as key=value pairs in
+// the insertion order.
+type Fields []Field
+
+// String returns named values as space separated key=value pairs in
+// the insertion order.
+func (f Fields) String() string {
+	var buffer bytes.Buffer
+
+	for index, field := range f {
+		if index > 0 {
+			buffer.WriteByte(' ')
+		}
+
+		buffer.WriteString(field.String())
+	}
+
+	return buffer.String()
+}
+
+// MarshalJSON returns named values as JSON object with keys in the insertion
+// order.
+func (f Fields) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+
+	for index, field := range f {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(field.Key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(field.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// String returns named value as key=value pair.
+func (f Field) String() string {
+	return f.Key + "=" + fmt.Sprint(f.Value)
+}
+
 // getHostname returns local hostname.
 func getHostname() (string, error) {
 	hostname, err := os.Hostname()
@@ -34,26 +105,34 @@ func getHostname() (string, error) {
 	return hostname, nil
 }
 
-// getAddress returns local IP address.
-func getAddress() (string, error) {
-	connection, err := net.Dial("udp", "8.8.8.8:80")
+// hasCaller returns true if any of functions with provided names is on
+// the call stack of the calling goroutine. Skip call value is counted like for
+// the runtime.Callers function. Whole call stack is searched.
+func hasCaller(skipCall int, names ...string) bool {
+	pcs := make([]uintptr, maximumCallerDepth)
 
-	if err != nil {
-		return "127.0.0.1", NewRuntimeError("cannot connect to primary Google DNS", err)
+	for {
+		if count := runtime.Callers(skipCall, pcs); count < len(pcs) {
+			pcs = pcs[:count]
+			break
+		}
+
+		pcs = make([]uintptr, 2*len(pcs))
 	}
 
-	defer func() {
-		err := connection.Close()
+	frames := runtime.CallersFrames(pcs)
 
-		if err != nil {
-			printError(NewRuntimeError("cannot close UDP connection", err))
-		}
-	}()
+	for {
+		frame, more := frames.Next()
 
-	return connection.LocalAddr().(*net.UDPAddr).IP.String(), nil
-}
+		for _, name := range names {
+			if frame.Function == name {
+				return true
+			}
+		}
 
-// printError prints error to error output.
-func printError(err error) {
-	fmt.Fprintln(os.Stderr, "Logger error:", err)
+		if !more {
+			return false
+		}
+	}
 }
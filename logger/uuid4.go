@@ -20,6 +20,10 @@ import (
 	"io"
 )
 
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("uuid4", func() IDGenerator { return NewUUID4() })
+}
+
 // An UUID4 represents uui4 generator.
 type UUID4 struct{}
 
@@ -41,13 +45,13 @@ func (u *UUID4) Generate() (id string, err error) {
 	uuid[6] = (uuid[6] & 0x0f) | 0x40 // Version 4
 	uuid[8] = (uuid[8] & 0x3f) | 0x80 // Variant is 10
 
-	u.encodeHex(uuid, buffer[:])
+	encodeUUID(uuid, buffer[:])
 
 	return string(buffer[:]), nil
 }
 
-// encodeHex encodes uuid to UUID format.
-func (*UUID4) encodeHex(uuid [16]byte, buffer []byte) {
+// encodeUUID encodes uuid to UUID format.
+func encodeUUID(uuid [16]byte, buffer []byte) {
 	hex.Encode(buffer, uuid[:4])
 	buffer[8] = '-'
 
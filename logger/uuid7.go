@@ -0,0 +1,98 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+)
+
+func init() { // nolint:gochecknoinits
+	_ = RegisterIDGenerator("uuid7", func() IDGenerator { return NewUUID7() })
+}
+
+// These constants define masks of random parts of UUID7.
+const (
+	uuid7RandomAMask = 1<<12 - 1
+	uuid7RandomBMask = 1<<62 - 1
+)
+
+// An UUID7 represents UUID version 7 generator. UUID7 contains 48-bit Unix
+// timestamp in milliseconds followed by random bits from crypto/rand, as
+// defined by RFC 9562, and generated UUIDs sort by time. UUIDs generated
+// within the same millisecond by the same generator are monotonically ordered
+// by incrementing random bits of the previous UUID.
+type UUID7 struct {
+	mutex     sync.Mutex
+	timestamp uint64
+	randomA   uint64
+	randomB   uint64
+}
+
+// NewUUID7 create a new UUID7 object.
+func NewUUID7() *UUID7 {
+	return &UUID7{}
+}
+
+// Generate generates new UUID7.
+func (u *UUID7) Generate() (id string, err error) {
+	var uuid [16]byte
+
+	var buffer [36]byte
+
+	timestamp := uint64(time.Now().UnixMilli())
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if timestamp > u.timestamp {
+		if err := u.randomize(); err != nil {
+			return "", err
+		}
+
+		u.timestamp = timestamp
+	} else if u.randomB = (u.randomB + 1) & uuid7RandomBMask; u.randomB == 0 {
+		if u.randomA = (u.randomA + 1) & uuid7RandomAMask; u.randomA == 0 {
+			u.timestamp++
+		}
+	}
+
+	binary.BigEndian.PutUint64(uuid[:8], u.timestamp<<16|u.randomA)
+	binary.BigEndian.PutUint64(uuid[8:], u.randomB)
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x70 // Version 7
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // Variant is 10
+
+	encodeUUID(uuid, buffer[:])
+
+	return string(buffer[:]), nil
+}
+
+// randomize sets random bits of UUID7. Caller must hold the UUID7 lock.
+func (u *UUID7) randomize() error {
+	var random [10]byte
+
+	if _, err := io.ReadFull(rand.Reader, random[:]); err != nil {
+		return NewRuntimeError("cannot generate UUID7", err)
+	}
+
+	u.randomA = uint64(binary.BigEndian.Uint16(random[:2])) & uuid7RandomAMask
+	u.randomB = binary.BigEndian.Uint64(random[2:]) & uuid7RandomBMask
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestUUID7(test *testing.T) {
+	const ids = 10000
+
+	generator := logger.NewUUID7()
+
+	before := time.Now().UnixMilli()
+
+	previous := ""
+	unique := make(map[string]struct{}, ids)
+
+	for index := 0; index < ids; index++ {
+		id, err := generator.Generate()
+
+		if err != nil {
+			test.Fatal("Generate() returns an unexpected error", err)
+		}
+
+		if len(id) != 36 {
+			test.Fatalf("len(Generate()) = %d; want 36", len(id))
+		}
+
+		if id[14] != '7' {
+			test.Fatalf("Generate() = %q; want version 7", id)
+		}
+
+		if !strings.ContainsRune("89ab", rune(id[19])) {
+			test.Fatalf("Generate() = %q; want variant 10", id)
+		}
+
+		if id <= previous {
+			test.Fatalf("Generate() = %q; want greater than %q", id, previous)
+		}
+
+		unique[id] = struct{}{}
+		previous = id
+	}
+
+	after := time.Now().UnixMilli()
+
+	if len(unique) != ids {
+		test.Errorf("len(unique) = %d; want %d", len(unique), ids)
+	}
+
+	data, err := hex.DecodeString(strings.ReplaceAll(previous, "-", "")[:12])
+
+	if err != nil {
+		test.Fatal("DecodeString() returns an unexpected error", err)
+	}
+
+	var timestamp int64
+
+	for _, value := range data {
+		timestamp = timestamp<<8 | int64(value)
+	}
+
+	if (timestamp < before) || (timestamp > after) {
+		test.Errorf("UUID7 time = %d; want between %d and %d", timestamp, before, after)
+	}
+}
@@ -15,38 +15,88 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // These constants define default values for Worker.
 const (
 	DefaultQueueLength = 4096
+	DefaultBatchSize   = 64
 )
 
+var gWorkerRunName = runtime.FuncForPC( // nolint:gochecknoglobals
+	reflect.ValueOf((*Worker).run).Pointer(),
+).Name()
+
+var gLaneRunName = runtime.FuncForPC( // nolint:gochecknoglobals
+	reflect.ValueOf((*lane).run).Pointer(),
+).Name()
+
 // A Worker represents an active logger worker thread. It handles formatting
 // received log messages and I/O operations.
+//
+// Logger worker thread is started by the NewWorker function. The Stop method
+// flushes all log messages and it stops logger worker thread, the next logged
+// message starts it again. The Logger StopWorker method stops logger worker
+// thread without restarting, log messages are emitted synchronously by
+// calling goroutines until the Start method or the Logger StartWorker method
+// is called. Logging never blocks on the queue of stopped logger worker
+// thread, log messages queued concurrently with stopping are emitted when
+// logger worker thread is started again.
 type Worker struct {
-	flush   chan *sync.WaitGroup
-	records chan *Record
-	mutex   sync.RWMutex
+	flush     chan chan struct{}
+	stop      chan struct{}
+	stopped   chan struct{}
+	queue     *queue
+	retired   []*queue
+	running   bool
+	halted    bool
+	mutex     sync.RWMutex
+	stats     workerCounters
+	parallel  bool
+	lanes     map[Handler]*lane
+	batchSize int
+}
+
+// WorkerStats defines logger worker thread statistics.
+type WorkerStats struct {
+	QueueLength   int
+	QueueCapacity int
+	Processed     uint64
+	Dropped       uint64
+	EmitErrors    uint64
+	LastEmit      time.Time
+}
+
+// workerCounters defines logger worker thread counters updated atomically.
+type workerCounters struct {
+	processed uint64
+	dropped   uint64
+	errors    uint64
+	lastEmit  int64
 }
 
 var gWorkerOnce sync.Once   // nolint:gochecknoglobals
 var gWorkerInstance *Worker // nolint:gochecknoglobals
 
-// NewWorker creates a new Worker object.
+// NewWorker creates a new Worker object and it starts logger worker thread.
 func NewWorker() *Worker {
 	worker := &Worker{
-		flush:   make(chan *sync.WaitGroup, 1),
-		records: make(chan *Record, DefaultQueueLength),
+		flush:     make(chan chan struct{}),
+		stop:      make(chan struct{}),
+		queue:     newQueue(DefaultQueueLength),
+		batchSize: DefaultBatchSize,
 	}
 
-	go worker.run()
-
-	return worker
+	return worker.Start()
 }
 
 // GetWorker returns logger worker instance. First call to it creates and
@@ -59,103 +109,544 @@ func GetWorker() *Worker {
 	return gWorkerInstance
 }
 
-// SetQueueLength sets logger worker thread queue length for log messages.
+// SetQueueLength sets logger worker thread queue length for log messages. It
+// is safe to call it during logging. Log messages already buffered in
+// the previous queue are not lost, they are emitted before log messages from
+// the new queue.
 func (w *Worker) SetQueueLength(length int) *Worker {
-	w.mutex.Lock()
-	defer w.mutex.Unlock()
-
 	if length <= 0 {
 		length = DefaultQueueLength
 	}
 
-	if cap(w.records) != length {
-		w.records = make(chan *Record, length)
+	w.mutex.Lock()
+
+	if cap(w.queue.records) == length {
+		w.mutex.Unlock()
+		return w
 	}
 
+	retired := w.queue
+	w.queue = newQueue(length)
+	w.retired = append(w.retired, retired)
+	w.mutex.Unlock()
+
+	retired.retire()
+
+	return w
+}
+
+// getQueue returns the current queue of logger worker thread.
+func (w *Worker) getQueue() *queue {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.queue
+}
+
+// send sends provided log record to the current queue of logger worker
+// thread. It retries sending to the new queue if the current one was retired
+// during sending. Without provided done channel it doesn't wait if queue is
+// full.
+func (w *Worker) send(record *Record, done <-chan struct{}) bool {
+	for {
+		switch w.getQueue().send(record, done) {
+		case queueSent:
+			return true
+		case queueFull:
+			return false
+		}
+	}
+}
+
+// SetBatchSize sets maximum number of log records emitted at once to log
+// handlers implementing the BatchHandler interface. Logger worker thread
+// collects log records that are ready in its queue up to provided size
+// without waiting for more. Setting it to 1 disables batching.
+func (w *Worker) SetBatchSize(size int) *Worker {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	w.batchSize = size
+
+	return w
+}
+
+// GetBatchSize returns maximum number of log records emitted at once to log
+// handlers implementing the BatchHandler interface.
+func (w *Worker) GetBatchSize() int {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.batchSize
+}
+
+// SetParallelEmit enables or disables parallel emit of log records to log
+// handlers. On default logger worker thread emits log records to log handlers
+// one after another and a single slow log handler delays all others. With
+// enabled parallel emit every log handler gets own goroutine fed by own queue
+// of log records with the length of logger worker thread queue. Order of log
+// records is preserved per log handler and the Flush method waits for all
+// log handler queues. Log handlers receive own copies of log records created
+// by the Record.Clone method, log arguments are not copied deeply and they
+// must not be changed by log handlers.
+func (w *Worker) SetParallelEmit(enabled bool) *Worker {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.parallel = enabled
+
+	return w
+}
+
+// IsParallelEmit returns true if parallel emit of log records to log handlers
+// is enabled.
+func (w *Worker) IsParallelEmit() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.parallel
+}
+
+// Start starts logger worker thread. It does nothing if logger worker thread
+// is already running. It allows to restart logger worker thread stopped by
+// the Stop method or by the Logger StopWorker method.
+func (w *Worker) Start() *Worker {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.halted = false
+	w.start()
+
 	return w
 }
 
-// Flush flushes all log messages.
+// start starts logger worker thread if it is not running. Caller must hold
+// the worker lock.
+func (w *Worker) start() {
+	if !w.running {
+		w.running = true
+		w.stopped = make(chan struct{})
+
+		go w.run(w.stopped)
+	}
+}
+
+// acquire starts stopped logger worker thread and it returns channel that is
+// closed when logger worker thread exits. It returns false if logger worker
+// thread was stopped by the halt method and it must not be restarted.
+func (w *Worker) acquire() (stopped <-chan struct{}, ok bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.halted {
+		return nil, false
+	}
+
+	w.start()
+
+	return w.stopped, true
+}
+
+// halt flushes all log messages and it stops logger worker thread like
+// the Stop method. Halted logger worker thread is not restarted by logged
+// messages until the Start method is called.
+func (w *Worker) halt() *Worker {
+	w.mutex.Lock()
+	w.halted = true
+	w.mutex.Unlock()
+
+	return w.Stop()
+}
+
+// IsHalted returns true if logger worker thread was stopped by the Logger
+// StopWorker method and log messages are emitted synchronously.
+func (w *Worker) IsHalted() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.halted
+}
+
+// Flush flushes all log messages. It does nothing if logger worker thread is
+// not running.
 func (w *Worker) Flush() *Worker {
-	flush := new(sync.WaitGroup)
+	w.flushDone(nil)
+
+	return w
+}
+
+// FlushTimeout flushes all log messages like the Flush method but it waits
+// at most provided timeout duration. On timeout it returns an error that
+// wraps context.DeadlineExceeded and queued log messages are left intact.
+func (w *Worker) FlushTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return w.FlushContext(ctx)
+}
+
+// FlushContext flushes all log messages like the Flush method but it waits
+// until provided context is done. When context is done before flushing all
+// log messages it returns an error that wraps the context error and queued
+// log messages are left intact.
+func (w *Worker) FlushContext(ctx context.Context) error {
+	if !w.flushDone(ctx.Done()) {
+		return NewRuntimeError("cannot flush log messages", ctx.Err())
+	}
+
+	return nil
+}
+
+// flushDone flushes all log messages. It returns false if provided done
+// channel was closed before log messages were flushed. Called from logger
+// worker thread or log handler goroutine, like for example from hook or log
+// handler, it does nothing to avoid waiting for itself.
+func (w *Worker) flushDone(done <-chan struct{}) bool {
+	if isWorkerGoroutine() {
+		return true
+	}
+
+	w.mutex.RLock()
+	running, stopped := w.running, w.stopped
+	w.mutex.RUnlock()
+
+	if !running {
+		return true
+	}
+
+	flushed := make(chan struct{})
+
+	select {
+	case w.flush <- flushed:
+	case <-stopped:
+		return true
+	case <-done:
+		return false
+	}
+
+	select {
+	case <-flushed:
+	case <-stopped:
+	case <-done:
+		return false
+	}
+
+	return true
+}
+
+// Stop flushes all log messages and it stops logger worker thread. The Start
+// method restarts stopped logger worker thread.
+func (w *Worker) Stop() *Worker {
+	w.mutex.Lock()
+
+	if !w.running {
+		w.mutex.Unlock()
+		return w
+	}
+
+	w.running = false
+	stopped := w.stopped
+	w.mutex.Unlock()
 
-	flush.Add(1)
-	w.flush <- flush
-	flush.Wait()
+	w.stop <- struct{}{}
+	<-stopped
 
 	return w
 }
 
+// Stats returns logger worker thread statistics like current queue length and
+// capacity, total number of processed and dropped log records, total number of
+// log handler emit errors and time of the last emitted log record.
+func (w *Worker) Stats() WorkerStats {
+	w.mutex.RLock()
+	length, capacity := len(w.queue.records), cap(w.queue.records)
+
+	for _, retired := range w.retired {
+		length += len(retired.records)
+	}
+
+	w.mutex.RUnlock()
+
+	stats := WorkerStats{
+		QueueLength:   length,
+		QueueCapacity: capacity,
+		Processed:     atomic.LoadUint64(&w.stats.processed),
+		Dropped:       atomic.LoadUint64(&w.stats.dropped),
+		EmitErrors:    atomic.LoadUint64(&w.stats.errors),
+	}
+
+	if lastEmit := atomic.LoadInt64(&w.stats.lastEmit); lastEmit != 0 {
+		stats.LastEmit = time.Unix(0, lastEmit)
+	}
+
+	return stats
+}
+
+// IsRunning returns true if logger worker thread is running.
+func (w *Worker) IsRunning() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.running
+}
+
+// isWorkerGoroutine returns true if it is called from logger worker thread or
+// log handler goroutine. Their run functions are entry functions of these
+// goroutines and they mark them on the call stack.
+func isWorkerGoroutine() bool {
+	return hasCaller(3, gWorkerRunName, gLaneRunName)
+}
+
 // Run processes all incoming log messages from loggers. It emits received log
-// records to all added log handlers for specific logger.
-func (w *Worker) run() {
+// records to all added log handlers for specific logger. Provided channel is
+// closed when logger worker thread exits.
+func (w *Worker) run(stopped chan struct{}) {
+	defer close(stopped)
+
 	for {
+		w.drainRetired()
+
+		q := w.getQueue()
+
 		select {
-		case flush := <-w.flush:
-			for records := len(w.records); records > 0; records-- {
-				record := <-w.records
+		case flushed := <-w.flush:
+			w.drain()
+			w.waitLanes()
+			close(flushed)
+		case <-w.stop:
+			w.drain()
+			w.closeLanes()
+			return
+		case record := <-q.records:
+			w.processBatch(q, record)
+		case <-q.sealed:
+		}
+	}
+}
 
-				if record != nil {
-					w.emit(record.logger, record)
-				}
-			}
+// processBatch emits provided log record together with other log records
+// that are ready in provided queue up to the batch size.
+func (w *Worker) processBatch(q *queue, record *Record) {
+	b := w.newBatch()
 
-			if flush != nil {
-				flush.Done()
-			}
-		case record := <-w.records:
-			if record != nil {
-				w.emit(record.logger, record)
+	b.process(record)
+
+	for b.len() < b.size {
+		select {
+		case record := <-q.records:
+			b.process(record)
+		default:
+			b.flush()
+			return
+		}
+	}
+
+	b.flush()
+}
+
+// drain emits all queued log records until the queue is empty, including log
+// records queued during draining itself.
+func (w *Worker) drain() {
+	w.drainRetired()
+	w.drainQueue(w.getQueue())
+}
+
+// drainRetired emits all log records from queues retired by
+// the SetQueueLength method in order of their retirement.
+func (w *Worker) drainRetired() {
+	w.mutex.Lock()
+	retired := w.retired
+	w.retired = nil
+	w.mutex.Unlock()
+
+	for _, q := range retired {
+		<-q.sealed
+		w.drainQueue(q)
+	}
+}
+
+// drainQueue emits all log records from provided queue until it is empty.
+func (w *Worker) drainQueue(q *queue) {
+	b := w.newBatch()
+
+	for {
+		select {
+		case record := <-q.records:
+			b.process(record)
+
+			if b.len() >= b.size {
+				b.flush()
 			}
+		default:
+			b.flush()
+			return
 		}
 	}
 }
 
+// process emits provided log record. It recovers from a panic in log handler,
+// hook or template function, it reports the panic with a stack trace using
+// logger internal error handler and it drops the log record. Log records
+// for log handlers implementing the BatchHandler interface are collected to
+// provided batch if it is not nil. Only logger worker thread provides a batch,
+// other goroutines emitting log records directly provide nil.
+func (w *Worker) process(record *Record, b *batch) {
+	logger := record.logger
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			atomic.AddUint64(&w.stats.errors, 1)
+			reportRecordError(logger, NewRuntimeError("log record emitting panicked", recovered, string(debug.Stack())), record)
+		}
+	}()
+
+	logger.reportDropped(w, b)
+	w.emit(logger, record, b)
+}
+
 // emit prepares provided log record and it dispatches to all added log
 // handlers for further formatting and specific I/O implementation operations.
-func (*Worker) emit(logger *Logger, record *Record) {
+// Log handler goroutines are owned by logger worker thread and they are used
+// only when it provides a batch. Log records emitted directly by the calling
+// goroutine, like in synchronous mode or after the StopWorker method, are
+// emitted via log handlers without them.
+func (w *Worker) emit(logger *Logger, record *Record, b *batch) {
 	var err error
 
+	if b != nil {
+		b.retain(record)
+	} else {
+		defer record.release()
+	}
+
+	atomic.AddUint64(&w.stats.processed, 1)
+	atomic.StoreInt64(&w.stats.lastEmit, time.Now().UnixNano())
+
 	record.Type = DefaultTypeName
-	record.File.Name = filepath.Base(record.File.Path)
-	record.File.Function = filepath.Base(record.File.Function)
+
+	if record.File.Path != "" {
+		record.File.Name = filepath.Base(record.File.Path)
+		record.File.Package, _ = splitFunction(record.File.Function)
+		record.File.Function = filepath.Base(record.File.Function)
+	}
+
 	record.Timestamp.Created = record.Time.Format(time.RFC3339)
 
-	record.Address, err = getAddress()
+	record.Address, err = logger.getAddress()
 
 	if err != nil {
-		printError(NewRuntimeError("cannot get local IP address", err))
+		reportRecordError(logger, NewRuntimeError("cannot get local IP address", err), record)
 	}
 
 	record.Hostname, err = getHostname()
 
 	if err != nil {
-		printError(NewRuntimeError("cannot get local hostname", err))
+		reportRecordError(logger, NewRuntimeError("cannot get local hostname", err), record)
 	}
 
 	logger.mutex.RLock()
 	defer logger.mutex.RUnlock()
 
 	record.Name = logger.name
-	record.ID, err = logger.idGenerator.Generate()
 
-	if err != nil {
-		printError(NewRuntimeError("cannot generate ID", err))
+	switch logger.idGeneration {
+	case IDGenerationLazy:
+		record.lazyID = &lazyID{logger: logger, generator: logger.idGenerator}
+	case IDGenerationOff:
+	default:
+		record.ID, err = generateID(logger.idGenerator, record)
+
+		if err != nil {
+			reportRecordError(logger, NewRuntimeError("cannot generate ID", err), record)
+		}
 	}
 
 	if record.Name == "" {
 		record.Name = filepath.Base(os.Args[0])
 	}
 
-	for _, handler := range logger.handlers {
+	record.Labels = logger.getLabels()
+	record.Fields = getRecordFields(logger.getDefaultFields(), record.Arguments)
+
+	for _, entry := range logger.hooks {
+		if err = runHook(entry.hook, record); err != nil {
+			reportRecordError(logger, NewRuntimeError("cannot run hook", entry.name, err), record)
+			return
+		}
+	}
+
+	parallel := false
+
+	if !logger.synchronous && (b != nil) {
+		parallel = w.IsParallelEmit()
+
+		if !parallel {
+			w.closeLanes()
+		}
+	}
+
+	level := record.Level.Value
+	counted := false
+
+	for name, handler := range logger.getHandlers() {
+		if handler == nil {
+			continue
+		}
+
 		min, max := handler.GetLevelRange()
 
-		if handler.IsEnabled() && (record.Level.Value >= min) && (record.Level.Value <= max) {
-			err = handler.Emit(record)
+		if handler.IsEnabled() && (level >= min) && (level <= max) {
+			counted = true
+			emitted := record
+
+			if retainer, ok := handler.(Retainer); ok && retainer.RetainsRecords() {
+				emitted = record.Clone()
+			}
+
+			batcher, batched := handler.(BatchHandler)
+
+			switch {
+			case parallel:
+				if emitted == record {
+					emitted = record.Clone()
+				}
 
-			if err != nil {
-				printError(NewRuntimeError("cannot emit record", err))
+				w.getLane(handler).send(logger, name, emitted)
+			case batched && (b != nil):
+				b.add(logger, name, handler, batcher, emitted)
+			default:
+				w.emitHandler(logger, name, handler, emitted)
 			}
 		}
 	}
+
+	if counted {
+		logger.levelCounts.add(level)
+	}
+}
+
+// emitHandler emits provided log record via provided log handler.
+func (w *Worker) emitHandler(logger *Logger, name string, handler Handler, record *Record) {
+	if err := handler.Emit(record); err != nil {
+		atomic.AddUint64(&w.stats.errors, 1)
+		reportRecordError(logger, NewRuntimeError("cannot emit record via handler {p | printf \"%q\"}", name, err), record)
+	}
+}
+
+// emitBatch emits provided log records via provided log handler at once. It
+// uses the Emit method for a single log record.
+func (w *Worker) emitBatch(logger *Logger, name string, handler Handler, batcher BatchHandler, records []*Record) {
+	if len(records) == 1 {
+		w.emitHandler(logger, name, handler, records[0])
+		return
+	}
+
+	if err := batcher.EmitBatch(records); err != nil {
+		atomic.AddUint64(&w.stats.errors, 1)
+		reportRecordError(logger, NewRuntimeError("cannot emit records via handler {p | printf \"%q\"}", name, err), records...)
+	}
 }
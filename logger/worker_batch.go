@@ -0,0 +1,114 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// A batch represents log records collected by logger worker thread for log
+// handlers implementing the BatchHandler interface. It is used only by logger
+// worker thread.
+type batch struct {
+	worker   *Worker
+	size     int
+	records  []*Record
+	entries  []*batchEntry
+	handlers map[Handler]*batchEntry
+}
+
+// batchEntry defines log records collected for a single log handler.
+type batchEntry struct {
+	logger  *Logger
+	name    string
+	handler Handler
+	batcher BatchHandler
+	records []*Record
+}
+
+// newBatch creates a new empty batch.
+func (w *Worker) newBatch() *batch {
+	return &batch{
+		worker:   w,
+		size:     w.GetBatchSize(),
+		handlers: make(map[Handler]*batchEntry),
+	}
+}
+
+// len returns number of processed log records.
+func (b *batch) len() int {
+	return len(b.records)
+}
+
+// process processes provided log record.
+func (b *batch) process(record *Record) {
+	if record != nil {
+		b.worker.process(record, b)
+	}
+}
+
+// retain keeps provided log record until the batch is flushed.
+func (b *batch) retain(record *Record) {
+	b.records = append(b.records, record)
+}
+
+// add adds provided log record for provided log handler.
+func (b *batch) add(logger *Logger, name string, handler Handler, batcher BatchHandler, record *Record) {
+	entry, ok := b.handlers[handler]
+
+	if !ok {
+		entry = &batchEntry{
+			logger:  logger,
+			name:    name,
+			handler: handler,
+			batcher: batcher,
+		}
+
+		b.handlers[handler] = entry
+		b.entries = append(b.entries, entry)
+	}
+
+	entry.records = append(entry.records, record)
+}
+
+// flush emits all collected log records to log handlers in the order they
+// were added and it releases processed log records.
+func (b *batch) flush() {
+	for _, entry := range b.entries {
+		b.emit(entry)
+		delete(b.handlers, entry.handler)
+	}
+
+	for _, record := range b.records {
+		record.release()
+	}
+
+	b.records = b.records[:0]
+	b.entries = b.entries[:0]
+}
+
+// emit emits collected log records via log handler. It recovers from a panic
+// in log handler like the Worker.process method.
+func (b *batch) emit(entry *batchEntry) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			atomic.AddUint64(&b.worker.stats.errors, 1)
+			reportRecordError(entry.logger, NewRuntimeError("log records emitting panicked", recovered, string(debug.Stack())), entry.records...)
+		}
+	}()
+
+	b.worker.emitBatch(entry.logger, entry.name, entry.handler, entry.batcher, entry.records)
+}
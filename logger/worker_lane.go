@@ -0,0 +1,162 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// A lane represents log handler goroutine with own queue of log records used
+// by logger worker thread in parallel emit mode.
+type lane struct {
+	worker  *Worker
+	handler Handler
+	records chan laneRecord
+	pending sync.WaitGroup
+	stopped chan struct{}
+}
+
+// laneRecord defines log record queued to log handler goroutine.
+type laneRecord struct {
+	logger *Logger
+	name   string
+	record *Record
+}
+
+// getLane returns log handler goroutine for provided log handler. It creates
+// and starts a new one if it doesn't exist yet. Log handlers shared between
+// loggers share also log handler goroutine. It must be called only from logger
+// worker thread.
+func (w *Worker) getLane(handler Handler) *lane {
+	if l, ok := w.lanes[handler]; ok {
+		return l
+	}
+
+	if w.lanes == nil {
+		w.lanes = make(map[Handler]*lane)
+	}
+
+	l := &lane{
+		worker:  w,
+		handler: handler,
+		records: make(chan laneRecord, cap(w.getQueue().records)),
+		stopped: make(chan struct{}),
+	}
+
+	w.lanes[handler] = l
+
+	go l.run()
+
+	return l
+}
+
+// waitLanes waits until all log handler goroutines emit all queued log
+// records. It must be called only from logger worker thread.
+func (w *Worker) waitLanes() {
+	for _, l := range w.lanes {
+		l.pending.Wait()
+	}
+}
+
+// closeLanes emits all queued log records and it stops all log handler
+// goroutines. It must be called only from logger worker thread.
+func (w *Worker) closeLanes() {
+	for handler, l := range w.lanes {
+		close(l.records)
+		<-l.stopped
+		delete(w.lanes, handler)
+	}
+}
+
+// send queues provided log record to log handler goroutine. It blocks when
+// log handler queue is full.
+func (l *lane) send(logger *Logger, name string, record *Record) {
+	l.pending.Add(1)
+
+	l.records <- laneRecord{
+		logger: logger,
+		name:   name,
+		record: record,
+	}
+}
+
+// run emits all queued log records via log handler until log handler queue
+// is closed. Log records that are ready in log handler queue are emitted at
+// once up to the batch size if log handler implements the BatchHandler
+// interface.
+func (l *lane) run() {
+	defer close(l.stopped)
+
+	batcher, batched := l.handler.(BatchHandler)
+
+	for entry := range l.records {
+		entries := []laneRecord{entry}
+
+		if batched {
+			entries = l.collect(entries)
+		}
+
+		l.emit(batcher, entries)
+	}
+}
+
+// collect appends log records that are ready in log handler queue up to
+// the batch size.
+func (l *lane) collect(entries []laneRecord) []laneRecord {
+	size := l.worker.GetBatchSize()
+
+	for len(entries) < size {
+		select {
+		case entry, ok := <-l.records:
+			if !ok {
+				return entries
+			}
+
+			entries = append(entries, entry)
+		default:
+			return entries
+		}
+	}
+
+	return entries
+}
+
+// emit emits queued log records via log handler. It recovers from a panic in
+// log handler like logger worker thread.
+func (l *lane) emit(batcher BatchHandler, entries []laneRecord) {
+	defer l.pending.Add(-len(entries))
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			atomic.AddUint64(&l.worker.stats.errors, 1)
+			reportRecordError(entries[0].logger, NewRuntimeError("log record emitting panicked", recovered, string(debug.Stack())), entries[0].record)
+		}
+	}()
+
+	if len(entries) == 1 {
+		l.worker.emitHandler(entries[0].logger, entries[0].name, l.handler, entries[0].record)
+		return
+	}
+
+	records := make([]*Record, len(entries))
+
+	for index, entry := range entries {
+		records[index] = entry.record
+	}
+
+	l.worker.emitBatch(entries[0].logger, entries[0].name, l.handler, batcher, records)
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+)
+
+// These constants define results of sending log record to queue.
+const (
+	queueSent = iota
+	queueFull
+	queueRetired
+)
+
+// A queue represents logger worker thread queue of log records. Queue replaced
+// by the Worker SetQueueLength method is retired. Retired queue doesn't accept
+// new log records and log records already buffered in it are emitted by logger
+// worker thread before log records from the new queue.
+type queue struct {
+	records chan *Record
+	retired chan struct{}
+	sealed  chan struct{}
+	mutex   sync.RWMutex
+}
+
+// newQueue creates a new queue with provided length.
+func newQueue(length int) *queue {
+	return &queue{
+		records: make(chan *Record, length),
+		retired: make(chan struct{}),
+		sealed:  make(chan struct{}),
+	}
+}
+
+// send sends provided log record to queue. Without provided done channel it
+// returns queueFull immediately if queue is full, otherwise it waits until
+// log record is queued or done channel is closed. It returns queueRetired if
+// queue was retired and log record must be sent to the current queue.
+func (q *queue) send(record *Record, done <-chan struct{}) int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	select {
+	case <-q.retired:
+		return queueRetired
+	default:
+	}
+
+	if done == nil {
+		select {
+		case q.records <- record:
+			return queueSent
+		default:
+			return queueFull
+		}
+	}
+
+	select {
+	case q.records <- record:
+		return queueSent
+	case <-q.retired:
+		return queueRetired
+	case <-done:
+		return queueFull
+	}
+}
+
+// receive returns the oldest log record from queue or nil if queue is empty.
+func (q *queue) receive() *Record {
+	select {
+	case record := <-q.records:
+		return record
+	default:
+		return nil
+	}
+}
+
+// retire stops accepting of new log records and it waits for pending send
+// calls. After it returns no more log records can be sent to queue and
+// the sealed channel is closed.
+func (q *queue) retire() {
+	close(q.retired)
+
+	q.mutex.Lock()
+	close(q.sealed)
+	q.mutex.Unlock()
+}
@@ -0,0 +1,795 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/tymonx/go-logger/logger"
+)
+
+func TestLoggerSetWorker(test *testing.T) {
+	buffer := logger.NewBuffer()
+	buffer.GetFormatter().SetFormat("{message}")
+
+	worker := logger.NewWorker()
+
+	log := logger.New().SetHandler("buffer", buffer).SetWorker(worker)
+
+	if log.GetWorker() != worker {
+		test.Error("log.GetWorker() returns an unexpected worker")
+	}
+
+	log.Info(testMessage)
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+
+	if worker.IsRunning() {
+		test.Error("worker.IsRunning() = true; want false")
+	}
+
+	if buffer.String() != testMessage+"\n" {
+		test.Error("buffer.String() =", buffer.String(), "; want", testMessage)
+	}
+
+	if !logger.GetWorker().IsRunning() {
+		test.Error("logger.GetWorker().IsRunning() = false; want true")
+	}
+}
+
+func TestWorkerStopStart(test *testing.T) {
+	goroutines := runtime.NumGoroutine()
+
+	observer := logger.NewObserver()
+
+	worker := logger.NewWorker()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(worker)
+
+	log.Info(testMessage)
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+
+	if worker.IsRunning() {
+		test.Error("worker.IsRunning() = true; want false")
+	}
+
+	worker.Flush().Stop()
+
+	log.Info(testMessage)
+	log.Flush()
+
+	if !worker.IsRunning() {
+		test.Error("worker.IsRunning() = false; want true")
+	}
+
+	if observer.Len() != 2 {
+		test.Errorf("observer.Len() = %d; want 2", observer.Len())
+	}
+
+	worker.Start().Stop()
+
+	for retry := 0; (runtime.NumGoroutine() > goroutines) && (retry < 100); retry++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	if runtime.NumGoroutine() > goroutines {
+		test.Errorf("runtime.NumGoroutine() = %d; want %d", runtime.NumGoroutine(), goroutines)
+	}
+}
+
+func TestWorkerFlushFromHook(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(logger.NewWorker()).SetFlushLevel(logger.ErrorLevel)
+
+	log.AddHook("log", func(record *logger.Record) error {
+		if record.Message == testMessage {
+			log.Error("from hook")
+		}
+
+		return nil
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		log.Info(testMessage)
+		log.Flush()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		test.Fatal("Flush() called from logger worker thread deadlocks")
+	}
+
+	log.Flush()
+
+	if observer.Len() != 2 {
+		test.Errorf("observer.Len() = %d; want 2", observer.Len())
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestWorkerStats(test *testing.T) {
+	stream := logger.NewStream()
+
+	if err := stream.SetWriter(failingWriter{}); err != nil {
+		test.Fatal("SetWriter() returns an unexpected error", err)
+	}
+
+	worker := logger.NewWorker()
+
+	log := logger.New().SetHandlers(logger.Handlers{
+		"observer": logger.NewObserver(),
+		"failing":  stream,
+	}).SetWorker(worker).SetInternalErrorHandler(func(error) {})
+
+	if stats := worker.Stats(); !stats.LastEmit.IsZero() || (stats.QueueCapacity != logger.DefaultQueueLength) {
+		test.Errorf("Stats() = %+v; want zero LastEmit and default queue capacity", stats)
+	}
+
+	log.Info(testMessage)
+	log.Info(testMessage)
+	log.Flush()
+
+	stats := worker.Stats()
+
+	if (stats.Processed != 2) || (stats.EmitErrors != 2) || (stats.Dropped != 0) || (stats.QueueLength != 0) {
+		test.Errorf("Stats() = %+v; want 2 processed and 2 emit errors", stats)
+	}
+
+	if stats.LastEmit.IsZero() {
+		test.Error("Stats().LastEmit is zero")
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+type panickingHandler struct {
+	*logger.Observer
+}
+
+func (panickingHandler) Emit(record *logger.Record) error {
+	if record.Message == "panic" {
+		panic(testError)
+	}
+
+	return nil
+}
+
+func TestWorkerPanickingHandler(test *testing.T) {
+	var reported []error
+
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandlers(logger.Handlers{
+		"observer":  observer,
+		"panicking": panickingHandler{logger.NewObserver()},
+	}).SetWorker(logger.NewWorker()).SetInternalErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+
+	log.Info("panic")
+	log.Info("after")
+	log.Flush()
+
+	if observer.FilterMessage("after").Len() != 1 {
+		test.Error("log records are not emitted after handler panic")
+	}
+
+	if len(reported) != 1 {
+		test.Fatalf("len(reported) = %d; want 1", len(reported))
+	}
+
+	if message := reported[0].Error(); !strings.Contains(message, "panicked") || !strings.Contains(message, "goroutine") {
+		test.Error("reported panic doesn't contain a stack trace", message)
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+type blockingHandler struct {
+	*logger.Observer
+	release chan struct{}
+}
+
+func (b blockingHandler) Emit(record *logger.Record) error {
+	<-b.release
+
+	return b.Observer.Emit(record)
+}
+
+func TestWorkerParallelEmit(test *testing.T) {
+	fast := logger.NewObserver()
+
+	slow := blockingHandler{
+		Observer: logger.NewObserver(),
+		release:  make(chan struct{}),
+	}
+
+	worker := logger.NewWorker().SetParallelEmit(true)
+
+	if !worker.IsParallelEmit() {
+		test.Error("IsParallelEmit() = false; want true")
+	}
+
+	log := logger.New().SetWorker(worker).SetHandlers(logger.Handlers{
+		"fast": fast,
+		"slow": slow,
+	})
+
+	for index := 0; index < 8; index++ {
+		log.Info("{p}", index)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for (fast.Len() != 8) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if fast.Len() != 8 {
+		test.Fatalf("fast.Len() = %d; want 8", fast.Len())
+	}
+
+	if slow.Len() != 0 {
+		test.Errorf("slow.Len() = %d; want 0", slow.Len())
+	}
+
+	close(slow.release)
+	log.Flush()
+
+	for _, observer := range []*logger.Observer{fast, slow.Observer} {
+		entries := observer.All()
+
+		if len(entries) != 8 {
+			test.Fatalf("len(entries) = %d; want 8", len(entries))
+		}
+
+		for index, entry := range entries {
+			if want := strconv.Itoa(index); entry.Message != want {
+				test.Errorf("entries[%d].Message = %q; want %q", index, entry.Message, want)
+			}
+		}
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+type batchingHandler struct {
+	*logger.Observer
+	batches *[]int
+}
+
+func (b batchingHandler) EmitBatch(records []*logger.Record) error {
+	*b.batches = append(*b.batches, len(records))
+
+	for _, record := range records {
+		if err := b.Observer.Emit(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestWorkerBatchHandler(test *testing.T) {
+	var batches []int
+
+	started := make(chan struct{})
+	released := make(chan struct{})
+
+	handler := batchingHandler{
+		Observer: logger.NewObserver(),
+		batches:  &batches,
+	}
+
+	worker := logger.NewWorker().SetBatchSize(4)
+
+	log := logger.New().SetWorker(worker).SetHandlers(logger.Handlers{
+		"batching": handler,
+	}).AddHook("block", func(record *logger.Record) error {
+		if record.Message == "block" {
+			close(started)
+			<-released
+		}
+
+		return nil
+	})
+
+	log.Info("block")
+	<-started
+
+	for index := 0; index < 10; index++ {
+		log.Info("{p}", index)
+	}
+
+	close(released)
+	log.Flush()
+
+	if want := []int{4, 4, 3}; !reflect.DeepEqual(batches, want) {
+		test.Errorf("batches = %v; want %v", batches, want)
+	}
+
+	entries := handler.All()
+
+	if len(entries) != 11 {
+		test.Fatalf("len(entries) = %d; want 11", len(entries))
+	}
+
+	for index, entry := range entries[1:] {
+		if want := strconv.Itoa(index); entry.Message != want {
+			test.Errorf("entries[%d].Message = %q; want %q", index+1, entry.Message, want)
+		}
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestWorkerFlushConcurrentProducers(test *testing.T) {
+	const (
+		producers = 8
+		records   = 200
+	)
+
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(logger.NewWorker().Stop().SetQueueLength(4).Start())
+
+	var wg sync.WaitGroup
+
+	for producer := 0; producer < producers; producer++ {
+		wg.Add(1)
+
+		go func(producer int) {
+			defer wg.Done()
+
+			prefix := "producer " + strconv.Itoa(producer) + " "
+
+			for index := 0; index < records; index++ {
+				log.Info(prefix + strconv.Itoa(index))
+			}
+
+			log.Flush()
+
+			emitted := observer.All().Filter(func(entry *logger.ObservedEntry) bool {
+				return strings.HasPrefix(entry.Message, prefix)
+			})
+
+			if len(emitted) != records {
+				test.Errorf("%s: len(emitted) = %d; want %d", prefix, len(emitted), records)
+			}
+		}(producer)
+	}
+
+	wg.Wait()
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestWorkerSetQueueLengthConcurrent(test *testing.T) {
+	const (
+		producers = 8
+		records   = 200
+	)
+
+	observer := logger.NewObserver()
+
+	worker := logger.NewWorker().SetQueueLength(4)
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(worker)
+
+	done := make(chan struct{})
+	resized := make(chan struct{})
+
+	go func() {
+		defer close(resized)
+
+		for length := 1; ; length++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			worker.SetQueueLength(1 + length%8)
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	for producer := 0; producer < producers; producer++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := 0; index < records; index++ {
+				log.Info(testMessage)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	<-resized
+	log.Flush()
+
+	if want := producers * records; observer.Len() != want {
+		test.Errorf("observer.Len() = %d; want %d", observer.Len(), want)
+	}
+
+	if stats := worker.SetQueueLength(16).Stats(); (stats.QueueCapacity != 16) || (stats.QueueLength != 0) {
+		test.Errorf("Stats() = %+v; want empty queue with capacity 16", stats)
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestLoggerStopWorker(test *testing.T) {
+	observer := logger.NewObserver()
+
+	worker := logger.NewWorker()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(worker)
+
+	log.Info(testMessage)
+	log.StopWorker()
+
+	if worker.IsRunning() || !worker.IsHalted() {
+		test.Error("StopWorker() doesn't stop logger worker thread")
+	}
+
+	if observer.Len() != 1 {
+		test.Errorf("observer.Len() = %d; want 1", observer.Len())
+	}
+
+	log.Info(testMessage)
+	log.Info(testMessage)
+
+	if observer.Len() != 3 {
+		test.Errorf("observer.Len() = %d; want 3", observer.Len())
+	}
+
+	if worker.IsRunning() {
+		test.Error("worker.IsRunning() = true; want false")
+	}
+
+	log.StartWorker()
+
+	if !worker.IsRunning() || worker.IsHalted() {
+		test.Error("StartWorker() doesn't start logger worker thread")
+	}
+
+	log.Info(testMessage)
+	log.Flush()
+
+	if observer.Len() != 4 {
+		test.Errorf("observer.Len() = %d; want 4", observer.Len())
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestLoggerStopWorkerConcurrentProducers(test *testing.T) {
+	const (
+		producers = 8
+		records   = 200
+	)
+
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(logger.NewWorker().Stop().SetQueueLength(4).Start())
+
+	var wg sync.WaitGroup
+
+	for producer := 0; producer < producers; producer++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := 0; index < records; index++ {
+				log.Info(testMessage)
+			}
+		}()
+	}
+
+	log.StopWorker()
+	wg.Wait()
+	log.StartWorker().Flush()
+
+	if want := producers * records; observer.Len() != want {
+		test.Errorf("observer.Len() = %d; want %d", observer.Len(), want)
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+type retainingHandler struct {
+	*logger.Observer
+	mutex   *sync.Mutex
+	records *[]*logger.Record
+}
+
+func (r retainingHandler) RetainsRecords() bool {
+	return true
+}
+
+func (r retainingHandler) Emit(record *logger.Record) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	*r.records = append(*r.records, record)
+
+	return nil
+}
+
+func (r retainingHandler) snapshot() []*logger.Record {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*logger.Record(nil), *r.records...)
+}
+
+func TestWorkerRetainer(test *testing.T) {
+	const count = 1000
+
+	var records []*logger.Record
+
+	handler := retainingHandler{
+		Observer: logger.NewObserver(),
+		mutex:    new(sync.Mutex),
+		records:  &records,
+	}
+
+	log := logger.New().SetRecordPooling(true).SetWorker(logger.NewWorker()).SetHandlers(logger.Handlers{
+		"retaining": handler,
+		"observer":  logger.NewObserver(),
+	})
+
+	done := make(chan struct{})
+	read := make(chan struct{})
+
+	go func() {
+		defer close(read)
+
+		for {
+			for _, record := range handler.snapshot() {
+				if record.Message != "Message {p}" {
+					test.Errorf("record.Message = %q; want %q", record.Message, "Message {p}")
+					return
+				}
+			}
+
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+	}()
+
+	for index := 0; index < count; index++ {
+		log.Info("Message {p}", index)
+	}
+
+	log.Flush()
+	close(done)
+	<-read
+
+	retained := handler.snapshot()
+
+	if len(retained) != count {
+		test.Fatalf("len(retained) = %d; want %d", len(retained), count)
+	}
+
+	for index, record := range retained {
+		if (len(record.Arguments) != 1) || (record.Arguments[0] != index) {
+			test.Errorf("retained[%d].Arguments = %v; want [%d]", index, record.Arguments, index)
+		}
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+type flushingHandler struct {
+	*logger.Observer
+	log *logger.Logger
+}
+
+func (f flushingHandler) Emit(record *logger.Record) error {
+	f.log.Flush()
+
+	return f.Observer.Emit(record)
+}
+
+func TestWorkerFlushFromLaneHandler(test *testing.T) {
+	log := logger.New().SetWorker(logger.NewWorker().SetParallelEmit(true))
+	handler := flushingHandler{Observer: logger.NewObserver(), log: log}
+
+	log.SetHandlers(logger.Handlers{"flushing": handler}).SetInternalErrorHandler(func(err error) {
+		test.Error("unexpected internal error", err)
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		log.Info(testMessage)
+		log.Flush()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		test.Fatal("Flush() called from log handler goroutine deadlocks")
+	}
+
+	if handler.Len() != 1 {
+		test.Errorf("Len() = %d; want 1", handler.Len())
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestLoggerStopWorkerParallelEmit(test *testing.T) {
+	const (
+		producers = 8
+		records   = 100
+	)
+
+	observer := logger.NewObserver()
+
+	log := logger.New().SetHandler("observer", observer).SetWorker(logger.NewWorker().SetParallelEmit(true))
+
+	log.Info(testMessage)
+	log.StopWorker()
+
+	var wg sync.WaitGroup
+
+	for producer := 0; producer < producers; producer++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := 0; index < records; index++ {
+				log.Info(testMessage)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if want := producers*records + 1; observer.Len() != want {
+		test.Errorf("observer.Len() = %d; want %d", observer.Len(), want)
+	}
+
+	if err := log.StartWorker().Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+type mutatingHandler struct {
+	*logger.Observer
+}
+
+func (m mutatingHandler) Emit(record *logger.Record) error {
+	record.Message = "mutated"
+
+	return m.Observer.Emit(record)
+}
+
+func TestWorkerParallelEmitCopies(test *testing.T) {
+	observer := logger.NewObserver()
+
+	log := logger.New().SetWorker(logger.NewWorker().SetParallelEmit(true)).SetHandlers(logger.Handlers{
+		"mutating": mutatingHandler{logger.NewObserver()},
+		"observer": observer,
+	})
+
+	for index := 0; index < 100; index++ {
+		log.Info(testMessage)
+	}
+
+	log.Flush()
+
+	if length := observer.FilterMessage(testMessage).Len(); length != 100 {
+		test.Errorf("Len() = %d; want 100", length)
+	}
+
+	if err := log.Close(); err != nil {
+		test.Error("Close() returns an unexpected error", err)
+	}
+}
+
+func TestWorkerRecordPooling(test *testing.T) {
+	var batches []int
+
+	observer := logger.NewObserver()
+
+	handler := batchingHandler{
+		Observer: logger.NewObserver(),
+		batches:  &batches,
+	}
+
+	for _, parallel := range []bool{false, true} {
+		log := logger.New().SetRecordPooling(true).SetWorker(logger.NewWorker().SetParallelEmit(parallel)).SetHandlers(logger.Handlers{
+			"batching": handler,
+			"observer": observer,
+		})
+
+		for index := 0; index < 100; index++ {
+			log.Info("Message {p}", index)
+		}
+
+		log.Flush()
+
+		if err := log.Close(); err != nil {
+			test.Error("Close() returns an unexpected error", err)
+		}
+	}
+
+	for _, entries := range []logger.ObservedLogs{observer.All(), handler.All()} {
+		if len(entries) != 200 {
+			test.Fatalf("len(entries) = %d; want 200", len(entries))
+		}
+
+		for index, entry := range entries {
+			if want := "Message " + strconv.Itoa(index%100); entry.Message != want {
+				test.Errorf("entries[%d].Message = %q; want %q", index, entry.Message, want)
+			}
+		}
+	}
+}
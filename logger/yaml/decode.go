@@ -0,0 +1,452 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yaml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// These errors are returned when data cannot be decoded from YAML.
+var (
+	ErrInvalidSyntax = errors.New("invalid syntax")           // nolint:gochecknoglobals
+	ErrUnsupported   = errors.New("unsupported feature")      // nolint:gochecknoglobals
+	ErrDuplicateKey  = errors.New("duplicate mapping key")    // nolint:gochecknoglobals
+	ErrUnterminated  = errors.New("unterminated quoted text") // nolint:gochecknoglobals
+)
+
+// A line represents significant line of YAML document without indentation and
+// comment.
+type line struct {
+	number int
+	indent int
+	text   string
+}
+
+// A decoder represents YAML decoder state.
+type decoder struct {
+	lines []line
+	index int
+}
+
+// Unmarshal decodes YAML data and it stores the result in the value pointed to
+// by provided value. Decoded value is stored like by the encoding/json package,
+// using the same json tags and conversion rules. For a pointer to an empty
+// interface mappings are decoded as map[string]interface{}, sequences as
+// []interface{}, integers as int64, floats as float64, booleans as bool and
+// nulls as nil.
+func Unmarshal(data []byte, value interface{}) error {
+	lines, err := split(string(data))
+
+	if err != nil {
+		return err
+	}
+
+	d := &decoder{lines: lines}
+
+	var decoded interface{}
+
+	if len(d.lines) != 0 {
+		if decoded, err = d.decodeNode(); err != nil {
+			return err
+		}
+
+		if d.index != len(d.lines) {
+			return d.errorf(ErrInvalidSyntax)
+		}
+	}
+
+	if pointer, ok := value.(*interface{}); ok {
+		*pointer = decoded
+		return nil
+	}
+
+	bytes, err := json.Marshal(decoded)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, value)
+}
+
+// split splits YAML document to significant lines. It removes comments, empty
+// lines and document markers.
+func split(data string) ([]line, error) {
+	var lines []line
+
+	for index, text := range strings.Split(data, "\n") {
+		number := index + 1
+		content := strings.TrimLeft(strings.TrimRight(text, "\r"), " ")
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+
+		content, err := stripComment(content)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode line %d: %w", number, err)
+		}
+
+		if content = strings.TrimRight(content, " \t"); content == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(content, "\t"):
+			return nil, fmt.Errorf("cannot decode line %d: %w", number, ErrInvalidSyntax)
+		case (indent == 0) && (content == "---") && (len(lines) == 0):
+			continue
+		case (indent == 0) && (content == "..."):
+			return lines, nil
+		case (indent == 0) && (strings.HasPrefix(content, "---") || strings.HasPrefix(content, "%")):
+			return nil, fmt.Errorf("cannot decode line %d: %w", number, ErrUnsupported)
+		}
+
+		lines = append(lines, line{
+			number: number,
+			indent: indent,
+			text:   content,
+		})
+	}
+
+	return lines, nil
+}
+
+// stripComment removes comment from provided line content.
+func stripComment(content string) (string, error) {
+	for index := 0; index < len(content); index++ {
+		switch character := content[index]; {
+		case (character == '#') && ((index == 0) || (content[index-1] == ' ') || (content[index-1] == '\t')):
+			return content[:index], nil
+		case ((character == '"') || (character == '\'')) && startsScalar(content, index):
+			end, err := findQuoteEnd(content, index)
+
+			if err != nil {
+				return "", err
+			}
+
+			index = end
+		}
+	}
+
+	return content, nil
+}
+
+// startsScalar returns true if scalar can start at provided index, at the
+// beginning of line content or after mapping value or sequence item indicator.
+func startsScalar(content string, index int) bool {
+	previous := strings.TrimRight(content[:index], " ")
+
+	if previous == "" {
+		return true
+	}
+
+	return (len(previous) < index) && strings.ContainsAny(previous[len(previous)-1:], ":-")
+}
+
+// findQuoteEnd returns index of closing quote of quoted scalar starting at
+// provided index.
+func findQuoteEnd(content string, start int) (int, error) {
+	quote := content[start]
+
+	for index := start + 1; index < len(content); index++ {
+		switch {
+		case (quote == '"') && (content[index] == '\\'):
+			index++
+		case (quote == '\'') && (content[index] == '\'') && (index+1 < len(content)) && (content[index+1] == '\''):
+			index++
+		case content[index] == quote:
+			return index, nil
+		}
+	}
+
+	return 0, ErrUnterminated
+}
+
+// decodeNode decodes mapping, sequence or scalar starting at the current line.
+func (d *decoder) decodeNode() (interface{}, error) {
+	current := d.lines[d.index]
+
+	if isSequenceItem(current.text) {
+		return d.decodeSequence(current.indent)
+	}
+
+	if _, _, ok, err := splitKey(current.text); err != nil {
+		return nil, d.errorf(err)
+	} else if ok {
+		return d.decodeMapping(current.indent)
+	}
+
+	value, err := decodeScalar(current.text)
+
+	if err != nil {
+		return nil, d.errorf(err)
+	}
+
+	d.index++
+
+	return value, nil
+}
+
+// decodeMapping decodes block mapping with provided indentation.
+func (d *decoder) decodeMapping(indent int) (interface{}, error) {
+	mapping := make(map[string]interface{})
+
+	for d.index < len(d.lines) {
+		current := d.lines[d.index]
+
+		if current.indent < indent {
+			break
+		}
+
+		if (current.indent > indent) || isSequenceItem(current.text) {
+			return nil, d.errorf(ErrInvalidSyntax)
+		}
+
+		key, text, ok, err := splitKey(current.text)
+
+		switch {
+		case err != nil:
+			return nil, d.errorf(err)
+		case !ok:
+			return nil, d.errorf(ErrInvalidSyntax)
+		}
+
+		if _, exists := mapping[key]; exists {
+			return nil, d.errorf(ErrDuplicateKey)
+		}
+
+		var value interface{}
+
+		if text != "" {
+			if value, err = decodeScalar(text); err != nil {
+				return nil, d.errorf(err)
+			}
+
+			d.index++
+		} else {
+			d.index++
+
+			if value, err = d.decodeChild(indent, true); err != nil {
+				return nil, err
+			}
+		}
+
+		mapping[key] = value
+	}
+
+	return mapping, nil
+}
+
+// decodeSequence decodes block sequence with provided indentation.
+func (d *decoder) decodeSequence(indent int) (interface{}, error) {
+	sequence := make([]interface{}, 0)
+
+	for d.index < len(d.lines) {
+		current := &d.lines[d.index]
+
+		if (current.indent < indent) || ((current.indent == indent) && !isSequenceItem(current.text)) {
+			break
+		}
+
+		if current.indent > indent {
+			return nil, d.errorf(ErrInvalidSyntax)
+		}
+
+		var item interface{}
+
+		var err error
+
+		if rest := strings.TrimLeft(current.text[1:], " "); rest == "" {
+			d.index++
+			item, err = d.decodeChild(indent, false)
+		} else {
+			current.indent += len(current.text) - len(rest)
+			current.text = rest
+			item, err = d.decodeNode()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		sequence = append(sequence, item)
+	}
+
+	return sequence, nil
+}
+
+// decodeChild decodes nested node of mapping value or sequence item without
+// inlined content. Block sequence can be a mapping value with the same
+// indentation as mapping. Missing nested node is decoded as nil.
+func (d *decoder) decodeChild(indent int, sequence bool) (interface{}, error) {
+	if d.index >= len(d.lines) {
+		return nil, nil
+	}
+
+	next := d.lines[d.index]
+
+	if (next.indent > indent) || (sequence && (next.indent == indent) && isSequenceItem(next.text)) {
+		return d.decodeNode()
+	}
+
+	return nil, nil
+}
+
+// errorf returns provided error with the current line number.
+func (d *decoder) errorf(err error) error {
+	number := 0
+
+	if d.index < len(d.lines) {
+		number = d.lines[d.index].number
+	}
+
+	return fmt.Errorf("cannot decode line %d: %w", number, err)
+}
+
+// isSequenceItem returns true if line content starts with sequence item
+// indicator.
+func isSequenceItem(text string) bool {
+	return (text == "-") || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits mapping entry to key and value text. It returns false if
+// provided line content is not a mapping entry.
+func splitKey(text string) (key, value string, ok bool, err error) {
+	separator := -1
+
+	if (text[0] == '"') || (text[0] == '\'') {
+		end, err := findQuoteEnd(text, 0)
+
+		if err != nil {
+			return "", "", false, err
+		}
+
+		rest := strings.TrimLeft(text[end+1:], " ")
+
+		if !strings.HasPrefix(rest, ":") || ((len(rest) > 1) && (rest[1] != ' ')) {
+			return "", "", false, nil
+		}
+
+		separator = len(text) - len(rest)
+	} else {
+		for index := 0; index < len(text); index++ {
+			if (text[index] == ':') && ((index+1 == len(text)) || (text[index+1] == ' ')) {
+				separator = index
+				break
+			}
+		}
+	}
+
+	if separator < 0 {
+		return "", "", false, nil
+	}
+
+	decoded, err := decodeScalar(strings.TrimRight(text[:separator], " "))
+
+	if err != nil {
+		return "", "", false, err
+	}
+
+	if key, ok = decoded.(string); !ok {
+		key = strings.TrimRight(text[:separator], " ")
+	}
+
+	if key == "" {
+		return "", "", false, ErrInvalidSyntax
+	}
+
+	return key, strings.TrimLeft(text[separator+1:], " "), true, nil
+}
+
+// decodeScalar decodes plain, single-quoted or double-quoted scalar.
+func decodeScalar(text string) (interface{}, error) {
+	switch text[0] {
+	case '"', '\'':
+		end, err := findQuoteEnd(text, 0)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if end != len(text)-1 {
+			return nil, ErrInvalidSyntax
+		}
+
+		if text[0] == '\'' {
+			return strings.ReplaceAll(text[1:end], "''", "'"), nil
+		}
+
+		value, err := strconv.Unquote(text)
+
+		if err != nil {
+			return nil, ErrInvalidSyntax
+		}
+
+		return value, nil
+	case '[', '{', '|', '>', '&', '*', '!', '?':
+		return nil, ErrUnsupported
+	case '@', '`', ',', ']', '}':
+		return nil, ErrInvalidSyntax
+	}
+
+	if strings.Contains(text, ": ") || strings.HasSuffix(text, ":") {
+		return nil, ErrInvalidSyntax
+	}
+
+	return resolvePlain(text), nil
+}
+
+// resolvePlain resolves plain scalar to null, boolean, integer, float or
+// string value using the YAML core schema.
+func resolvePlain(text string) interface{} {
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(text, "0x"):
+		if value, err := strconv.ParseInt(text[2:], 16, 64); err == nil {
+			return value
+		}
+	case strings.HasPrefix(text, "0o"):
+		if value, err := strconv.ParseInt(text[2:], 8, 64); err == nil {
+			return value
+		}
+	case isNumber(text):
+		if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return value
+		}
+
+		if value, err := strconv.ParseFloat(text, 64); err == nil {
+			return value
+		}
+	}
+
+	return text
+}
+
+// isNumber returns true if plain scalar contains only characters used by
+// decimal integers and floats.
+func isNumber(text string) bool {
+	return strings.Trim(text, "+-.0123456789eE") == "" && strings.ContainsAny(text, "0123456789")
+}
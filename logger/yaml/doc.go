@@ -0,0 +1,21 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yaml implements decoding of the YAML data serialization format used
+// by the logger package for configuration files. It supports a subset of YAML
+// with block mappings, block sequences, plain, single-quoted and double-quoted
+// scalars and comments. Flow collections, block scalars, anchors, aliases,
+// tags and multiple documents are not supported and they are reported as
+// errors.
+package yaml
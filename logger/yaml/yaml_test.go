@@ -0,0 +1,122 @@
+// Copyright 2020 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yaml_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gitlab.com/tymonx/go-logger/logger/yaml"
+)
+
+func TestUnmarshal(test *testing.T) {
+	data := `---
+# Comment
+level: info # trailing comment
+port: 514
+ratio: 0.5
+enabled: true
+empty:
+quoted: "tab\t{message} # not a comment"
+single: 'it''s'
+"quoted key": plain#text # comment
+handlers:
+  file:
+    name: /var/log/app.log
+list:
+- first
+- key: value
+  other: 0x1f
+-
+  - nested
+...
+ignored: true
+`
+
+	want := map[string]interface{}{
+		"level":      "info",
+		"port":       int64(514),
+		"ratio":      0.5,
+		"enabled":    true,
+		"empty":      nil,
+		"quoted":     "tab\t{message} # not a comment",
+		"single":     "it's",
+		"quoted key": "plain#text",
+		"handlers": map[string]interface{}{
+			"file": map[string]interface{}{
+				"name": "/var/log/app.log",
+			},
+		},
+		"list": []interface{}{
+			"first",
+			map[string]interface{}{"key": "value", "other": int64(31)},
+			[]interface{}{"nested"},
+		},
+	}
+
+	var got interface{}
+
+	if err := yaml.Unmarshal([]byte(data), &got); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		test.Errorf("Unmarshal() = %#v; want %#v", got, want)
+	}
+}
+
+func TestUnmarshalStruct(test *testing.T) {
+	var got struct {
+		Name  string   `json:"name"`
+		Port  int      `json:"port"`
+		Items []string `json:"items"`
+	}
+
+	if err := yaml.Unmarshal([]byte("name: logs\nport: 514\nitems:\n  - a\n  - b\n"), &got); err != nil {
+		test.Fatal("Unmarshal() returns an unexpected error", err)
+	}
+
+	if (got.Name != "logs") || (got.Port != 514) || !reflect.DeepEqual(got.Items, []string{"a", "b"}) {
+		test.Errorf("Unmarshal() = %+v; want logs, 514, [a b]", got)
+	}
+}
+
+func TestUnmarshalErrors(test *testing.T) {
+	tests := []struct {
+		data string
+		want error
+	}{
+		{"key: [a, b]", yaml.ErrUnsupported},
+		{"key: {a: b}", yaml.ErrUnsupported},
+		{"key: |\n  text", yaml.ErrUnsupported},
+		{"key: &anchor value", yaml.ErrUnsupported},
+		{"---\na: 1\n---\nb: 2", yaml.ErrUnsupported},
+		{"key: 'value", yaml.ErrUnterminated},
+		{"a: 1\na: 2", yaml.ErrDuplicateKey},
+		{"a: 1\n  b: 2", yaml.ErrInvalidSyntax},
+		{"a: b: c", yaml.ErrInvalidSyntax},
+		{"a: 1\n- b", yaml.ErrInvalidSyntax},
+		{"a:\n\t- b", yaml.ErrInvalidSyntax},
+	}
+
+	for _, tt := range tests {
+		var got interface{}
+
+		if err := yaml.Unmarshal([]byte(tt.data), &got); !errors.Is(err, tt.want) {
+			test.Errorf("Unmarshal(%q) = %v; want %v", tt.data, err, tt.want)
+		}
+	}
+}